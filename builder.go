@@ -0,0 +1,404 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stardict
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ianlewis/go-dictzip"
+
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/idx"
+	"github.com/ianlewis/go-stardict/ifo"
+	"github.com/ianlewis/go-stardict/internal/headword"
+	"github.com/ianlewis/go-stardict/syn"
+)
+
+var (
+	// ErrBuilderClosed indicates that the Builder has already been finished or
+	// closed.
+	ErrBuilderClosed = errors.New("builder closed")
+
+	errInvalidBasename = errors.New("invalid basename")
+)
+
+// tmpExt is the extension of the files written by Builder.Finish until they
+// are renamed into place.
+const tmpExt = ".tmp"
+
+// BuilderOptions are options for a Builder.
+type BuilderOptions struct {
+	// CompressIndex indicates that the index should be written gzip
+	// compressed as a .idx.gz file.
+	CompressIndex bool
+
+	// TempDir is the directory where the dictionary data is stored until
+	// Finish is called. If empty, the default directory for temporary files
+	// is used.
+	TempDir string
+}
+
+// Builder creates a new dictionary. Entries are added with AddEntry and the
+// .ifo, .idx, .syn, and .dict.dz files are written when Finish is called.
+// Dictionary data is stored in a temporary file until then so Close should be
+// called if Finish is not.
+type Builder struct {
+	info             ifo.Info
+	sametypesequence []dict.DataType
+	compressIndex    bool
+
+	tmp  *os.File
+	buf  *bufio.Writer
+	dict *dict.Writer
+
+	words    []*idx.Word
+	synonyms []*syn.Word
+
+	closed bool
+}
+
+// NewBuilder returns a new Builder for a dictionary with the given metadata.
+// The BookName is required. The word counts and file sizes are set by the
+// Builder and idxoffsetbits is set to 64 if needed. If the Version is empty
+// it defaults to "2.4.2". Tree dictionaries are not supported.
+func NewBuilder(info *ifo.Info, options *BuilderOptions) (*Builder, error) {
+	if options == nil {
+		options = &BuilderOptions{}
+	}
+	if info.BookName == "" {
+		return nil, errNoBookname
+	}
+	if info.Magic != "" && info.Magic != ifo.DictMagic {
+		return nil, fmt.Errorf("%w: %q", errInvalidMagic, info.Magic)
+	}
+
+	b := &Builder{
+		info:          *info,
+		compressIndex: options.CompressIndex,
+	}
+	b.info.Magic = ifo.DictMagic
+	if b.info.Version == "" {
+		b.info.Version = "2.4.2"
+	}
+	for _, r := range info.SameTypeSequence {
+		b.sametypesequence = append(b.sametypesequence, dict.DataType(r))
+	}
+
+	tmp, err := os.CreateTemp(options.TempDir, "stardict-*.dict")
+	if err != nil {
+		return nil, fmt.Errorf("creating temporary file: %w", err)
+	}
+	b.tmp = tmp
+	b.buf = bufio.NewWriter(tmp)
+
+	b.dict, err = dict.NewWriter(b.buf, &dict.WriterOptions{
+		SameTypeSequence: b.sametypesequence,
+	})
+	if err != nil {
+		_ = b.Close()
+		return nil, fmt.Errorf("creating dict writer: %w", err)
+	}
+
+	return b, nil
+}
+
+// AddEntry adds an entry for the word to the dictionary. The synonyms are
+// added to the synonym file and refer to the entry. If the dictionary has a
+// sametypesequence the data must match it.
+func (b *Builder) AddEntry(word string, synonyms []string, data ...*dict.Data) error {
+	if b.closed {
+		return ErrBuilderClosed
+	}
+	if !headword.Valid(word) {
+		return fmt.Errorf("%w: %q", idx.ErrInvalidWord, word)
+	}
+	for _, s := range synonyms {
+		if !headword.Valid(s) {
+			return fmt.Errorf("%w: %q", syn.ErrInvalidWord, s)
+		}
+	}
+	if len(b.words) == math.MaxUint32 {
+		return fmt.Errorf("%w: too many entries", idx.ErrInvalidWord)
+	}
+
+	e, err := b.dict.Add(&dict.Word{
+		Data: data,
+	})
+	if err != nil {
+		return fmt.Errorf("adding %q: %w", word, err)
+	}
+	e.Word = word
+
+	//nolint:gosec // The number of words is checked above.
+	n := uint32(len(b.words))
+	b.words = append(b.words, e)
+	for _, s := range synonyms {
+		b.synonyms = append(b.synonyms, &syn.Word{
+			Word:              s,
+			OriginalWordIndex: n,
+		})
+	}
+
+	return nil
+}
+
+// Finish writes the dictionary files to dir. The files are named using
+// basename (e.g. basename.ifo). The .syn file is only written if synonyms
+// were added. The idxoffsetbits is set to 64 if the dictionary data is larger
+// than 4GiB. Existing dictionary files with the same basename are replaced.
+// Other files with the same basename that readers would use instead of, or
+// together with, the new files (e.g. a .dict file, a .syn.gz file, or
+// collation and offset cache files) are removed after the new files are in
+// place. The Builder is closed after Finish returns.
+func (b *Builder) Finish(dir, basename string) (retErr error) {
+	if b.closed {
+		return ErrBuilderClosed
+	}
+	defer func() {
+		if err := b.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+
+	if basename == "" || strings.ContainsAny(basename, `/\`) {
+		return fmt.Errorf("%w: %q", errInvalidBasename, basename)
+	}
+	base := filepath.Join(dir, basename)
+
+	// Files are written to temporary files and renamed into place after all
+	// files have been written. The temporary files are removed if an error
+	// occurs.
+	var written []string
+	defer func() {
+		if retErr != nil {
+			for _, path := range written {
+				_ = os.Remove(path + tmpExt)
+			}
+		}
+	}()
+	create := func(path string, write func(io.Writer) error) error {
+		written = append(written, path)
+		return writeFile(path+tmpExt, write)
+	}
+
+	if err := b.dict.Close(); err != nil {
+		return fmt.Errorf("closing dict writer: %w", err)
+	}
+	if err := b.buf.Flush(); err != nil {
+		return fmt.Errorf("writing dict: %w", err)
+	}
+	if _, err := b.tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("reading dict: %w", err)
+	}
+
+	info := b.info
+	bits := max(offsetBits(b.dict.Size()), info.IdxOffsetBits)
+	if bits == 64 {
+		// idxoffsetbits is only supported in version 3.0.0.
+		info.IdxOffsetBits = 64
+		info.Version = "3.0.0"
+	}
+
+	if err := create(base+".dict.dz", func(w io.Writer) error {
+		dz, err := dictzip.NewWriter(w)
+		if err != nil {
+			return fmt.Errorf("creating dictzip writer: %w", err)
+		}
+		if _, err = io.Copy(dz, b.tmp); err != nil {
+			return fmt.Errorf("writing dict: %w", err)
+		}
+		if err = dz.Close(); err != nil {
+			return fmt.Errorf("closing dictzip writer: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	idxExt := ".idx"
+	if b.compressIndex {
+		idxExt = ".idx.gz"
+	}
+	var iw *idx.Writer
+	if err := create(base+idxExt, func(w io.Writer) error {
+		var z *gzip.Writer
+		if b.compressIndex {
+			z = gzip.NewWriter(w)
+			w = z
+		}
+
+		var err error
+		iw, err = idx.NewWriter(w, &idx.WriterOptions{
+			OffsetBits: bits,
+		})
+		if err != nil {
+			return fmt.Errorf("creating index writer: %w", err)
+		}
+		for _, word := range b.words {
+			if err = iw.Add(word); err != nil {
+				return fmt.Errorf("adding %q to index: %w", word.Word, err)
+			}
+		}
+		if err = iw.Flush(); err != nil {
+			return fmt.Errorf("writing index: %w", err)
+		}
+
+		if z != nil {
+			if err = z.Close(); err != nil {
+				return fmt.Errorf("closing gzip writer: %w", err)
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	info.WordCount = iw.WordCount()
+	info.IdxFileSize = iw.FileSize()
+
+	info.SynWordCount = 0
+	if len(b.synonyms) > 0 {
+		if err := create(base+".syn", func(w io.Writer) error {
+			sw := syn.NewWriter(w)
+			for _, word := range b.synonyms {
+				if err := sw.Add(word); err != nil {
+					return fmt.Errorf("adding %q to synonyms: %w", word.Word, err)
+				}
+			}
+			if err := sw.Flush(iw.Order()); err != nil {
+				return fmt.Errorf("writing synonyms: %w", err)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		info.SynWordCount = int64(len(b.synonyms))
+	}
+
+	if err := create(base+".ifo", func(w io.Writer) error {
+		//nolint:wrapcheck // ifo.Write errors are descriptive.
+		return ifo.Write(w, ifo.FromInfo(&info))
+	}); err != nil {
+		return err
+	}
+
+	// The .ifo file is renamed last so that the dictionary can't be opened
+	// until all other files are in place.
+	var files []os.FileInfo
+	for _, path := range written {
+		if err := os.Rename(path+tmpExt, path); err != nil {
+			return fmt.Errorf("renaming %q: %w", path+tmpExt, err)
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("reading %q: %w", path, err)
+		}
+		files = append(files, fi)
+	}
+
+	return removeStale(base, files)
+}
+
+// staleExts returns the extensions of files from a previous dictionary that
+// readers would use instead of, or together with, the files written by
+// Finish. For example, a .dict file is preferred over a .dict.dz file and
+// collation and offset cache files depend on the .idx and .syn files.
+func staleExts() []string {
+	exts := dict.Extensions()
+	for _, ext := range idx.Extensions() {
+		exts = append(exts, ext, ext+".oft")
+	}
+	exts = append(exts, syn.Extensions()...)
+	return append(exts, ".idx.clt", ".syn.clt")
+}
+
+// removeStale removes the files with the given base name and the extensions
+// returned by staleExts except for the files that were written. Files are
+// compared with os.SameFile so that files written with a different case on
+// case-insensitive file systems are not removed.
+func removeStale(base string, written []os.FileInfo) error {
+	for _, ext := range staleExts() {
+		path := base + ext
+		fi, err := os.Lstat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("removing %q: %w", path, err)
+		}
+		if slices.ContainsFunc(written, func(w os.FileInfo) bool {
+			return os.SameFile(fi, w)
+		}) {
+			continue
+		}
+		if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("removing %q: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Close discards the dictionary and removes temporary files. It is not
+// necessary to call Close after Finish.
+func (b *Builder) Close() error {
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+
+	name := b.tmp.Name()
+	if err := b.tmp.Close(); err != nil {
+		_ = os.Remove(name)
+		return fmt.Errorf("closing temporary file: %w", err)
+	}
+	if err := os.Remove(name); err != nil {
+		return fmt.Errorf("removing temporary file: %w", err)
+	}
+	return nil
+}
+
+// offsetBits returns the idxoffsetbits needed for a .dict file of the given
+// size.
+func offsetBits(dictSize int64) int {
+	if dictSize > math.MaxUint32 {
+		return 64
+	}
+	return 32
+}
+
+// writeFile creates the file at path and writes it using write.
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating %q: %w", path, err)
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing %q: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing %q: %w", path, err)
+	}
+	return nil
+}
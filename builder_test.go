@@ -0,0 +1,335 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stardict
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/idx"
+	"github.com/ianlewis/go-stardict/ifo"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Parallel()
+
+	type entry struct {
+		word     string
+		synonyms []string
+		data     []*dict.Data
+	}
+
+	tests := []struct {
+		name    string
+		info    *ifo.Info
+		options *BuilderOptions
+		entries []entry
+
+		query    string
+		expected []*Entry
+	}{
+		{
+			name: "typed",
+			info: &ifo.Info{
+				BookName: "hoge",
+				Author:   "Ian Lewis",
+			},
+			entries: []entry{
+				{
+					word:     "hoge",
+					synonyms: []string{"piyo"},
+					data: []*dict.Data{
+						{Type: dict.UTFTextType, Data: []byte("hoge data")},
+					},
+				},
+				{
+					word: "fuga",
+					data: []*dict.Data{
+						{Type: dict.PhoneticType, Data: []byte("fu:ga")},
+						{Type: dict.UTFTextType, Data: []byte("fuga data")},
+					},
+				},
+			},
+			query: "piyo",
+			expected: []*Entry{
+				{
+					word: "hoge",
+					data: []*dict.Data{
+						{Type: dict.UTFTextType, Data: []byte("hoge data")},
+					},
+				},
+			},
+		},
+		{
+			name: "sametypesequence",
+			info: &ifo.Info{
+				BookName:         "hoge",
+				SameTypeSequence: "tm",
+			},
+			entries: []entry{
+				{
+					word: "hoge",
+					data: []*dict.Data{
+						{Type: dict.PhoneticType, Data: []byte("ho:ge")},
+						{Type: dict.UTFTextType, Data: []byte("hoge data")},
+					},
+				},
+				{
+					word:     "fuga",
+					synonyms: []string{"foo", "bar"},
+					data: []*dict.Data{
+						{Type: dict.PhoneticType, Data: []byte("fu:ga")},
+						{Type: dict.UTFTextType, Data: []byte("fuga data")},
+					},
+				},
+			},
+			query: "fuga",
+			expected: []*Entry{
+				{
+					word: "fuga",
+					data: []*dict.Data{
+						{Type: dict.PhoneticType, Data: []byte("fu:ga")},
+						{Type: dict.UTFTextType, Data: []byte("fuga data")},
+					},
+				},
+			},
+		},
+		{
+			name: "64-bit offsets",
+			info: &ifo.Info{
+				BookName:      "hoge",
+				IdxOffsetBits: 64,
+			},
+			entries: []entry{
+				{
+					word: "hoge",
+					data: []*dict.Data{
+						{Type: dict.UTFTextType, Data: []byte("hoge data")},
+					},
+				},
+			},
+			query: "hoge",
+			expected: []*Entry{
+				{
+					word: "hoge",
+					data: []*dict.Data{
+						{Type: dict.UTFTextType, Data: []byte("hoge data")},
+					},
+				},
+			},
+		},
+		{
+			name: "compressed index",
+			info: &ifo.Info{
+				BookName: "hoge",
+			},
+			options: &BuilderOptions{
+				CompressIndex: true,
+			},
+			entries: []entry{
+				{
+					word: "hoge",
+					data: []*dict.Data{
+						{Type: dict.UTFTextType, Data: []byte("hoge data")},
+					},
+				},
+			},
+			query: "hoge",
+			expected: []*Entry{
+				{
+					word: "hoge",
+					data: []*dict.Data{
+						{Type: dict.UTFTextType, Data: []byte("hoge data")},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := NewBuilder(test.info, test.options)
+			if err != nil {
+				t.Fatalf("NewBuilder: %v", err)
+			}
+			var synCount int64
+			for _, e := range test.entries {
+				if err := b.AddEntry(e.word, e.synonyms, e.data...); err != nil {
+					t.Fatalf("AddEntry: %v", err)
+				}
+				synCount += int64(len(e.synonyms))
+			}
+
+			dir := t.TempDir()
+			if err := b.Finish(dir, "dictionary"); err != nil {
+				t.Fatalf("Finish: %v", err)
+			}
+
+			s, err := Open(filepath.Join(dir, "dictionary.ifo"), nil)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			defer s.Close()
+
+			if got, want := s.Bookname(), test.info.BookName; got != want {
+				t.Errorf("Bookname: expected %q, got %q", want, got)
+			}
+			if got, want := s.WordCount(), int64(len(test.entries)); got != want {
+				t.Errorf("WordCount: expected %d, got %d", want, got)
+			}
+			if got, want := s.SynWordCount(), synCount; got != want {
+				t.Errorf("SynWordCount: expected %d, got %d", want, got)
+			}
+
+			entries, err := s.Search(test.query)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if diff := cmp.Diff(test.expected, entries, cmp.AllowUnexported(Entry{})); diff != "" {
+				t.Errorf("Search (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBuilder_AddEntry_invalid(t *testing.T) {
+	t.Parallel()
+
+	b, err := NewBuilder(&ifo.Info{
+		BookName: "hoge",
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+	defer b.Close()
+
+	if err := b.AddEntry("", nil); !errors.Is(err, idx.ErrInvalidWord) {
+		t.Errorf("AddEntry: expected %v, got %v", idx.ErrInvalidWord, err)
+	}
+	if err := b.AddEntry("hoge", []string{"a\x00b"}); err == nil {
+		t.Error("AddEntry: expected error")
+	}
+	if err := b.AddEntry("hoge", nil, &dict.Data{Type: dict.DataType('!')}); err == nil {
+		t.Error("AddEntry: expected error")
+	}
+}
+
+func TestBuilder_Finish_existing(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{
+		"dictionary.ifo",
+		"dictionary.dict",
+		"dictionary.dict.dz",
+		"dictionary.idx.gz",
+		"dictionary.IDX",
+		"dictionary.idx.oft",
+		"dictionary.idx.clt",
+		"dictionary.syn",
+		"dictionary.syn.gz",
+		"dictionary.syn.clt",
+		"dictionary.DICT.DZ",
+		"other.dict",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("stale"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b, err := NewBuilder(&ifo.Info{
+		BookName: "hoge",
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+	if err := b.AddEntry("hoge", nil, &dict.Data{Type: dict.UTFTextType, Data: []byte("hoge data")}); err != nil {
+		t.Fatalf("AddEntry: %v", err)
+	}
+	if err := b.Finish(dir, "dictionary"); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	expected := []string{"dictionary.dict.dz", "dictionary.idx", "dictionary.ifo", "other.dict"}
+	if diff := cmp.Diff(expected, names); diff != "" {
+		t.Errorf("files (-want, +got):\n%s", diff)
+	}
+
+	s, err := Open(filepath.Join(dir, "dictionary.ifo"), nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+
+	words, err := s.Search("hoge")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(words) != 1 || len(words[0].Data()) != 1 || string(words[0].Data()[0].Data) != "hoge data" {
+		t.Errorf("Search: unexpected result: %v", words)
+	}
+}
+
+func TestBuilder_closed(t *testing.T) {
+	t.Parallel()
+
+	b, err := NewBuilder(&ifo.Info{
+		BookName: "hoge",
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+	tmpName := b.tmp.Name()
+
+	if err := b.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := os.Stat(tmpName); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("temporary file: expected %v, got %v", os.ErrNotExist, err)
+	}
+	if err := b.AddEntry("hoge", nil); !errors.Is(err, ErrBuilderClosed) {
+		t.Errorf("AddEntry: expected %v, got %v", ErrBuilderClosed, err)
+	}
+	if err := b.Finish(t.TempDir(), "hoge"); !errors.Is(err, ErrBuilderClosed) {
+		t.Errorf("Finish: expected %v, got %v", ErrBuilderClosed, err)
+	}
+}
+
+func TestOffsetBits(t *testing.T) {
+	t.Parallel()
+
+	if got, want := offsetBits(math.MaxUint32), 32; got != want {
+		t.Errorf("offsetBits(%d): expected %d, got %d", int64(math.MaxUint32), want, got)
+	}
+	if got, want := offsetBits(math.MaxUint32+1), 64; got != want {
+		t.Errorf("offsetBits(%d): expected %d, got %d", int64(math.MaxUint32+1), want, got)
+	}
+}
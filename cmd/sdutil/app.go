@@ -46,6 +46,9 @@ var ErrFlagParse = fmt.Errorf("%w: parsing flags", ErrSdutil)
 // ErrUnsupported indicates a feature is unsupported.
 var ErrUnsupported = fmt.Errorf("%w: unsupported", ErrSdutil)
 
+// ErrInvalidDictionary indicates that a dictionary failed validation.
+var ErrInvalidDictionary = fmt.Errorf("%w: invalid dictionary", ErrSdutil)
+
 var copyrightNames = []string{
 	"2021 Google LLC",
 	"2024 Ian Lewis",
@@ -76,12 +79,22 @@ func check(err error) {
 	}
 }
 
-func openStardicts(dirs []string) ([]*stardict.Stardict, []error) {
+// commandError wraps the error in ErrSdutil, prints it to the app's error
+// writer, and returns it.
+func commandError(c *cli.Context, err error) error {
+	if !errors.Is(err, ErrSdutil) {
+		err = fmt.Errorf("%w: %w", ErrSdutil, err)
+	}
+	fmt.Fprintln(c.App.ErrWriter, err)
+	return err
+}
+
+func openStardicts(dirs []string, options *stardict.Options) ([]*stardict.Stardict, []error) {
 	var dicts []*stardict.Stardict
 	var errs []error
 
 	for _, path := range dirs {
-		openDicts, openErrs := stardict.OpenAll(path, nil)
+		openDicts, openErrs := stardict.OpenAll(path, options)
 
 		dicts = append(dicts, openDicts...)
 		errs = append(errs, openErrs...)
@@ -132,8 +145,15 @@ func newStardictApp() *cli.App {
 			return nil
 		},
 		Commands: []*cli.Command{
+			buildCommand,
+			convertCommand,
+			exportCommand,
+			filterCommand,
 			listCommand,
+			mergeCommand,
 			queryCommand,
+			repairCommand,
+			validateCommand,
 		},
 	}
 }
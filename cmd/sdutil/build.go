@@ -0,0 +1,162 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/go-stardict"
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/ifo"
+	"github.com/ianlewis/go-stardict/tabfile"
+)
+
+var buildCommand = &cli.Command{
+	Name:            "build",
+	Usage:           "Build a dictionary from a tabfile",
+	ArgsUsage:       "FILE",
+	HideHelp:        true,
+	HideHelpCommand: true,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "output",
+			Usage:   "write the dictionary to `DIR` (default: the directory of FILE)",
+			Aliases: []string{"o"},
+		},
+		&cli.StringFlag{
+			Name:    "type",
+			Usage:   "the data `TYPE` of definitions (e.g. 'm' for text or 'h' for HTML)",
+			Aliases: []string{"t"},
+			Value:   string(dict.UTFTextType),
+		},
+		&cli.StringFlag{
+			Name:  "bookname",
+			Usage: "the dictionary `NAME` (default: the base name of FILE)",
+		},
+		&cli.StringFlag{
+			Name:  "author",
+			Usage: "the dictionary `AUTHOR`",
+		},
+		&cli.StringFlag{
+			Name:  "email",
+			Usage: "the author's `EMAIL`",
+		},
+		&cli.StringFlag{
+			Name:  "website",
+			Usage: "the dictionary `URL`",
+		},
+		&cli.StringFlag{
+			Name:  "description",
+			Usage: "the dictionary `DESCRIPTION`",
+		},
+		&cli.StringFlag{
+			Name:  "lang",
+			Usage: "the dictionary `LANG` (e.g. en-ja)",
+		},
+		&cli.BoolFlag{
+			Name:               "compress-index",
+			Usage:              "compress the index (.idx.gz)",
+			DisableDefaultText: true,
+		},
+
+		// Special flags are shown at the end.
+		&cli.BoolFlag{
+			Name:               "help",
+			Usage:              "print this help text and exit",
+			Aliases:            []string{"h"},
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "version",
+			Usage:              "print version information and exit",
+			Aliases:            []string{"V"},
+			DisableDefaultText: true,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.Bool("help") {
+			check(cli.ShowCommandHelp(c, c.Command.Name))
+			return nil
+		}
+		if c.Bool("version") {
+			return printVersion(c)
+		}
+
+		if c.NArg() != 1 {
+			return commandError(c, fmt.Errorf("%w: expected one FILE argument", ErrFlagParse))
+		}
+		path := c.Args().First()
+
+		t := c.String("type")
+		if utf8.RuneCountInString(t) != 1 {
+			return commandError(c, fmt.Errorf("%w: invalid type %q", ErrFlagParse, t))
+		}
+
+		basename := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		outDir := c.String("output")
+		if outDir == "" {
+			outDir = filepath.Dir(path)
+		}
+		bookname := c.String("bookname")
+		if bookname == "" {
+			bookname = basename
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return commandError(c, err)
+		}
+		defer f.Close()
+
+		b, err := stardict.NewBuilder(&ifo.Info{
+			BookName:         bookname,
+			Author:           c.String("author"),
+			Email:            c.String("email"),
+			Website:          c.String("website"),
+			Description:      c.String("description"),
+			Lang:             c.String("lang"),
+			SameTypeSequence: t,
+		}, &stardict.BuilderOptions{
+			CompressIndex: c.Bool("compress-index"),
+		})
+		if err != nil {
+			return commandError(c, err)
+		}
+		defer b.Close()
+
+		n, err := tabfile.Import(f, b, &tabfile.ImportOptions{
+			DataType: dict.DataType([]rune(t)[0]),
+		})
+		if err != nil {
+			return commandError(c, fmt.Errorf("%s: %w", path, err))
+		}
+		if err := b.Finish(outDir, basename); err != nil {
+			return commandError(c, err)
+		}
+
+		_, err = fmt.Fprintf(c.App.Writer, "wrote %d entries to %s\n",
+			n, filepath.Join(outDir, basename+".ifo"))
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrSdutil, err)
+		}
+		return nil
+	},
+}
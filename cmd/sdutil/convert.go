@@ -0,0 +1,324 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/go-stardict"
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/dictd"
+	"github.com/ianlewis/go-stardict/dsl"
+	"github.com/ianlewis/go-stardict/ifo"
+	"github.com/ianlewis/go-stardict/internal/gzipfile"
+)
+
+// Dictionary formats supported by the convert command.
+const (
+	formatStardict = "stardict"
+	formatDSL      = "dsl"
+	formatDictd    = "dictd"
+)
+
+var convertCommand = &cli.Command{
+	Name:  "convert",
+	Usage: "Convert between dictionary formats",
+	Description: strings.Join([]string{
+		"Converts the dictionary SRC to DEST. The formats are determined by the file extensions:",
+		"  .ifo           StarDict",
+		"  .dsl, .dsl.dz  ABBYY Lingvo DSL",
+		"  .index         dictd (with a .dict.dz or .dict data file)",
+	}, "\n"),
+	ArgsUsage:       "SRC DEST",
+	HideHelp:        true,
+	HideHelpCommand: true,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "type",
+			Usage:   "the data `TYPE` of StarDict entries (default: 'h' for DSL and 'm' for dictd)",
+			Aliases: []string{"t"},
+		},
+		&cli.StringFlag{
+			Name:  "bookname",
+			Usage: "the StarDict dictionary `NAME` (default: the source dictionary name)",
+		},
+		&cli.BoolFlag{
+			Name:               "compress-index",
+			Usage:              "compress the StarDict index (.idx.gz)",
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "utf8",
+			Usage:              "write DSL files in UTF-8 rather than UTF-16",
+			DisableDefaultText: true,
+		},
+
+		// Special flags are shown at the end.
+		&cli.BoolFlag{
+			Name:               "help",
+			Usage:              "print this help text and exit",
+			Aliases:            []string{"h"},
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "version",
+			Usage:              "print version information and exit",
+			Aliases:            []string{"V"},
+			DisableDefaultText: true,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.Bool("help") {
+			check(cli.ShowCommandHelp(c, c.Command.Name))
+			return nil
+		}
+		if c.Bool("version") {
+			return printVersion(c)
+		}
+
+		if c.NArg() != 2 {
+			return commandError(c, fmt.Errorf("%w: expected SRC and DEST arguments", ErrFlagParse))
+		}
+		src, dst := c.Args().Get(0), c.Args().Get(1)
+
+		var err error
+		switch srcFormat, dstFormat := dictFormat(src), dictFormat(dst); {
+		case srcFormat == formatDSL && dstFormat == formatStardict:
+			err = convertDSLToStardict(c, src, dst)
+		case srcFormat == formatStardict && dstFormat == formatDSL:
+			err = convertStardictToDSL(c, src, dst)
+		case srcFormat == formatDictd && dstFormat == formatStardict:
+			err = convertDictdToStardict(c, src, dst)
+		case srcFormat == formatStardict && dstFormat == formatDictd:
+			err = convertStardictToDictd(c, src, dst)
+		default:
+			err = fmt.Errorf("%w: conversion from %q to %q", ErrUnsupported, src, dst)
+		}
+		if err != nil {
+			return commandError(c, err)
+		}
+		return nil
+	},
+}
+
+// dictFormat returns the dictionary format of the path based on its
+// extension.
+func dictFormat(path string) string {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".ifo"):
+		return formatStardict
+	case strings.HasSuffix(lower, ".dsl"), strings.HasSuffix(lower, ".dsl.dz"):
+		return formatDSL
+	case strings.HasSuffix(lower, ".index"):
+		return formatDictd
+	default:
+		return ""
+	}
+}
+
+// openInput opens the file at path. Files with a .dz extension are read as
+// dictzip files and files with a .gz extension are decompressed.
+func openInput(path string) (io.ReadCloser, error) {
+	r, err := gzipfile.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening %q: %w", path, err)
+	}
+	return r, nil
+}
+
+// newBuilder returns a new Builder for the converted dictionary and the
+// data type of its entries. The data type defaults to defaultType.
+func newBuilder(c *cli.Context, info *ifo.Info, defaultType dict.DataType) (*stardict.Builder, dict.DataType, error) {
+	t := c.String("type")
+	if t == "" {
+		t = string(defaultType)
+	}
+	if utf8.RuneCountInString(t) != 1 {
+		return nil, 0, fmt.Errorf("%w: invalid type %q", ErrFlagParse, t)
+	}
+	if name := c.String("bookname"); name != "" {
+		info.BookName = name
+	}
+	info.SameTypeSequence = t
+
+	b, err := stardict.NewBuilder(info, &stardict.BuilderOptions{
+		CompressIndex: c.Bool("compress-index"),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("creating dictionary: %w", err)
+	}
+	return b, dict.DataType([]rune(t)[0]), nil
+}
+
+// convertDSLToStardict converts the DSL file src to a StarDict dictionary
+// with the .ifo file dst.
+func convertDSLToStardict(c *cli.Context, src, dst string) error {
+	r, err := openInput(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	s, err := dsl.NewScanner(r)
+	if err != nil {
+		return fmt.Errorf("reading %q: %w", src, err)
+	}
+
+	info := s.Header().Info()
+	if info.BookName == "" {
+		info.BookName = strings.TrimSuffix(filepath.Base(dst), filepath.Ext(dst))
+	}
+	b, t, err := newBuilder(c, info, dict.HTMLType)
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	n, err := dsl.Import(s, b, &dsl.ImportOptions{
+		DataType: t,
+	})
+	if err != nil {
+		return fmt.Errorf("reading %q: %w", src, err)
+	}
+
+	return finish(c, b, n, dst)
+}
+
+// finish writes the dictionary built by b to the .ifo path dst.
+func finish(c *cli.Context, b *stardict.Builder, n int, dst string) error {
+	dir, base := filepath.Split(dst)
+	if dir == "" {
+		dir = "."
+	}
+	if err := b.Finish(dir, strings.TrimSuffix(base, filepath.Ext(base))); err != nil {
+		return fmt.Errorf("writing %q: %w", dst, err)
+	}
+
+	if _, err := fmt.Fprintf(c.App.Writer, "wrote %d entries to %s\n", n, dst); err != nil {
+		return fmt.Errorf("%w: %w", ErrSdutil, err)
+	}
+	return nil
+}
+
+// convertStardictToDSL converts the StarDict dictionary with the .ifo file
+// src to the DSL file dst.
+func convertStardictToDSL(c *cli.Context, src, dst string) error {
+	s, err := stardict.Open(src, nil)
+	if err != nil {
+		return fmt.Errorf("opening %q: %w", src, err)
+	}
+	defer s.Close()
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("creating %q: %w", dst, err)
+	}
+	defer f.Close()
+
+	n, err := dsl.Export(s, f, &dsl.WriterOptions{
+		UTF8: c.Bool("utf8"),
+	})
+	if err != nil {
+		return fmt.Errorf("writing %q: %w", dst, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing %q: %w", dst, err)
+	}
+
+	if _, err := fmt.Fprintf(c.App.Writer, "wrote %d cards to %s\n", n, dst); err != nil {
+		return fmt.Errorf("%w: %w", ErrSdutil, err)
+	}
+	return nil
+}
+
+// convertDictdToStardict converts the dictd database with the .index file src
+// to a StarDict dictionary with the .ifo file dst.
+func convertDictdToStardict(c *cli.Context, src, dst string) error {
+	db, err := dictd.Open(src)
+	if err != nil {
+		return fmt.Errorf("opening %q: %w", src, err)
+	}
+	defer db.Close()
+
+	info, err := db.Info()
+	if err != nil {
+		return fmt.Errorf("reading %q: %w", src, err)
+	}
+	if info.BookName == "" {
+		info.BookName = strings.TrimSuffix(filepath.Base(dst), filepath.Ext(dst))
+	}
+	b, t, err := newBuilder(c, info, dict.UTFTextType)
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	n, err := dictd.Import(db, b, &dictd.ImportOptions{
+		DataType: t,
+	})
+	if err != nil {
+		return fmt.Errorf("reading %q: %w", src, err)
+	}
+
+	return finish(c, b, n, dst)
+}
+
+// convertStardictToDictd converts the StarDict dictionary with the .ifo file
+// src to a dictd database with the .index file dst. The data file is written
+// to a .dict.dz file with the same base name.
+func convertStardictToDictd(c *cli.Context, src, dst string) error {
+	s, err := stardict.Open(src, nil)
+	if err != nil {
+		return fmt.Errorf("opening %q: %w", src, err)
+	}
+	defer s.Close()
+
+	dataPath := strings.TrimSuffix(dst, filepath.Ext(dst)) + ".dict.dz"
+	index, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("creating %q: %w", dst, err)
+	}
+	defer index.Close()
+	data, err := os.Create(dataPath)
+	if err != nil {
+		return fmt.Errorf("creating %q: %w", dataPath, err)
+	}
+	defer data.Close()
+
+	n, err := dictd.Export(s, index, data, &dictd.WriterOptions{
+		DictZip: true,
+	})
+	if err != nil {
+		return fmt.Errorf("writing %q: %w", dst, err)
+	}
+	for _, f := range []*os.File{index, data} {
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("writing %q: %w", f.Name(), err)
+		}
+	}
+
+	if _, err := fmt.Fprintf(c.App.Writer, "wrote %d entries to %s\n", n, dst); err != nil {
+		return fmt.Errorf("%w: %w", ErrSdutil, err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/go-stardict"
+	"github.com/ianlewis/go-stardict/export"
+)
+
+var exportCommand = &cli.Command{
+	Name:            "export",
+	Usage:           "Export a dictionary to JSON Lines or CSV",
+	ArgsUsage:       "IFO_FILE",
+	HideHelp:        true,
+	HideHelpCommand: true,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "format",
+			Usage:   "the export `FORMAT` (jsonl or csv)",
+			Aliases: []string{"f"},
+			Value:   string(export.JSONL),
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Usage:   "write the export to `FILE` (default: stdout)",
+			Aliases: []string{"o"},
+		},
+
+		// Special flags are shown at the end.
+		&cli.BoolFlag{
+			Name:               "help",
+			Usage:              "print this help text and exit",
+			Aliases:            []string{"h"},
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "version",
+			Usage:              "print version information and exit",
+			Aliases:            []string{"V"},
+			DisableDefaultText: true,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.Bool("help") {
+			check(cli.ShowCommandHelp(c, c.Command.Name))
+			return nil
+		}
+		if c.Bool("version") {
+			return printVersion(c)
+		}
+
+		if c.NArg() != 1 {
+			return commandError(c, fmt.Errorf("%w: expected one IFO_FILE argument", ErrFlagParse))
+		}
+
+		s, err := stardict.Open(c.Args().First(), nil)
+		if err != nil {
+			return commandError(c, err)
+		}
+		defer s.Close()
+
+		// The output is set after the format is validated so that the output
+		// file is not created for an invalid format.
+		bw := bufio.NewWriter(nil)
+		w, err := export.NewWriter(bw, export.Format(c.String("format")))
+		if err != nil {
+			return commandError(c, fmt.Errorf("%w: %w", ErrFlagParse, err))
+		}
+
+		var out io.Writer = c.App.Writer
+		var f *os.File
+		if path := c.String("output"); path != "" {
+			f, err = os.Create(path)
+			if err != nil {
+				return commandError(c, err)
+			}
+			defer f.Close()
+			out = f
+		}
+		bw.Reset(out)
+
+		if _, err := export.Export(s, w); err != nil {
+			return commandError(c, err)
+		}
+		if err := bw.Flush(); err != nil {
+			return commandError(c, err)
+		}
+		if f != nil {
+			if err := f.Close(); err != nil {
+				return commandError(c, err)
+			}
+		}
+
+		return nil
+	},
+}
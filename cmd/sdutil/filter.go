@@ -0,0 +1,205 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/go-stardict"
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/idx"
+)
+
+var filterCommand = &cli.Command{
+	Name:            "filter",
+	Usage:           "Write a dictionary with a subset of entries",
+	ArgsUsage:       "IFO_FILE",
+	HideHelp:        true,
+	HideHelpCommand: true,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "output",
+			Usage:   "write the filtered dictionary to the .ifo `FILE`",
+			Aliases: []string{"o"},
+		},
+		&cli.StringFlag{
+			Name:  "words",
+			Usage: "keep only headwords listed in `FILE` (one per line, text after a tab is ignored)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "glob",
+			Usage: "keep only headwords matching `PATTERN` (may be repeated)",
+		},
+		&cli.BoolFlag{
+			Name:               "ignore-case",
+			Usage:              "match headwords case-insensitively",
+			Aliases:            []string{"i"},
+			DisableDefaultText: true,
+		},
+		&cli.StringFlag{
+			Name:  "bookname",
+			Usage: "the dictionary `NAME` (default: the source dictionary name)",
+		},
+		&cli.BoolFlag{
+			Name:               "compress-index",
+			Usage:              "compress the index (.idx.gz)",
+			DisableDefaultText: true,
+		},
+
+		// Special flags are shown at the end.
+		&cli.BoolFlag{
+			Name:               "help",
+			Usage:              "print this help text and exit",
+			Aliases:            []string{"h"},
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "version",
+			Usage:              "print version information and exit",
+			Aliases:            []string{"V"},
+			DisableDefaultText: true,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.Bool("help") {
+			check(cli.ShowCommandHelp(c, c.Command.Name))
+			return nil
+		}
+		if c.Bool("version") {
+			return printVersion(c)
+		}
+
+		if c.NArg() != 1 {
+			return commandError(c, fmt.Errorf("%w: expected one IFO_FILE argument", ErrFlagParse))
+		}
+		src := c.Args().First()
+		output := c.String("output")
+		if output == "" {
+			return commandError(c, fmt.Errorf("%w: --output is required", ErrFlagParse))
+		}
+		if !strings.EqualFold(filepath.Ext(output), ".ifo") {
+			return commandError(c, fmt.Errorf("%w: output must be an .ifo file: %q", ErrFlagParse, output))
+		}
+
+		keep, err := newFilter(c)
+		if err != nil {
+			return commandError(c, err)
+		}
+
+		s, err := stardict.Open(src, nil)
+		if err != nil {
+			return commandError(c, fmt.Errorf("opening %q: %w", src, err))
+		}
+		defer s.Close()
+
+		info, err := s.Info()
+		if err != nil {
+			return commandError(c, err)
+		}
+		if name := c.String("bookname"); name != "" {
+			info.BookName = name
+		}
+		b, err := stardict.NewBuilder(info, &stardict.BuilderOptions{
+			CompressIndex: c.Bool("compress-index"),
+		})
+		if err != nil {
+			return commandError(c, err)
+		}
+		defer b.Close()
+
+		n, err := stardict.Filter(s, b, keep)
+		if err != nil {
+			return commandError(c, err)
+		}
+
+		if err := finish(c, b, n, output); err != nil {
+			return commandError(c, err)
+		}
+		return nil
+	},
+}
+
+// newFilter returns a filter that keeps headwords matching all of the
+// --words and --glob flags.
+func newFilter(c *cli.Context) (stardict.FilterFunc, error) {
+	fold := func(s string) string { return s }
+	if c.Bool("ignore-case") {
+		fold = strings.ToLower
+	}
+
+	var words map[string]bool
+	if wordsPath := c.String("words"); wordsPath != "" {
+		var err error
+		words, err = readWords(wordsPath, fold)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var globs []string
+	for _, g := range c.StringSlice("glob") {
+		g = fold(g)
+		if _, err := path.Match(g, ""); err != nil {
+			return nil, fmt.Errorf("%w: invalid glob %q: %w", ErrFlagParse, g, err)
+		}
+		globs = append(globs, g)
+	}
+
+	return func(idxWord *idx.Word, _ *dict.Word) bool {
+		w := fold(idxWord.Word)
+		if words != nil && !words[w] {
+			return false
+		}
+		if len(globs) == 0 {
+			return true
+		}
+		for _, g := range globs {
+			if ok, _ := path.Match(g, w); ok {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// readWords reads the headwords in the word list file. Each line contains a
+// headword optionally followed by a tab and other text, such as a frequency.
+func readWords(wordsPath string, fold func(string) string) (map[string]bool, error) {
+	f, err := os.Open(wordsPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening %q: %w", wordsPath, err)
+	}
+	defer f.Close()
+
+	words := map[string]bool{}
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		w, _, _ := strings.Cut(s.Text(), "\t")
+		if w = strings.TrimSpace(w); w != "" {
+			words[fold(w)] = true
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("reading %q: %w", wordsPath, err)
+	}
+	return words, nil
+}
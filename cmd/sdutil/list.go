@@ -53,7 +53,7 @@ var listCommand = &cli.Command{
 			return printVersion(c)
 		}
 
-		dicts, errs := openStardicts(c.StringSlice("data-dir"))
+		dicts, errs := openStardicts(c.StringSlice("data-dir"), nil)
 		for _, err := range errs {
 			// Ignore errors where data dir doesn't exist.
 			if !errors.Is(err, fs.ErrNotExist) {
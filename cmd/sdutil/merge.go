@@ -0,0 +1,139 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/go-stardict"
+)
+
+var mergeCommand = &cli.Command{
+	Name:            "merge",
+	Usage:           "Merge dictionaries into one dictionary",
+	ArgsUsage:       "IFO_FILE...",
+	HideHelp:        true,
+	HideHelpCommand: true,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "output",
+			Usage:   "write the merged dictionary to the .ifo `FILE`",
+			Aliases: []string{"o"},
+		},
+		&cli.StringFlag{
+			Name:    "policy",
+			Usage:   "how to combine entries with the same headword (concat, first, last)",
+			Aliases: []string{"p"},
+			Value:   string(stardict.MergeConcat),
+		},
+		&cli.StringFlag{
+			Name:  "bookname",
+			Usage: "the dictionary `NAME` (default: the dictionary names joined with commas)",
+		},
+		&cli.BoolFlag{
+			Name:               "compress-index",
+			Usage:              "compress the index (.idx.gz)",
+			DisableDefaultText: true,
+		},
+
+		// Special flags are shown at the end.
+		&cli.BoolFlag{
+			Name:               "help",
+			Usage:              "print this help text and exit",
+			Aliases:            []string{"h"},
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "version",
+			Usage:              "print version information and exit",
+			Aliases:            []string{"V"},
+			DisableDefaultText: true,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.Bool("help") {
+			check(cli.ShowCommandHelp(c, c.Command.Name))
+			return nil
+		}
+		if c.Bool("version") {
+			return printVersion(c)
+		}
+
+		if c.NArg() < 1 {
+			return commandError(c, fmt.Errorf("%w: expected at least one IFO_FILE argument", ErrFlagParse))
+		}
+		policy := stardict.MergePolicy(c.String("policy"))
+		if !slices.Contains([]stardict.MergePolicy{
+			stardict.MergeConcat,
+			stardict.MergeFirst,
+			stardict.MergeLast,
+		}, policy) {
+			return commandError(c, fmt.Errorf("%w: invalid policy %q", ErrFlagParse, policy))
+		}
+		output := c.String("output")
+		if output == "" {
+			return commandError(c, fmt.Errorf("%w: --output is required", ErrFlagParse))
+		}
+		if !strings.EqualFold(filepath.Ext(output), ".ifo") {
+			return commandError(c, fmt.Errorf("%w: output must be an .ifo file: %q", ErrFlagParse, output))
+		}
+
+		var dicts []*stardict.Stardict
+		defer func() {
+			for _, s := range dicts {
+				s.Close()
+			}
+		}()
+		for _, path := range c.Args().Slice() {
+			s, err := stardict.Open(path, nil)
+			if err != nil {
+				return commandError(c, fmt.Errorf("opening %q: %w", path, err))
+			}
+			dicts = append(dicts, s)
+		}
+
+		info, err := stardict.MergeInfo(dicts)
+		if err != nil {
+			return commandError(c, err)
+		}
+		if name := c.String("bookname"); name != "" {
+			info.BookName = name
+		}
+		b, err := stardict.NewBuilder(info, &stardict.BuilderOptions{
+			CompressIndex: c.Bool("compress-index"),
+		})
+		if err != nil {
+			return commandError(c, err)
+		}
+		defer b.Close()
+
+		n, err := stardict.Merge(dicts, b, &stardict.MergeOptions{
+			Policy: policy,
+		})
+		if err != nil {
+			return commandError(c, err)
+		}
+
+		if err := finish(c, b, n, output); err != nil {
+			return commandError(c, err)
+		}
+		return nil
+	},
+}
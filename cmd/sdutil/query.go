@@ -19,9 +19,12 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path/filepath"
 
 	"github.com/rodaine/table"
 	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/go-stardict"
 )
 
 var queryCommand = &cli.Command{
@@ -31,6 +34,16 @@ var queryCommand = &cli.Command{
 	HideHelp:        true,
 	HideHelpCommand: true,
 	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "cache-dir",
+			Usage: "store folded index cache files in `DIR` (default: the user cache directory)",
+		},
+		&cli.BoolFlag{
+			Name:               "no-cache",
+			Usage:              "don't read or write folded index cache files",
+			DisableDefaultText: true,
+		},
+
 		// Special flags are shown at the end.
 		&cli.BoolFlag{
 			Name:               "help",
@@ -58,7 +71,11 @@ var queryCommand = &cli.Command{
 
 		query := args[0]
 
-		dicts, errs := openStardicts(c.StringSlice("data-dir"))
+		dicts, errs := openStardicts(c.StringSlice("data-dir"), &stardict.Options{
+			Folder:        stardict.DefaultFolder,
+			FolderID:      stardict.DefaultFolderID,
+			IndexCacheDir: queryCacheDir(c),
+		})
 		for _, err := range errs {
 			// Ignore errors where data dir doesn't exist.
 			if !errors.Is(err, fs.ErrNotExist) {
@@ -102,3 +119,20 @@ var queryCommand = &cli.Command{
 		return nil
 	},
 }
+
+// queryCacheDir returns the directory for folded index cache files or an
+// empty string if cache files should not be used.
+func queryCacheDir(c *cli.Context) string {
+	if c.Bool("no-cache") {
+		return ""
+	}
+	if dir := c.String("cache-dir"); dir != "" {
+		return dir
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		// Searches work without the cache so it's simply not used.
+		return ""
+	}
+	return filepath.Join(dir, "sdutil")
+}
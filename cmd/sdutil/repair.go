@@ -0,0 +1,156 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/go-stardict"
+)
+
+// repairReportExt is the extension of the repair report written alongside
+// the repaired dictionary.
+const repairReportExt = ".repair.txt"
+
+var repairCommand = &cli.Command{
+	Name:            "repair",
+	Usage:           "Write a repaired copy of a dictionary",
+	ArgsUsage:       "IFO_FILE",
+	HideHelp:        true,
+	HideHelpCommand: true,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "output",
+			Usage:   "write the repaired dictionary to the new directory `DIR`",
+			Aliases: []string{"o"},
+		},
+		&cli.BoolFlag{
+			Name:               "compress-index",
+			Usage:              "compress the index (.idx.gz)",
+			DisableDefaultText: true,
+		},
+
+		// Special flags are shown at the end.
+		&cli.BoolFlag{
+			Name:               "help",
+			Usage:              "print this help text and exit",
+			Aliases:            []string{"h"},
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "version",
+			Usage:              "print version information and exit",
+			Aliases:            []string{"V"},
+			DisableDefaultText: true,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.Bool("help") {
+			check(cli.ShowCommandHelp(c, c.Command.Name))
+			return nil
+		}
+		if c.Bool("version") {
+			return printVersion(c)
+		}
+
+		if c.NArg() != 1 {
+			return commandError(c, fmt.Errorf("%w: expected one IFO_FILE argument", ErrFlagParse))
+		}
+		src := c.Args().First()
+		dir := c.String("output")
+		if dir == "" {
+			return commandError(c, fmt.Errorf("%w: --output is required", ErrFlagParse))
+		}
+
+		s, err := stardict.Open(src, nil)
+		if err != nil {
+			return commandError(c, fmt.Errorf("opening %q: %w", src, err))
+		}
+		defer s.Close()
+
+		// The output directory must be new so that the original dictionary
+		// or another dictionary is never overwritten.
+		if err = os.Mkdir(dir, 0o755); err != nil {
+			return commandError(c, fmt.Errorf("creating %q: %w", dir, err))
+		}
+
+		base := filepath.Base(src)
+		base = strings.TrimSuffix(base, filepath.Ext(base))
+		r, err := stardict.Repair(s, dir, base, &stardict.BuilderOptions{
+			CompressIndex: c.Bool("compress-index"),
+		})
+		if err != nil {
+			// Remove the output directory if nothing was written.
+			_ = os.Remove(dir)
+			return commandError(c, fmt.Errorf("repairing %q: %w", src, err))
+		}
+
+		reportPath := filepath.Join(dir, base+repairReportExt)
+		if err = writeRepairReport(reportPath, src, r); err != nil {
+			return commandError(c, err)
+		}
+
+		if err := printRepairReport(c.App.Writer, src, r); err != nil {
+			return commandError(c, fmt.Errorf("%w: %w", ErrSdutil, err))
+		}
+		if _, err := fmt.Fprintf(c.App.Writer, "wrote %d entries to %s\n",
+			r.Entries, filepath.Join(dir, base+".ifo")); err != nil {
+			return commandError(c, fmt.Errorf("%w: %w", ErrSdutil, err))
+		}
+		if _, err := fmt.Fprintf(c.App.Writer, "wrote report to %s\n", reportPath); err != nil {
+			return commandError(c, fmt.Errorf("%w: %w", ErrSdutil, err))
+		}
+		return nil
+	},
+}
+
+// writeRepairReport writes the repair report to the file at path.
+func writeRepairReport(path, src string, r *stardict.RepairReport) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating %q: %w", path, err)
+	}
+	if err := printRepairReport(f, src, r); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing %q: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing %q: %w", path, err)
+	}
+	return nil
+}
+
+// printRepairReport writes the changes in the repair report, one per line,
+// prefixed by the path of the source dictionary.
+func printRepairReport(w io.Writer, src string, r *stardict.RepairReport) error {
+	if len(r.Changes) == 0 {
+		_, err := fmt.Fprintf(w, "%s: no changes\n", src)
+		//nolint:wrapcheck // error is wrapped by the caller.
+		return err
+	}
+	for _, change := range r.Changes {
+		if _, err := fmt.Fprintf(w, "%s: %s\n", src, change); err != nil {
+			//nolint:wrapcheck // error is wrapped by the caller.
+			return err
+		}
+	}
+	return nil
+}
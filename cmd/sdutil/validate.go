@@ -0,0 +1,101 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ianlewis/go-stardict"
+)
+
+var validateCommand = &cli.Command{
+	Name:            "validate",
+	Usage:           "Check dictionaries for errors",
+	ArgsUsage:       "IFO_FILE...",
+	HideHelp:        true,
+	HideHelpCommand: true,
+	Flags: []cli.Flag{
+		// Special flags are shown at the end.
+		&cli.BoolFlag{
+			Name:               "help",
+			Usage:              "print this help text and exit",
+			Aliases:            []string{"h"},
+			DisableDefaultText: true,
+		},
+		&cli.BoolFlag{
+			Name:               "version",
+			Usage:              "print version information and exit",
+			Aliases:            []string{"V"},
+			DisableDefaultText: true,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.Bool("help") {
+			check(cli.ShowCommandHelp(c, c.Command.Name))
+			return nil
+		}
+		if c.Bool("version") {
+			return printVersion(c)
+		}
+
+		if c.NArg() < 1 {
+			return commandError(c, fmt.Errorf("%w: expected at least one IFO_FILE argument", ErrFlagParse))
+		}
+
+		invalid := 0
+		for _, path := range c.Args().Slice() {
+			ok, err := validate(c, path)
+			if err != nil {
+				return commandError(c, err)
+			}
+			if !ok {
+				invalid++
+			}
+		}
+		if invalid > 0 {
+			return commandError(c, fmt.Errorf("%w: %d of %d dictionaries", ErrInvalidDictionary, invalid, c.NArg()))
+		}
+		return nil
+	},
+}
+
+// validate validates the dictionary at path and prints the findings. It
+// returns whether the dictionary is valid.
+func validate(c *cli.Context, path string) (bool, error) {
+	s, err := stardict.Open(path, nil)
+	if err != nil {
+		// The dictionary can't be opened at all.
+		if _, err = fmt.Fprintf(c.App.Writer, "%s: error: %v\n", path, err); err != nil {
+			return false, fmt.Errorf("%w: %w", ErrSdutil, err)
+		}
+		return false, nil
+	}
+	defer s.Close()
+
+	r := s.Validate()
+	for _, f := range r.Findings {
+		if _, err := fmt.Fprintf(c.App.Writer, "%s: %s\n", path, f); err != nil {
+			return false, fmt.Errorf("%w: %w", ErrSdutil, err)
+		}
+	}
+	if len(r.Findings) == 0 {
+		if _, err := fmt.Fprintf(c.App.Writer, "%s: ok\n", path); err != nil {
+			return false, fmt.Errorf("%w: %w", ErrSdutil, err)
+		}
+	}
+	return r.Valid(), nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package collation implements reading and writing collation (.clt) files and
+// the collation functions they name.
+//
+// A collation file stores the order of the entries in an .idx or .syn file
+// when sorted using a locale specific collation function. Collation files are
+// named after the file they were generated for (e.g. dictionary.idx.clt).
+package collation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// ErrInvalidFunc indicates that the collation function is unknown.
+var ErrInvalidFunc = errors.New("invalid collation function")
+
+// Func is a collation function supported by StarDict.
+type Func int
+
+const (
+	// UTF8GeneralCI is a case-insensitive collation using the Unicode
+	// Collation Algorithm's default ordering.
+	UTF8GeneralCI Func = iota
+
+	// UTF8UnicodeCI is a case-insensitive collation using the Unicode
+	// Collation Algorithm's default ordering.
+	UTF8UnicodeCI
+
+	// UTF8Bin is a binary collation comparing the utf-8 bytes.
+	UTF8Bin
+
+	// UTF8CzechCI is a case-insensitive Czech collation.
+	UTF8CzechCI
+
+	// UTF8DanishCI is a case-insensitive Danish collation.
+	UTF8DanishCI
+
+	// UTF8EsperantoCI is a case-insensitive Esperanto collation.
+	UTF8EsperantoCI
+
+	// UTF8EstonianCI is a case-insensitive Estonian collation.
+	UTF8EstonianCI
+
+	// UTF8HungarianCI is a case-insensitive Hungarian collation.
+	UTF8HungarianCI
+
+	// UTF8IcelandicCI is a case-insensitive Icelandic collation.
+	UTF8IcelandicCI
+
+	// UTF8LatvianCI is a case-insensitive Latvian collation.
+	UTF8LatvianCI
+
+	// UTF8LithuanianCI is a case-insensitive Lithuanian collation.
+	UTF8LithuanianCI
+
+	// UTF8PersianCI is a case-insensitive Persian collation.
+	UTF8PersianCI
+
+	// UTF8PolishCI is a case-insensitive Polish collation.
+	UTF8PolishCI
+
+	// UTF8RomanCI is a case-insensitive Classical Latin collation.
+	//
+	// NOTE: Classical Latin tailoring is not available so the Unicode
+	// Collation Algorithm's default ordering is used.
+	UTF8RomanCI
+
+	// UTF8RomanianCI is a case-insensitive Romanian collation.
+	UTF8RomanianCI
+
+	// UTF8SlovakCI is a case-insensitive Slovak collation.
+	UTF8SlovakCI
+
+	// UTF8SlovenianCI is a case-insensitive Slovenian collation.
+	UTF8SlovenianCI
+
+	// UTF8SpanishCI is a case-insensitive modern Spanish collation.
+	UTF8SpanishCI
+
+	// UTF8Spanish2CI is a case-insensitive traditional Spanish collation.
+	//
+	// NOTE: Traditional Spanish tailoring is not available so the modern
+	// Spanish ordering is used.
+	UTF8Spanish2CI
+
+	// UTF8SwedishCI is a case-insensitive Swedish collation.
+	UTF8SwedishCI
+
+	// UTF8TurkishCI is a case-insensitive Turkish collation.
+	UTF8TurkishCI
+)
+
+// funcs holds the name and language of each collation function in the same
+// order as the Func constants.
+var funcs = []struct {
+	name string
+	lang string
+}{
+	{"utf8_general_ci", "und"},
+	{"utf8_unicode_ci", "und"},
+	{"utf8_bin", ""},
+	{"utf8_czech_ci", "cs"},
+	{"utf8_danish_ci", "da"},
+	{"utf8_esperanto_ci", "eo"},
+	{"utf8_estonian_ci", "et"},
+	{"utf8_hungarian_ci", "hu"},
+	{"utf8_icelandic_ci", "is"},
+	{"utf8_latvian_ci", "lv"},
+	{"utf8_lithuanian_ci", "lt"},
+	{"utf8_persian_ci", "fa"},
+	{"utf8_polish_ci", "pl"},
+	{"utf8_roman_ci", "und"},
+	{"utf8_romanian_ci", "ro"},
+	{"utf8_slovak_ci", "sk"},
+	{"utf8_slovenian_ci", "sl"},
+	{"utf8_spanish_ci", "es"},
+	{"utf8_spanish2_ci", "es"},
+	{"utf8_swedish_ci", "sv"},
+	{"utf8_turkish_ci", "tr"},
+}
+
+// ParseFunc returns the collation function with the given name (e.g.
+// "utf8_spanish_ci").
+func ParseFunc(name string) (Func, error) {
+	for i, f := range funcs {
+		if strings.EqualFold(f.name, name) {
+			return Func(i), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrInvalidFunc, name)
+}
+
+// Valid returns true if f is a known collation function.
+func (f Func) Valid() bool {
+	return f >= 0 && int(f) < len(funcs)
+}
+
+// String returns the name of the collation function.
+func (f Func) String() string {
+	if !f.Valid() {
+		return fmt.Sprintf("Func(%d)", int(f))
+	}
+	return funcs[f].name
+}
+
+// Compare returns a comparison function that orders strings using the
+// collation function. The returned function returns a negative number when
+// a < b, a positive number when a > b and zero when a == b. It is safe for
+// concurrent use.
+func (f Func) Compare() func(a, b string) int {
+	if !f.Valid() || funcs[f].lang == "" {
+		return strings.Compare
+	}
+	return newCompare(language.Make(funcs[f].lang))
+}
+
+// ForLanguage returns a case-insensitive comparison function suitable for the
+// given BCP 47 language tag (e.g. "es" or "zh-Hans"). Underscores are accepted
+// in place of hyphens. ForLanguage returns nil if the language is empty or
+// cannot be parsed.
+func ForLanguage(lang string) func(a, b string) int {
+	lang = strings.TrimSpace(strings.ReplaceAll(lang, "_", "-"))
+	if lang == "" {
+		return nil
+	}
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return nil
+	}
+	return newCompare(tag)
+}
+
+// newCompare returns a comparison function using a collator for the given
+// language. [collate.Collator] is not safe for concurrent use so access is
+// serialized.
+func newCompare(tag language.Tag) func(a, b string) int {
+	var mu sync.Mutex
+	c := collate.New(tag, collate.IgnoreCase)
+	return func(a, b string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return c.CompareString(a, b)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collation_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/collation"
+)
+
+// TestParseFunc tests ParseFunc and Func.String.
+func TestParseFunc(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		expected collation.Func
+		err      bool
+	}{
+		{
+			name:     "utf8_general_ci",
+			expected: collation.UTF8GeneralCI,
+		},
+		{
+			name:     "utf8_spanish2_ci",
+			expected: collation.UTF8Spanish2CI,
+		},
+		{
+			name:     "utf8_turkish_ci",
+			expected: collation.UTF8TurkishCI,
+		},
+		{
+			name: "utf8_klingon_ci",
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := collation.ParseFunc(test.name)
+			if test.err {
+				if err == nil {
+					t.Fatal("ParseFunc: expected failure")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFunc: %v", err)
+			}
+			if want, got := test.expected, f; want != got {
+				t.Errorf("ParseFunc: want: %v, got: %v", want, got)
+			}
+			if want, got := test.name, f.String(); want != got {
+				t.Errorf("String: want: %q, got: %q", want, got)
+			}
+		})
+	}
+}
+
+// TestFunc_Compare tests Func.Compare.
+func TestFunc_Compare(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		f        collation.Func
+		words    []string
+		expected []string
+	}{
+		{
+			name:     "binary",
+			f:        collation.UTF8Bin,
+			words:    []string{"b", "B", "a", "A"},
+			expected: []string{"A", "B", "a", "b"},
+		},
+		{
+			name:     "general",
+			f:        collation.UTF8GeneralCI,
+			words:    []string{"b", "c", "a"},
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:     "swedish",
+			f:        collation.UTF8SwedishCI,
+			words:    []string{"äpple", "zebra", "apa"},
+			expected: []string{"apa", "zebra", "äpple"},
+		},
+		{
+			name:     "spanish",
+			f:        collation.UTF8SpanishCI,
+			words:    []string{"ñu", "oso", "nube"},
+			expected: []string{"nube", "ñu", "oso"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			words := slices.Clone(test.words)
+			slices.SortFunc(words, test.f.Compare())
+			if diff := cmp.Diff(test.expected, words); diff != "" {
+				t.Errorf("sorted words (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestForLanguage tests ForLanguage.
+func TestForLanguage(t *testing.T) {
+	t.Parallel()
+
+	if compare := collation.ForLanguage(""); compare != nil {
+		t.Error("ForLanguage: expected nil for empty language")
+	}
+
+	compare := collation.ForLanguage("sv_SE")
+	if compare == nil {
+		t.Fatal("ForLanguage: unexpected nil")
+	}
+	if compare("zebra", "äpple") >= 0 {
+		t.Error("ForLanguage: want zebra < äpple")
+	}
+	if compare("Apa", "apa") != 0 {
+		t.Error("ForLanguage: want case-insensitive comparison")
+	}
+}
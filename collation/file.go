@@ -0,0 +1,146 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collation
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// cltMagic is the header written at the start of collation files.
+const cltMagic = "StarDict's clt file\nversion=2.4.8\n"
+
+var (
+	errInvalidMagic  = errors.New("invalid magic data")
+	errInvalidHeader = errors.New("invalid collation file header")
+	errTruncated     = errors.New("truncated collation file")
+)
+
+// File is the contents of a collation (.clt) file.
+//
+// The collation file begins with a text header containing the magic string,
+// the url of the file the collation was generated for, and the collation
+// function number. The header is followed by one 32-bit integer for each
+// entry in little-endian byte order.
+type File struct {
+	// URL is the path to the .idx or .syn file that the collation file was
+	// generated for.
+	URL string
+
+	// Func is the collation function used to sort the entries.
+	Func Func
+
+	// Order is the order of entries when sorted using the collation
+	// function. Order[i] is the index of the i-th entry in the .idx or .syn
+	// file.
+	Order []uint32
+}
+
+// Read reads a collation file from r.
+func Read(r io.Reader) (*File, error) {
+	br := bufio.NewReader(r)
+
+	magic := make([]byte, len(cltMagic))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidMagic, err)
+	}
+	if string(magic) != cltMagic {
+		return nil, errInvalidMagic
+	}
+
+	f := &File{}
+
+	url, err := readHeader(br, "url")
+	if err != nil {
+		return nil, err
+	}
+	f.URL = url
+
+	fn, err := readHeader(br, "func")
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(fn)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFunc, err)
+	}
+	f.Func = Func(n)
+	if !f.Func.Valid() {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidFunc, n)
+	}
+
+	b, err := io.ReadAll(br)
+	if err != nil {
+		return nil, fmt.Errorf("reading collation file: %w", err)
+	}
+	if len(b)%4 != 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", errTruncated, len(b)%4)
+	}
+	f.Order = make([]uint32, len(b)/4)
+	for i := range f.Order {
+		f.Order[i] = binary.LittleEndian.Uint32(b[i*4:])
+	}
+
+	return f, nil
+}
+
+// readHeader reads a "key=value" header line.
+func readHeader(r *bufio.Reader, key string) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("%w: reading %s: %w", errInvalidHeader, key, err)
+	}
+	value, ok := strings.CutPrefix(strings.TrimSuffix(line, "\n"), key+"=")
+	if !ok {
+		return "", fmt.Errorf("%w: missing %s", errInvalidHeader, key)
+	}
+	return value, nil
+}
+
+// ReadFile reads the collation file at the given path.
+func ReadFile(path string) (*File, error) {
+	r, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening collation file: %w", err)
+	}
+	defer r.Close()
+
+	return Read(r)
+}
+
+// Write writes the collation file to w.
+func Write(w io.Writer, f *File) error {
+	bw := bufio.NewWriter(w)
+	if _, err := fmt.Fprintf(bw, "%surl=%s\nfunc=%d\n", cltMagic, f.URL, int(f.Func)); err != nil {
+		return fmt.Errorf("writing collation file: %w", err)
+	}
+	var b [4]byte
+	for _, i := range f.Order {
+		binary.LittleEndian.PutUint32(b[:], i)
+		if _, err := bw.Write(b[:]); err != nil {
+			return fmt.Errorf("writing collation file: %w", err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("writing collation file: %w", err)
+	}
+	return nil
+}
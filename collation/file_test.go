@@ -0,0 +1,111 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collation_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/collation"
+)
+
+// TestWrite tests that a collation file can be written and read back.
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	f := &collation.File{
+		URL:   "/usr/share/stardict/dic/dictionary.idx",
+		Func:  collation.UTF8SpanishCI,
+		Order: []uint32{2, 0, 1},
+	}
+
+	var b bytes.Buffer
+	if err := collation.Write(&b, f); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	got, err := collation.Read(&b)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if diff := cmp.Diff(f, got); diff != "" {
+		t.Errorf("Read (-want, +got):\n%s", diff)
+	}
+}
+
+// TestRead tests Read.
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		data     string
+		expected *collation.File
+		err      bool
+	}{
+		{
+			name: "valid",
+			data: "StarDict's clt file\nversion=2.4.8\nurl=dictionary.idx\nfunc=17\n\x01\x00\x00\x00\x00\x00\x00\x00",
+			expected: &collation.File{
+				URL:   "dictionary.idx",
+				Func:  collation.UTF8SpanishCI,
+				Order: []uint32{1, 0},
+			},
+		},
+		{
+			name: "invalid magic",
+			data: "StarDict's oft file\nversion=2.4.8\nurl=dictionary.idx\nfunc=17\n",
+			err:  true,
+		},
+		{
+			name: "invalid func",
+			data: "StarDict's clt file\nversion=2.4.8\nurl=dictionary.idx\nfunc=99\n",
+			err:  true,
+		},
+		{
+			name: "missing url",
+			data: "StarDict's clt file\nversion=2.4.8\nfunc=17\n",
+			err:  true,
+		},
+		{
+			name: "truncated",
+			data: "StarDict's clt file\nversion=2.4.8\nurl=dictionary.idx\nfunc=17\n\x01\x00",
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := collation.Read(strings.NewReader(test.data))
+			if test.err {
+				if err == nil {
+					t.Fatal("Read: expected failure")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if diff := cmp.Diff(test.expected, f); diff != "" {
+				t.Errorf("Read (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
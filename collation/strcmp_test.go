@@ -0,0 +1,49 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collation
+
+import (
+	"testing"
+)
+
+// TestStardictCompare tests StardictCompare.
+func TestStardictCompare(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "abc", b: "abc", expected: 0},
+		{a: "abc", b: "abd", expected: -1},
+		{a: "ABD", b: "abc", expected: 1},
+		{a: "abc", b: "ABC", expected: 1},
+		{a: "ABC", b: "abc", expected: -1},
+		{a: "ab", b: "ABC", expected: -1},
+		{a: "Zebra", b: "apple", expected: 1},
+		{a: "_", b: "a", expected: -1},
+		{a: "é", b: "z", expected: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.a+"_"+test.b, func(t *testing.T) {
+			t.Parallel()
+
+			if got := StardictCompare(test.a, test.b); got != test.expected {
+				t.Errorf("StardictCompare(%q, %q): expected %d, got %d", test.a, test.b, test.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dict
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/language"
+)
+
+// detectCandidates are the character encodings tried by DetectCharset in
+// order of preference.
+var detectCandidates = []encoding.Encoding{
+	simplifiedchinese.GBK,
+	traditionalchinese.Big5,
+	japanese.ShiftJIS,
+	japanese.EUCJP,
+	korean.EUCKR,
+}
+
+// CharsetForLanguage returns the legacy character encoding commonly used for
+// dictionaries in the given language. The language is given as a BCP 47
+// language tag or locale name (e.g. "zh_TW") as found in the .ifo file's lang
+// key. It returns nil if the language is empty, unknown, or typically uses
+// UTF-8.
+func CharsetForLanguage(lang string) encoding.Encoding {
+	lang = strings.ReplaceAll(lang, "_", "-")
+	if lang == "" {
+		return nil
+	}
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return nil
+	}
+
+	base, _ := tag.Base()
+	switch base.String() {
+	case "zh":
+		script, _ := tag.Script()
+		if script.String() == "Hant" {
+			return traditionalchinese.Big5
+		}
+		return simplifiedchinese.GBK
+	case "ja":
+		return japanese.ShiftJIS
+	case "ko":
+		return korean.EUCKR
+	case "ru", "uk", "be", "bg", "sr", "mk":
+		return charmap.Windows1251
+	case "pl", "cs", "sk", "hu", "sl", "hr", "ro":
+		return charmap.Windows1250
+	case "el":
+		return charmap.Windows1253
+	case "tr":
+		return charmap.Windows1254
+	case "he":
+		return charmap.Windows1255
+	case "ar", "fa":
+		return charmap.Windows1256
+	case "lt", "lv", "et":
+		return charmap.Windows1257
+	case "vi":
+		return charmap.Windows1258
+	case "th":
+		return charmap.Windows874
+	case "en", "fr", "de", "es", "it", "pt", "nl", "sv", "da", "no", "nb", "fi", "is":
+		return charmap.Windows1252
+	default:
+		return nil
+	}
+}
+
+// DetectCharset guesses the character encoding of data. It returns nil if
+// data is valid UTF-8. Otherwise, the CJK multi-byte encodings are tried in
+// turn and the encoding that decodes data with the fewest invalid characters
+// is returned. Detection is a heuristic and may guess incorrectly for short
+// data.
+func DetectCharset(data []byte) encoding.Encoding {
+	if utf8.Valid(data) {
+		return nil
+	}
+
+	var best encoding.Encoding
+	bestInvalid := -1
+	for _, enc := range detectCandidates {
+		b, err := enc.NewDecoder().Bytes(data)
+		if err != nil {
+			continue
+		}
+		invalid := strings.Count(string(b), string(utf8.RuneError))
+		if bestInvalid < 0 || invalid < bestInvalid {
+			best = enc
+			bestInvalid = invalid
+		}
+	}
+	return best
+}
+
+// decodeCharset decodes data in the given character encoding to UTF-8.
+func decodeCharset(enc encoding.Encoding, data []byte) ([]byte, error) {
+	b, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding locale text: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dict_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/idx"
+	"github.com/ianlewis/go-stardict/internal/testutil"
+)
+
+// encode encodes s using the given encoding.
+func encode(t *testing.T, enc encoding.Encoding, s string) []byte {
+	t.Helper()
+
+	b, err := enc.NewEncoder().Bytes([]byte(s))
+	if err != nil {
+		t.Fatalf("encoding %q: %v", s, err)
+	}
+	return b
+}
+
+// TestCharsetForLanguage tests CharsetForLanguage.
+func TestCharsetForLanguage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		lang     string
+		expected encoding.Encoding
+	}{
+		{lang: "", expected: nil},
+		{lang: "zh", expected: simplifiedchinese.GBK},
+		{lang: "zh_CN", expected: simplifiedchinese.GBK},
+		{lang: "zh-TW", expected: traditionalchinese.Big5},
+		{lang: "zh-Hant", expected: traditionalchinese.Big5},
+		{lang: "ja", expected: japanese.ShiftJIS},
+		{lang: "ru", expected: charmap.Windows1251},
+		{lang: "xx-invalid-!", expected: nil},
+		{lang: "eo", expected: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.lang, func(t *testing.T) {
+			t.Parallel()
+
+			if got := dict.CharsetForLanguage(test.lang); got != test.expected {
+				t.Errorf("CharsetForLanguage(%q): expected %v, got %v", test.lang, test.expected, got)
+			}
+		})
+	}
+}
+
+// TestDetectCharset tests DetectCharset.
+func TestDetectCharset(t *testing.T) {
+	t.Parallel()
+
+	if got := dict.DetectCharset([]byte("ユニコード")); got != nil {
+		t.Errorf("DetectCharset(utf-8): expected nil, got %v", got)
+	}
+
+	gbk := encode(t, simplifiedchinese.GBK, "你好，世界。这是一个简体中文的句子。")
+	if got := dict.DetectCharset(gbk); got != simplifiedchinese.GBK {
+		t.Errorf("DetectCharset(gbk): expected %v, got %v", simplifiedchinese.GBK, got)
+	}
+}
+
+// TestDict_Word_charset tests decoding LocaleTextType data.
+func TestDict_Word_charset(t *testing.T) {
+	t.Parallel()
+
+	big5 := encode(t, traditionalchinese.Big5, "字典")
+	gbk := encode(t, simplifiedchinese.GBK, "你好，世界。这是一个简体中文的句子。")
+
+	tests := []struct {
+		name    string
+		data    []byte
+		options *dict.Options
+
+		expected string
+	}{
+		{
+			name:     "no charset",
+			data:     []byte("hoge"),
+			expected: "hoge",
+		},
+		{
+			name: "charset",
+			data: big5,
+			options: &dict.Options{
+				Charset: traditionalchinese.Big5,
+			},
+			expected: "字典",
+		},
+		{
+			name: "detect utf-8",
+			data: []byte("字典"),
+			options: &dict.Options{
+				Charset:       traditionalchinese.Big5,
+				DetectCharset: true,
+			},
+			expected: "字典",
+		},
+		{
+			name: "detect with charset",
+			data: big5,
+			options: &dict.Options{
+				Charset:       traditionalchinese.Big5,
+				DetectCharset: true,
+			},
+			expected: "字典",
+		},
+		{
+			name: "detect",
+			data: gbk,
+			options: &dict.Options{
+				DetectCharset: true,
+			},
+			expected: "你好，世界。这是一个简体中文的句子。",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := testutil.MakeDict(t, []*dict.Word{
+				{
+					Data: []*dict.Data{
+						{
+							Type: dict.LocaleTextType,
+							Data: test.data,
+						},
+					},
+				},
+			}, nil)
+
+			d, err := dict.New(readerAtCloser{bytes.NewReader(b)}, test.options)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+
+			w, err := d.Word(&idx.Word{
+				Size: uint32(len(b)),
+			})
+			if err != nil {
+				t.Fatalf("Word: %v", err)
+			}
+			if diff := cmp.Diff(test.expected, w.Data[0].String()); diff != "" {
+				t.Errorf("String (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// readerAtCloser is an io.ReaderAt with a no-op Close method.
+type readerAtCloser struct {
+	*bytes.Reader
+}
+
+func (readerAtCloser) Close() error {
+	return nil
+}
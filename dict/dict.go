@@ -24,19 +24,54 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/ianlewis/go-dictzip"
 	"github.com/k3a/html2text"
+	"golang.org/x/text/encoding"
 
 	"github.com/ianlewis/go-stardict/idx"
 )
 
+// DefaultMaxEntrySize is the default maximum size of an entry's data.
+const DefaultMaxEntrySize = 64 << 20
+
 var (
+	// ErrCorruptEntry indicates that an entry's data is corrupt. Errors for
+	// corrupt entries are *CorruptEntryError values that wrap
+	// ErrCorruptEntry.
+	ErrCorruptEntry = errors.New("corrupt entry")
+
 	errInvalidType        = errors.New("invalid type")
 	errWordOffsetTooLarge = errors.New("word offset too large")
+	errTruncatedSize      = errors.New("truncated data size")
+	errTruncatedData      = errors.New("truncated data")
 )
 
+// CorruptEntryError is the error returned when an entry's data is corrupt.
+type CorruptEntryError struct {
+	// Word is the entry's headword.
+	Word string
+
+	// Offset is the offset of the entry's data in the .dict file.
+	Offset uint64
+
+	// Reason describes the problem with the data.
+	Reason string
+}
+
+// Error implements error.Error.
+func (e *CorruptEntryError) Error() string {
+	return fmt.Sprintf("%v: %q at offset %d: %s", ErrCorruptEntry, e.Word, e.Offset, e.Reason)
+}
+
+// Unwrap returns ErrCorruptEntry.
+func (e *CorruptEntryError) Unwrap() error {
+	return ErrCorruptEntry
+}
+
 // ReaderAtCloser is an interface that wraps the io.ReaderAt and io.Closer
 // interfaces.
 type ReaderAtCloser interface {
@@ -52,12 +87,29 @@ type Options struct {
 	//
 	// See: https://github.com/huzheng001/stardict-3/blob/master/dict/doc/StarDictFileFormat
 	SameTypeSequence []DataType
+
+	// Charset is the character encoding of LocaleTextType data. If set,
+	// LocaleTextType data is decoded to UTF-8 when it is read.
+	Charset encoding.Encoding
+
+	// DetectCharset indicates that LocaleTextType data that is valid UTF-8
+	// should not be decoded. Other data is decoded using Charset or, if
+	// Charset is nil, the character encoding guessed by DetectCharset.
+	DetectCharset bool
+
+	// MaxEntrySize is the maximum size of an entry's data. Entries that are
+	// larger are reported as corrupt rather than allocating a buffer for
+	// them. If zero, DefaultMaxEntrySize is used.
+	MaxEntrySize uint32
 }
 
 // Dict represents a Stardict dictionary's dictionary data.
 type Dict struct {
 	r                ReaderAtCloser
 	sametypesequence []DataType
+	charset          encoding.Encoding
+	detectCharset    bool
+	maxEntrySize     uint32
 }
 
 // Word is a full dictionary entry.
@@ -99,7 +151,8 @@ const (
 	// UTFTextType is utf-8 text.
 	UTFTextType = DataType('m')
 
-	// LocaleTextType is text in a locale encoding.
+	// LocaleTextType is text in a locale encoding. The data is decoded to
+	// UTF-8 when read if a character encoding is given in the Options.
 	LocaleTextType = DataType('l')
 
 	// PangoTextType is utf-8 text in the Pango text format.
@@ -126,7 +179,9 @@ const (
 	// WordNetType is WordNet data.
 	WordNetType = DataType('n')
 
-	// ResourceFileListType is a list of files in resource storage.
+	// ResourceFileListType is a list of files in resource storage. Each line
+	// is a file type prefix and a file name relative to the resource storage
+	// (e.g. "img:pic/example.jpg").
 	ResourceFileListType = DataType('r')
 
 	// WavType is .wav sound file data.
@@ -141,6 +196,35 @@ const (
 	ExperimentalType = DataType('X')
 )
 
+// valid returns true if the data type is a known data type.
+func (t DataType) valid() bool {
+	switch t {
+	case UTFTextType,
+		LocaleTextType,
+		PangoTextType,
+		PhoneticType,
+		XDXFType,
+		YinBiaoOrKataType,
+		PowerWordType,
+		MediaWikiType,
+		HTMLType,
+		WordNetType,
+		ResourceFileListType,
+		WavType,
+		PictureType,
+		ExperimentalType:
+		return true
+	default:
+		return false
+	}
+}
+
+// isString returns true if the data type is string-like data that is
+// terminated by a null terminator.
+func (t DataType) isString() bool {
+	return 'a' <= t && t <= 'z'
+}
+
 // Data is a data entry in a Word.
 type Data struct {
 	Type DataType
@@ -155,9 +239,35 @@ func (d *Data) String() string {
 		return string(d.Data)
 	case HTMLType:
 		return html2text.HTML2Text(string(d.Data))
-	case PangoTextType, XDXFType, PowerWordType, WordNetType, ResourceFileListType,
-		WavType, PictureType, ExperimentalType:
-		// TODO(#22): Support other formats.
+	case ResourceFileListType:
+		// The resource file list is a newline separated list of files.
+		return string(d.Data)
+	case XDXFType:
+		a, err := ParseXDXF(d.Data)
+		if err != nil {
+			return string(d.Data)
+		}
+		return a.Text()
+	case PangoTextType:
+		m, err := ParsePango(d.Data)
+		if err != nil {
+			return string(d.Data)
+		}
+		return m.Text()
+	case PowerWordType:
+		e, err := ParsePowerWord(d.Data)
+		if err != nil {
+			return string(d.Data)
+		}
+		return e.Text()
+	case WordNetType:
+		e, err := ParseWordNet(d.Data)
+		if err != nil {
+			return string(d.Data)
+		}
+		return e.Text()
+	case WavType, PictureType, ExperimentalType:
+		// Binary data has no string representation.
 		return ""
 	default:
 		return ""
@@ -172,45 +282,48 @@ func New(r ReaderAtCloser, options *Options) (*Dict, error) {
 	}
 
 	// verify sametypesequence
-	for _, s := range options.SameTypeSequence {
-		switch s {
-		case UTFTextType,
-			LocaleTextType,
-			PangoTextType,
-			PhoneticType,
-			XDXFType,
-			YinBiaoOrKataType,
-			PowerWordType,
-			MediaWikiType,
-			HTMLType,
-			WordNetType,
-			ResourceFileListType,
-			WavType,
-			PictureType,
-			ExperimentalType:
-		default:
-			return nil, fmt.Errorf("%w: %v", errInvalidType, s)
+	for _, t := range options.SameTypeSequence {
+		if !t.valid() {
+			return nil, fmt.Errorf("%w: %v", errInvalidType, t)
 		}
 	}
 
+	maxEntrySize := options.MaxEntrySize
+	if maxEntrySize == 0 {
+		maxEntrySize = DefaultMaxEntrySize
+	}
+
 	return &Dict{
 		r:                r,
 		sametypesequence: options.SameTypeSequence,
+		charset:          options.Charset,
+		detectCharset:    options.DetectCharset,
+		maxEntrySize:     maxEntrySize,
 	}, nil
 }
 
-// NewFromIfoPath opens the dict file given the path to the .ifo file.
-func NewFromIfoPath(ifoPath string, options *Options) (*Dict, error) {
+// dictExts are the file extensions of .dict files in the order that Open looks
+// for them.
+var dictExts = []string{
+	".dict",
+	".dict.dz",
+	".dict.DZ",
+	".DICT",
+	".DICT.dz",
+	".DICT.DZ",
+}
+
+// Extensions returns the file extensions of .dict files in the order that
+// Open looks for them.
+func Extensions() []string {
+	return slices.Clone(dictExts)
+}
+
+// Open opens the .dict file given the path to the .ifo file. The file may be
+// compressed with dictzip.
+func Open(ifoPath string) (*os.File, error) {
 	baseName := strings.TrimSuffix(ifoPath, filepath.Ext(ifoPath))
 
-	dictExts := []string{
-		".dict",
-		".dict.dz",
-		".dict.DZ",
-		".DICT",
-		".DICT.dz",
-		".DICT.DZ",
-	}
 	var f *os.File
 	var err error
 	for _, ext := range dictExts {
@@ -228,6 +341,16 @@ func NewFromIfoPath(ifoPath string, options *Options) (*Dict, error) {
 		return nil, fmt.Errorf("opening .dict file: %w", err)
 	}
 
+	return f, nil
+}
+
+// NewFromIfoPath opens the dict file given the path to the .ifo file.
+func NewFromIfoPath(ifoPath string, options *Options) (*Dict, error) {
+	f, err := Open(ifoPath)
+	if err != nil {
+		return nil, err
+	}
+
 	r := &dictReader{
 		f: f,
 	}
@@ -243,18 +366,25 @@ func NewFromIfoPath(ifoPath string, options *Options) (*Dict, error) {
 	return New(r, options)
 }
 
-// Word retrieves the word for the given index entry from the
-// dictionary.
+// Word retrieves the word for the given index entry from the dictionary. An
+// error wrapping ErrCorruptEntry is returned if the entry's data is larger
+// than the maximum entry size, extends past the end of the .dict file, or
+// can't be decoded.
 func (d *Dict) Word(e *idx.Word) (*Word, error) {
-	b := make([]byte, e.Size)
 	// NOTE: Dictionary word offsets math.MaxInt64 < x < math.MaxUint64 not supported.
 	if e.Offset > math.MaxInt64 {
 		return nil, fmt.Errorf("%w: %d", errWordOffsetTooLarge, e.Offset)
 	}
-	// NOTE: if ReadAt does not read e.Size bytes then an error should be
-	// returned.
-	_, err := d.r.ReadAt(b, int64(e.Offset))
-	if err != nil {
+	if e.Size > d.maxEntrySize {
+		return nil, corruptEntry(e, "size %d exceeds the maximum entry size %d", e.Size, d.maxEntrySize)
+	}
+
+	b := make([]byte, e.Size)
+	n, err := d.r.ReadAt(b, int64(e.Offset))
+	if n < len(b) && (err == nil || errors.Is(err, io.EOF)) {
+		return nil, corruptEntry(e, "data extends past the end of the .dict file")
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
 		return nil, fmt.Errorf("reading dictionary: %w", err)
 	}
 
@@ -262,25 +392,24 @@ func (d *Dict) Word(e *idx.Word) (*Word, error) {
 	if len(d.sametypesequence) > 0 {
 		// When sametypesequence is specified, that determines the type of the
 		// word's data.
-		for _, t := range d.sametypesequence {
+		for i, t := range d.sametypesequence {
 			var data []byte
-			if 'a' <= t && t <= 'z' {
-				// Data is a string like sequence.
-				i := bytes.IndexByte(b, 0)
-				if i >= 0 {
-					i++
-				} else {
-					// Use the full length of the buffer if no null terminator
-					// is found. The final data won't have one.
-					i = len(b)
-				}
-				data = b[:i]
-				b = b[i:]
-			} else {
+			switch {
+			case t.isString():
+				// Data is a string like sequence. The last data has no null
+				// terminator so the rest of the buffer is used if no null
+				// terminator is found.
+				data, b = splitString(b)
+			case i == len(d.sametypesequence)-1:
+				// The size of the last file like data is omitted so it is
+				// the rest of the buffer.
+				data, b = b, nil
+			default:
 				// Data is a file like sequence.
-				size := binary.BigEndian.Uint32(b)
-				data = b[4 : 4+size]
-				b = b[4+size:]
+				data, b, err = splitFile(b)
+				if err != nil {
+					return nil, corruptEntry(e, "data %d (%c): %v", i, t, err)
+				}
 			}
 			wordData = append(wordData, &Data{
 				Type: t,
@@ -293,19 +422,19 @@ func (d *Dict) Word(e *idx.Word) (*Word, error) {
 			b = b[1:]
 
 			var data []byte
-			if 'a' <= t && t <= 'z' {
-				// Data is a string like sequence.
-				i := bytes.IndexByte(b, 0)
-				if i < 0 {
-					i = len(b)
-				}
-				data = b[:i]
-				b = b[i+1:] // Skip the null terminator
-			} else {
+			switch {
+			case t.isString():
+				// Data is a string like sequence. A missing null
+				// terminator at the end of the entry is tolerated.
+				data, b = splitString(b)
+			case 'A' <= t && t <= 'Z':
 				// Data is a file like sequence.
-				size := binary.BigEndian.Uint32(b)
-				data = b[4 : 4+size]
-				b = b[4+size:]
+				data, b, err = splitFile(b)
+				if err != nil {
+					return nil, corruptEntry(e, "data %d (%c): %v", len(wordData), t, err)
+				}
+			default:
+				return nil, corruptEntry(e, "data %d: %v: %q", len(wordData), errInvalidType, t)
 			}
 			wordData = append(wordData, &Data{
 				Type: t,
@@ -314,11 +443,67 @@ func (d *Dict) Word(e *idx.Word) (*Word, error) {
 		}
 	}
 
+	// Decode locale text to UTF-8.
+	for _, data := range wordData {
+		if data.Type != LocaleTextType {
+			continue
+		}
+		charset := d.charset
+		if d.detectCharset {
+			if utf8.Valid(data.Data) {
+				continue
+			}
+			if charset == nil {
+				charset = DetectCharset(data.Data)
+			}
+		}
+		if charset != nil {
+			data.Data, err = decodeCharset(charset, data.Data)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return &Word{
 		Data: wordData,
 	}, nil
 }
 
+// splitString splits null terminated string data from the beginning of b and
+// returns the data and the rest of b. If there is no null terminator the
+// whole buffer is returned as the data.
+func splitString(b []byte) ([]byte, []byte) {
+	i := bytes.IndexByte(b, 0)
+	if i < 0 {
+		return b, nil
+	}
+	return b[:i], b[i+1:]
+}
+
+// splitFile splits file data preceded by its 32-bit size from the beginning
+// of b and returns the data and the rest of b.
+func splitFile(b []byte) ([]byte, []byte, error) {
+	if len(b) < 4 {
+		return nil, nil, errTruncatedSize
+	}
+	size := binary.BigEndian.Uint32(b)
+	b = b[4:]
+	if uint64(size) > uint64(len(b)) {
+		return nil, nil, fmt.Errorf("%w: size %d, %d bytes remaining", errTruncatedData, size, len(b))
+	}
+	return b[:size], b[size:], nil
+}
+
+// corruptEntry returns a CorruptEntryError for the index entry.
+func corruptEntry(e *idx.Word, format string, args ...any) error {
+	return &CorruptEntryError{
+		Word:   e.Word,
+		Offset: e.Offset,
+		Reason: fmt.Sprintf(format, args...),
+	}
+}
+
 // Close closes the underlying reader for the .dict file.
 func (d *Dict) Close() error {
 	//nolint:wrapcheck // error wrapping is unnecessary.
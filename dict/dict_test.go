@@ -15,7 +15,9 @@
 package dict_test
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -59,14 +61,45 @@ func TestData_String(t *testing.T) {
 			},
 			expected: "Body",
 		},
+		{
+			name: "PangoTextType",
+			data: &dict.Data{
+				Type: dict.PangoTextType,
+				Data: []byte(`<span foreground="blue"><b>hoge</b></span> fuga`),
+			},
+			expected: "hoge fuga",
+		},
+		{
+			name: "PowerWordType",
+			data: &dict.Data{
+				Type: dict.PowerWordType,
+				Data: []byte("<单词原型>hoge</单词原型><单词词性>n.</单词词性><解释项>fuga</解释项>"),
+			},
+			expected: "hoge\nn. fuga",
+		},
+		{
+			name: "WordNetType",
+			data: &dict.Data{
+				Type: dict.WordNetType,
+				Data: []byte("<type>n</type><wordgroup><word>hoge</word></wordgroup><gloss>fuga</gloss>"),
+			},
+			expected: "n. hoge\n  fuga",
+		},
+		{
+			name: "ResourceFileListType",
+			data: &dict.Data{
+				Type: dict.ResourceFileListType,
+				Data: []byte("img:pic/hoge.jpg\nsnd:hoge.wav"),
+			},
+			expected: "img:pic/hoge.jpg\nsnd:hoge.wav",
+		},
 		{
 			name: "XDXFType",
 			data: &dict.Data{
 				Type: dict.XDXFType,
-				Data: []byte("Some XDXF Format"),
+				Data: []byte("<k>hoge</k>\n<tr>hoɡe</tr> <abr>n.</abr> <dtrn>foo</dtrn>"),
 			},
-			// TODO(#22): Support other formats.
-			expected: "",
+			expected: "hoge\n[hoɡe] n. foo",
 		},
 	}
 
@@ -147,6 +180,44 @@ func TestDict_Word(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "multiple sametype",
+			sameTypeSequence: []dict.DataType{
+				dict.PhoneticType,
+				dict.UTFTextType,
+			},
+			dict: []*dict.Word{
+				{
+					Data: []*dict.Data{
+						{
+							Type: dict.PhoneticType,
+							Data: []byte{'h', 'o', 'g', 'e'},
+						},
+						{
+							Type: dict.UTFTextType,
+							Data: []byte{'f', 'u', 'g', 'a'},
+						},
+					},
+				},
+			},
+			index: &idx.Word{
+				Word:   "hoge",
+				Offset: uint64(0),
+				Size:   uint32(9), // 4 data + null terminator + 4 data
+			},
+			expected: &dict.Word{
+				Data: []*dict.Data{
+					{
+						Type: dict.PhoneticType,
+						Data: []byte{'h', 'o', 'g', 'e'},
+					},
+					{
+						Type: dict.UTFTextType,
+						Data: []byte{'f', 'u', 'g', 'a'},
+					},
+				},
+			},
+		},
 		{
 			name: "file type",
 			dict: []*dict.Word{
@@ -191,7 +262,7 @@ func TestDict_Word(t *testing.T) {
 			index: &idx.Word{
 				Word:   "hoge",
 				Offset: uint64(0),
-				Size:   uint32(8), // 4 (file size) + 4 data
+				Size:   uint32(4), // 4 data without the file size
 			},
 			expected: &dict.Word{
 				Data: []*dict.Data{
@@ -234,6 +305,205 @@ func TestDict_Word(t *testing.T) {
 	}
 }
 
+// TestDict_Word_corrupt tests that Word returns ErrCorruptEntry for corrupt
+// entries.
+func TestDict_Word_corrupt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		data             []byte
+		index            *idx.Word
+		sameTypeSequence []dict.DataType
+		maxEntrySize     uint32
+	}{
+		{
+			name:  "past end of file",
+			data:  []byte("mhoge\x00"),
+			index: &idx.Word{Word: "hoge", Offset: 2, Size: 6},
+		},
+		{
+			name:         "too large",
+			data:         []byte("mhoge\x00"),
+			index:        &idx.Word{Word: "hoge", Offset: 0, Size: 6},
+			maxEntrySize: 5,
+		},
+		{
+			name:  "truncated file size",
+			data:  []byte("W\x00\x00"),
+			index: &idx.Word{Word: "hoge", Offset: 0, Size: 3},
+		},
+		{
+			name:  "truncated file data",
+			data:  []byte("W\x00\x00\x00\x05hoge"),
+			index: &idx.Word{Word: "hoge", Offset: 0, Size: 9},
+		},
+		{
+			name:             "truncated sametype file data",
+			data:             []byte("\xff\xff\xff\xffhoge"),
+			index:            &idx.Word{Word: "hoge", Offset: 0, Size: 8},
+			sameTypeSequence: []dict.DataType{dict.WavType, dict.UTFTextType},
+		},
+		{
+			name:  "invalid type",
+			data:  []byte("\x01hoge"),
+			index: &idx.Word{Word: "hoge", Offset: 0, Size: 5},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "dictionary.dict")
+			if err := os.WriteFile(path, test.data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			d, err := dict.New(f, &dict.Options{
+				SameTypeSequence: test.sameTypeSequence,
+				MaxEntrySize:     test.maxEntrySize,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer d.Close()
+
+			_, err = d.Word(test.index)
+			if !errors.Is(err, dict.ErrCorruptEntry) {
+				t.Fatalf("Dict.Word: expected %v, got %v", dict.ErrCorruptEntry, err)
+			}
+			var corruptErr *dict.CorruptEntryError
+			if !errors.As(err, &corruptErr) {
+				t.Fatalf("Dict.Word: expected *dict.CorruptEntryError, got %T", err)
+			}
+			if got, want := corruptErr.Word, test.index.Word; got != want {
+				t.Errorf("Word: expected %q, got %q", want, got)
+			}
+			if got, want := corruptErr.Offset, test.index.Offset; got != want {
+				t.Errorf("Offset: expected %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+// TestDict_Word_unterminated tests that a missing null terminator at the end
+// of an entry is tolerated.
+func TestDict_Word_unterminated(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "dictionary.dict")
+	if err := os.WriteFile(path, []byte("mhoge"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := dict.New(f, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	w, err := d.Word(&idx.Word{Word: "hoge", Offset: 0, Size: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &dict.Word{
+		Data: []*dict.Data{
+			{
+				Type: dict.UTFTextType,
+				Data: []byte("hoge"),
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, w); diff != "" {
+		t.Fatalf("Dict.Word (-want, +got):\n%s", diff)
+	}
+}
+
+// TestDict_Word_sametypesequence tests decoding raw sametypesequence entries
+// without relying on the test .dict file builder.
+func TestDict_Word_sametypesequence(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		data             []byte
+		sameTypeSequence []dict.DataType
+		expected         []*dict.Data
+	}{
+		{
+			// String data that isn't last doesn't include its null
+			// terminator.
+			name:             "strings",
+			data:             []byte("hoge\x00fuga"),
+			sameTypeSequence: []dict.DataType{dict.PhoneticType, dict.UTFTextType},
+			expected: []*dict.Data{
+				{Type: dict.PhoneticType, Data: []byte("hoge")},
+				{Type: dict.UTFTextType, Data: []byte("fuga")},
+			},
+		},
+		{
+			// The size of the last file data is omitted.
+			name:             "string then file",
+			data:             []byte("hoge\x00\x01\x02"),
+			sameTypeSequence: []dict.DataType{dict.UTFTextType, dict.WavType},
+			expected: []*dict.Data{
+				{Type: dict.UTFTextType, Data: []byte("hoge")},
+				{Type: dict.WavType, Data: []byte{1, 2}},
+			},
+		},
+		{
+			name:             "file then string",
+			data:             []byte("\x00\x00\x00\x02\x01\x02hoge"),
+			sameTypeSequence: []dict.DataType{dict.WavType, dict.UTFTextType},
+			expected: []*dict.Data{
+				{Type: dict.WavType, Data: []byte{1, 2}},
+				{Type: dict.UTFTextType, Data: []byte("hoge")},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "dictionary.dict")
+			if err := os.WriteFile(path, test.data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			d, err := dict.New(f, &dict.Options{
+				SameTypeSequence: test.sameTypeSequence,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer d.Close()
+
+			//nolint:gosec // test data is small.
+			w, err := d.Word(&idx.Word{Word: "hoge", Offset: 0, Size: uint32(len(test.data))})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(&dict.Word{Data: test.expected}, w); diff != "" {
+				t.Fatalf("Dict.Word (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 // TestDict_NewFromIfoPath tests NewFromIfoPath.
 func TestDict_NewFromIfoPath(t *testing.T) {
 	t.Parallel()
@@ -349,7 +619,7 @@ func TestDict_NewFromIfoPath(t *testing.T) {
 			index: &idx.Word{
 				Word:   "hoge",
 				Offset: uint64(0),
-				Size:   uint32(8), // 4 (file size) + 4 data
+				Size:   uint32(4), // 4 data without the file size
 			},
 			expected: &dict.Word{
 				Data: []*dict.Data{
@@ -0,0 +1,314 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dict
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// PangoStyle is the text style of a run of Pango markup text.
+type PangoStyle struct {
+	Bold          bool
+	Italic        bool
+	Underline     bool
+	Strikethrough bool
+	Monospace     bool
+	Superscript   bool
+	Subscript     bool
+
+	// Foreground is the foreground color as given in the markup.
+	Foreground string
+
+	// Background is the background color as given in the markup.
+	Background string
+}
+
+// PangoRun is a run of text with a single style.
+type PangoRun struct {
+	Text  string
+	Style PangoStyle
+}
+
+// PangoMarkup is parsed Pango markup text. The markup is represented as a
+// sequence of runs of text that share the same style.
+type PangoMarkup struct {
+	Runs []PangoRun
+}
+
+var errPango = errors.New("invalid Pango markup")
+
+// ParsePango parses Pango markup text. Parsing is lenient and unknown
+// elements and attributes are ignored.
+func ParsePango(data []byte) (*PangoMarkup, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = false
+	d.AutoClose = []string{"br"}
+	d.Entity = xml.HTMLEntity
+
+	m := &PangoMarkup{}
+	stack := []PangoStyle{{}}
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		// Unclosed elements at the end of the data are implicitly closed.
+		var syntaxErr *xml.SyntaxError
+		if errors.As(err, &syntaxErr) && syntaxErr.Msg == "unexpected EOF" {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errPango, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "br" {
+				m.add("\n", stack[len(stack)-1])
+			}
+			stack = append(stack, pangoElementStyle(stack[len(stack)-1], t))
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			m.add(string(t), stack[len(stack)-1])
+		}
+	}
+
+	return m, nil
+}
+
+// add adds text to the markup merging it with the last run if the style is
+// the same.
+func (m *PangoMarkup) add(text string, style PangoStyle) {
+	if text == "" {
+		return
+	}
+	if len(m.Runs) > 0 && m.Runs[len(m.Runs)-1].Style == style {
+		m.Runs[len(m.Runs)-1].Text += text
+		return
+	}
+	m.Runs = append(m.Runs, PangoRun{
+		Text:  text,
+		Style: style,
+	})
+}
+
+// pangoElementStyle returns the style for the element e within an element
+// with the given style.
+func pangoElementStyle(style PangoStyle, e xml.StartElement) PangoStyle {
+	switch e.Name.Local {
+	case "b":
+		style.Bold = true
+	case "i":
+		style.Italic = true
+	case "u":
+		style.Underline = true
+	case "s":
+		style.Strikethrough = true
+	case "tt":
+		style.Monospace = true
+	case "sup":
+		style.Superscript = true
+	case "sub":
+		style.Subscript = true
+	case "span":
+		for _, a := range e.Attr {
+			pangoSpanAttr(&style, a)
+		}
+	}
+	return style
+}
+
+// pangoSpanAttr updates the style for the given <span> attribute.
+func pangoSpanAttr(style *PangoStyle, a xml.Attr) {
+	v := strings.ToLower(strings.TrimSpace(a.Value))
+	switch a.Name.Local {
+	case "foreground", "fgcolor", "color":
+		style.Foreground = v
+	case "background", "bgcolor":
+		style.Background = v
+	case "weight", "font_weight":
+		switch v {
+		case "bold", "ultrabold", "heavy", "ultraheavy":
+			style.Bold = true
+		case "normal", "light", "ultralight", "book", "thin":
+			style.Bold = false
+		default:
+			if w, err := strconv.Atoi(v); err == nil {
+				style.Bold = w >= 600
+			}
+		}
+	case "style", "font_style":
+		style.Italic = v == "italic" || v == "oblique"
+	case "underline":
+		style.Underline = v != "none"
+	case "strikethrough":
+		style.Strikethrough = v == "true"
+	case "font_family", "face":
+		style.Monospace = v == "monospace"
+	}
+}
+
+// Text returns the markup as plain text with all styling removed.
+func (m *PangoMarkup) Text() string {
+	var b strings.Builder
+	for _, r := range m.Runs {
+		_, _ = b.WriteString(r.Text)
+	}
+	return b.String()
+}
+
+// HTML returns the markup rendered as HTML.
+func (m *PangoMarkup) HTML() string {
+	var b strings.Builder
+	for _, r := range m.Runs {
+		var open, closing []string
+		var css []string
+		if c := cssColor(r.Style.Foreground, ""); c != "" {
+			css = append(css, "color:"+c)
+		}
+		if c := cssColor(r.Style.Background, ""); c != "" {
+			css = append(css, "background-color:"+c)
+		}
+		if len(css) > 0 {
+			open = append(open, `<span style="`+html.EscapeString(strings.Join(css, ";"))+`">`)
+			closing = append(closing, "</span>")
+		}
+		for _, t := range []struct {
+			set bool
+			tag string
+		}{
+			{r.Style.Bold, "b"},
+			{r.Style.Italic, "i"},
+			{r.Style.Underline, "u"},
+			{r.Style.Strikethrough, "s"},
+			{r.Style.Monospace, "code"},
+			{r.Style.Superscript, "sup"},
+			{r.Style.Subscript, "sub"},
+		} {
+			if t.set {
+				open = append(open, "<"+t.tag+">")
+				closing = append(closing, "</"+t.tag+">")
+			}
+		}
+
+		for _, s := range open {
+			_, _ = b.WriteString(s)
+		}
+		_, _ = b.WriteString(strings.ReplaceAll(html.EscapeString(r.Text), "\n", "<br>"))
+		for i := len(closing) - 1; i >= 0; i-- {
+			_, _ = b.WriteString(closing[i])
+		}
+	}
+	return b.String()
+}
+
+// ANSI returns the markup rendered as text with ANSI escape sequences for
+// display in a terminal. Colors are rendered as 24-bit color sequences.
+// Styles that cannot be displayed in a terminal are ignored.
+func (m *PangoMarkup) ANSI() string {
+	var b strings.Builder
+	for _, r := range m.Runs {
+		var codes []string
+		if r.Style.Bold {
+			codes = append(codes, "1")
+		}
+		if r.Style.Italic {
+			codes = append(codes, "3")
+		}
+		if r.Style.Underline {
+			codes = append(codes, "4")
+		}
+		if r.Style.Strikethrough {
+			codes = append(codes, "9")
+		}
+		if rgb, ok := parseColor(r.Style.Foreground); ok {
+			codes = append(codes, fmt.Sprintf("38;2;%d;%d;%d", rgb[0], rgb[1], rgb[2]))
+		}
+		if rgb, ok := parseColor(r.Style.Background); ok {
+			codes = append(codes, fmt.Sprintf("48;2;%d;%d;%d", rgb[0], rgb[1], rgb[2]))
+		}
+
+		if len(codes) == 0 {
+			_, _ = b.WriteString(r.Text)
+			continue
+		}
+		_, _ = b.WriteString("\x1b[" + strings.Join(codes, ";") + "m")
+		_, _ = b.WriteString(r.Text)
+		_, _ = b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
+
+// namedColors are common color names supported by Pango.
+var namedColors = map[string][3]uint8{
+	"black":     {0x00, 0x00, 0x00},
+	"white":     {0xff, 0xff, 0xff},
+	"red":       {0xff, 0x00, 0x00},
+	"green":     {0x00, 0xff, 0x00},
+	"blue":      {0x00, 0x00, 0xff},
+	"yellow":    {0xff, 0xff, 0x00},
+	"cyan":      {0x00, 0xff, 0xff},
+	"magenta":   {0xff, 0x00, 0xff},
+	"gray":      {0xbe, 0xbe, 0xbe},
+	"grey":      {0xbe, 0xbe, 0xbe},
+	"brown":     {0xa5, 0x2a, 0x2a},
+	"orange":    {0xff, 0xa5, 0x00},
+	"purple":    {0xa0, 0x20, 0xf0},
+	"navy":      {0x00, 0x00, 0x80},
+	"maroon":    {0xb0, 0x30, 0x60},
+	"darkred":   {0x8b, 0x00, 0x00},
+	"darkgreen": {0x00, 0x64, 0x00},
+	"darkblue":  {0x00, 0x00, 0x8b},
+}
+
+// parseColor parses a Pango color specification. Colors may be a color name
+// or a hex value in the form #rgb, #rrggbb, #rrrgggbbb, or #rrrrggggbbbb.
+func parseColor(c string) ([3]uint8, bool) {
+	if rgb, ok := namedColors[c]; ok {
+		return rgb, true
+	}
+
+	hex, ok := strings.CutPrefix(c, "#")
+	if !ok || len(hex) == 0 || len(hex)%3 != 0 || len(hex) > 12 {
+		return [3]uint8{}, false
+	}
+	n := len(hex) / 3
+	var rgb [3]uint8
+	for i := range rgb {
+		v, err := strconv.ParseUint(hex[i*n:(i+1)*n], 16, 16)
+		if err != nil {
+			return [3]uint8{}, false
+		}
+		// Scale the value to 8 bits.
+		switch n {
+		case 1:
+			v *= 0x11
+		default:
+			v >>= 4 * (n - 2)
+		}
+		rgb[i] = uint8(v)
+	}
+	return rgb, true
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dict_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/dict"
+)
+
+// TestParsePango tests ParsePango.
+func TestParsePango(t *testing.T) {
+	t.Parallel()
+
+	m, err := dict.ParsePango([]byte(
+		`<span foreground="#00f" weight="bold">cat</span> <i>n.</i><i> neko</i>` +
+			`<span style="italic"><sup>1</sup></span>`,
+	))
+	if err != nil {
+		t.Fatalf("ParsePango: %v", err)
+	}
+
+	expected := &dict.PangoMarkup{
+		Runs: []dict.PangoRun{
+			{Text: "cat", Style: dict.PangoStyle{Bold: true, Foreground: "#00f"}},
+			{Text: " "},
+			{Text: "n. neko", Style: dict.PangoStyle{Italic: true}},
+			{Text: "1", Style: dict.PangoStyle{Italic: true, Superscript: true}},
+		},
+	}
+	if diff := cmp.Diff(expected, m); diff != "" {
+		t.Errorf("ParsePango (-want, +got):\n%s", diff)
+	}
+}
+
+// TestPangoMarkup tests rendering Pango markup.
+func TestPangoMarkup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data string
+
+		text string
+		html string
+		ansi string
+	}{
+		{
+			name: "plain text",
+			data: "some text",
+			text: "some text",
+			html: "some text",
+			ansi: "some text",
+		},
+		{
+			name: "bold and italic",
+			data: "<b>bold</b> <i>italic</i>",
+			text: "bold italic",
+			html: "<b>bold</b> <i>italic</i>",
+			ansi: "\x1b[1mbold\x1b[0m \x1b[3mitalic\x1b[0m",
+		},
+		{
+			name: "nested",
+			data: "<b><u>both</u></b>",
+			text: "both",
+			html: "<b><u>both</u></b>",
+			ansi: "\x1b[1;4mboth\x1b[0m",
+		},
+		{
+			name: "foreground color",
+			data: `<span foreground="red">red</span> <span fgcolor="#336699">blue</span>`,
+			text: "red blue",
+			html: `<span style="color:red">red</span> <span style="color:#336699">blue</span>`,
+			ansi: "\x1b[38;2;255;0;0mred\x1b[0m \x1b[38;2;51;102;153mblue\x1b[0m",
+		},
+		{
+			name: "background color",
+			data: `<span background="#ffff00000000">hi</span>`,
+			text: "hi",
+			html: `<span style="background-color:#ffff00000000">hi</span>`,
+			ansi: "\x1b[48;2;255;0;0mhi\x1b[0m",
+		},
+		{
+			name: "invalid color",
+			data: `<span foreground="red;x:url(y)">text</span>`,
+			text: "text",
+			html: "text",
+			ansi: "text",
+		},
+		{
+			name: "superscript",
+			data: "x<sup>2</sup>",
+			text: "x2",
+			html: "x<sup>2</sup>",
+			ansi: "x2",
+		},
+		{
+			name: "escaping",
+			data: "a &lt; b &amp; c",
+			text: "a < b & c",
+			html: "a &lt; b &amp; c",
+			ansi: "a < b & c",
+		},
+		{
+			name: "newlines",
+			data: "a\n<b>b</b>",
+			text: "a\nb",
+			html: "a<br><b>b</b>",
+			ansi: "a\n\x1b[1mb\x1b[0m",
+		},
+		{
+			name: "unclosed",
+			data: "<b>bold",
+			text: "bold",
+			html: "<b>bold</b>",
+			ansi: "\x1b[1mbold\x1b[0m",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := dict.ParsePango([]byte(test.data))
+			if err != nil {
+				t.Fatalf("ParsePango: %v", err)
+			}
+
+			if diff := cmp.Diff(test.text, m.Text()); diff != "" {
+				t.Errorf("Text (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.html, m.HTML()); diff != "" {
+				t.Errorf("HTML (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.ansi, m.ANSI()); diff != "" {
+				t.Errorf("ANSI (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
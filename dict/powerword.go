@@ -0,0 +1,244 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dict
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PowerWord XML element names.
+const (
+	// pwHeadword is the headword (单词原型).
+	pwHeadword = "单词原型"
+
+	// pwPhonetic is the dictionary phonetic (词典音标).
+	pwPhonetic = "词典音标"
+
+	// pwWordPhonetic is the word phonetic (单词音标).
+	pwWordPhonetic = "单词音标"
+
+	// pwPartOfSpeech is the part of speech (单词词性).
+	pwPartOfSpeech = "单词词性"
+
+	// pwDefinition is a definition (解释项).
+	pwDefinition = "解释项"
+
+	// pwSubDefinition is a sub-definition (子解释项).
+	pwSubDefinition = "子解释项"
+
+	// pwPreDefinition is a preliminary definition (预解释).
+	pwPreDefinition = "预解释"
+
+	// pwFollowingDefinition is a following definition (跟随解释).
+	pwFollowingDefinition = "跟随解释"
+
+	// pwExample is an example sentence (例句原型).
+	pwExample = "例句原型"
+
+	// pwExampleTranslation is an example sentence's translation (例句解释).
+	pwExampleTranslation = "例句解释"
+)
+
+// PowerWordExample is an example sentence.
+type PowerWordExample struct {
+	// Text is the example sentence.
+	Text string
+
+	// Translation is the translation of the example sentence.
+	Translation string
+}
+
+// PowerWordSense is a single sense of a PowerWord entry.
+type PowerWordSense struct {
+	// PartOfSpeech is the part of speech for the sense.
+	PartOfSpeech string
+
+	// Definition is the definition text.
+	Definition string
+
+	// Examples are example sentences for the sense.
+	Examples []*PowerWordExample
+}
+
+// PowerWordEntry is a decoded KingSoft PowerWord XML entry.
+type PowerWordEntry struct {
+	// Headword is the entry's headword.
+	Headword string
+
+	// Phonetics are the phonetic transcriptions of the headword.
+	Phonetics []string
+
+	// Senses are the senses of the headword.
+	Senses []*PowerWordSense
+}
+
+var errPowerWord = errors.New("invalid PowerWord data")
+
+// ParsePowerWord decodes KingSoft PowerWord XML data. Unknown elements are
+// ignored. The text of known elements may be given in CDATA sections.
+func ParsePowerWord(data []byte) (*PowerWordEntry, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = false
+	d.Entity = xml.HTMLEntity
+
+	e := &PowerWordEntry{}
+	var partOfSpeech string
+	// field is the known element whose text is being read.
+	var field string
+	var text strings.Builder
+
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		// Unclosed elements at the end of the data are implicitly closed.
+		var syntaxErr *xml.SyntaxError
+		if errors.As(err, &syntaxErr) && syntaxErr.Msg == "unexpected EOF" {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errPowerWord, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if field == "" && isPowerWordField(t.Name.Local) {
+				field = t.Name.Local
+				text.Reset()
+			}
+		case xml.CharData:
+			if field != "" {
+				_, _ = text.Write(t)
+			}
+		case xml.EndElement:
+			if t.Name.Local == field {
+				partOfSpeech = e.add(field, strings.TrimSpace(text.String()), partOfSpeech)
+				field = ""
+			}
+		}
+	}
+	if field != "" {
+		e.add(field, strings.TrimSpace(text.String()), partOfSpeech)
+	}
+
+	return e, nil
+}
+
+// isPowerWordField returns true if name is a known PowerWord element.
+func isPowerWordField(name string) bool {
+	switch name {
+	case pwHeadword, pwPhonetic, pwWordPhonetic, pwPartOfSpeech, pwDefinition,
+		pwSubDefinition, pwPreDefinition, pwFollowingDefinition, pwExample,
+		pwExampleTranslation:
+		return true
+	default:
+		return false
+	}
+}
+
+// add adds the text for the field to the entry and returns the current part
+// of speech.
+func (e *PowerWordEntry) add(field, text, partOfSpeech string) string {
+	if text == "" {
+		return partOfSpeech
+	}
+
+	switch field {
+	case pwHeadword:
+		if e.Headword == "" {
+			e.Headword = text
+		}
+	case pwPhonetic, pwWordPhonetic:
+		e.Phonetics = append(e.Phonetics, text)
+	case pwPartOfSpeech:
+		return text
+	case pwDefinition, pwSubDefinition, pwPreDefinition, pwFollowingDefinition:
+		e.Senses = append(e.Senses, &PowerWordSense{
+			PartOfSpeech: partOfSpeech,
+			Definition:   text,
+		})
+	case pwExample:
+		s := e.lastSense(partOfSpeech)
+		s.Examples = append(s.Examples, &PowerWordExample{
+			Text: text,
+		})
+	case pwExampleTranslation:
+		s := e.lastSense(partOfSpeech)
+		if len(s.Examples) == 0 || s.Examples[len(s.Examples)-1].Translation != "" {
+			s.Examples = append(s.Examples, &PowerWordExample{})
+		}
+		s.Examples[len(s.Examples)-1].Translation = text
+	}
+
+	return partOfSpeech
+}
+
+// lastSense returns the last sense in the entry, adding an empty sense if
+// there are none.
+func (e *PowerWordEntry) lastSense(partOfSpeech string) *PowerWordSense {
+	if len(e.Senses) == 0 {
+		e.Senses = append(e.Senses, &PowerWordSense{
+			PartOfSpeech: partOfSpeech,
+		})
+	}
+	return e.Senses[len(e.Senses)-1]
+}
+
+// Text returns the entry as plain text. The headword and phonetics are
+// written on the first line followed by each sense on its own line. Examples
+// are indented below their sense.
+func (e *PowerWordEntry) Text() string {
+	var lines []string
+
+	var head []string
+	if e.Headword != "" {
+		head = append(head, e.Headword)
+	}
+	for _, p := range e.Phonetics {
+		head = append(head, "["+p+"]")
+	}
+	if len(head) > 0 {
+		lines = append(lines, strings.Join(head, " "))
+	}
+
+	for _, s := range e.Senses {
+		var sense []string
+		if s.PartOfSpeech != "" {
+			sense = append(sense, s.PartOfSpeech)
+		}
+		if s.Definition != "" {
+			sense = append(sense, s.Definition)
+		}
+		if len(sense) > 0 {
+			lines = append(lines, strings.Join(sense, " "))
+		}
+		for _, ex := range s.Examples {
+			if ex.Text != "" {
+				lines = append(lines, "  "+ex.Text)
+			}
+			if ex.Translation != "" {
+				lines = append(lines, "  "+ex.Translation)
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
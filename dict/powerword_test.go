@@ -0,0 +1,121 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dict_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/dict"
+)
+
+// TestParsePowerWord tests ParsePowerWord.
+func TestParsePowerWord(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data string
+
+		expected *dict.PowerWordEntry
+		text     string
+	}{
+		{
+			name: "full entry",
+			data: `<基本词义><单词原型><![CDATA[hello]]></单词原型>` +
+				`<单词音标><![CDATA[hә'lәu]]></单词音标>` +
+				`<单词词性><![CDATA[int.]]></单词词性>` +
+				`<解释项><![CDATA[喂]]></解释项>` +
+				`<例句原型><![CDATA[Hello, John!]]></例句原型>` +
+				`<例句解释><![CDATA[喂，约翰！]]></例句解释>` +
+				`<单词词性><![CDATA[n.]]></单词词性>` +
+				`<解释项><![CDATA[招呼]]></解释项></基本词义>`,
+			expected: &dict.PowerWordEntry{
+				Headword:  "hello",
+				Phonetics: []string{"hә'lәu"},
+				Senses: []*dict.PowerWordSense{
+					{
+						PartOfSpeech: "int.",
+						Definition:   "喂",
+						Examples: []*dict.PowerWordExample{
+							{
+								Text:        "Hello, John!",
+								Translation: "喂，约翰！",
+							},
+						},
+					},
+					{
+						PartOfSpeech: "n.",
+						Definition:   "招呼",
+					},
+				},
+			},
+			text: "hello [hә'lәu]\nint. 喂\n  Hello, John!\n  喂，约翰！\nn. 招呼",
+		},
+		{
+			name: "example without sense",
+			data: `<例句原型>foo</例句原型><例句解释>bar</例句解释><例句解释>baz</例句解释>`,
+			expected: &dict.PowerWordEntry{
+				Senses: []*dict.PowerWordSense{
+					{
+						Examples: []*dict.PowerWordExample{
+							{Text: "foo", Translation: "bar"},
+							{Translation: "baz"},
+						},
+					},
+				},
+			},
+			text: "  foo\n  bar\n  baz",
+		},
+		{
+			name: "unknown elements",
+			data: `<相关词>ignored</相关词><单词原型>hoge</单词原型>`,
+			expected: &dict.PowerWordEntry{
+				Headword: "hoge",
+			},
+			text: "hoge",
+		},
+		{
+			name: "unclosed",
+			data: `<单词原型>hoge</单词原型><解释项>fuga`,
+			expected: &dict.PowerWordEntry{
+				Headword: "hoge",
+				Senses: []*dict.PowerWordSense{
+					{Definition: "fuga"},
+				},
+			},
+			text: "hoge\nfuga",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			e, err := dict.ParsePowerWord([]byte(test.data))
+			if err != nil {
+				t.Fatalf("ParsePowerWord: %v", err)
+			}
+
+			if diff := cmp.Diff(test.expected, e); diff != "" {
+				t.Errorf("ParsePowerWord (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.text, e.Text()); diff != "" {
+				t.Errorf("Text (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
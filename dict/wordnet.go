@@ -0,0 +1,229 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dict
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WordNetRelation is a type of semantic relation between WordNet synsets.
+type WordNetRelation string
+
+const (
+	// Hypernym relates a synset to a more general synset.
+	Hypernym WordNetRelation = "hypernym"
+
+	// Hyponym relates a synset to a more specific synset.
+	Hyponym WordNetRelation = "hyponym"
+
+	// Antonym relates a synset to one with the opposite meaning.
+	Antonym WordNetRelation = "antonym"
+
+	// Meronym relates a synset to a synset that is a part of it.
+	Meronym WordNetRelation = "meronym"
+
+	// Holonym relates a synset to a synset that it is a part of.
+	Holonym WordNetRelation = "holonym"
+
+	// Similar relates an adjective synset to a similar adjective synset.
+	Similar WordNetRelation = "similar"
+
+	// SeeAlso relates a synset to a related synset.
+	SeeAlso WordNetRelation = "also"
+
+	// Entailment relates a verb synset to a verb synset that it entails.
+	Entailment WordNetRelation = "entailment"
+
+	// Cause relates a verb synset to a verb synset that it causes.
+	Cause WordNetRelation = "cause"
+
+	// Derivation relates a synset to a morphologically related synset.
+	Derivation WordNetRelation = "derivation"
+
+	// Pertainym relates an adjective synset to the noun synset it pertains to.
+	Pertainym WordNetRelation = "pertainym"
+)
+
+// wordNetRelations are the supported relation element names.
+var wordNetRelations = map[string]WordNetRelation{
+	string(Hypernym):   Hypernym,
+	string(Hyponym):    Hyponym,
+	string(Antonym):    Antonym,
+	string(Meronym):    Meronym,
+	string(Holonym):    Holonym,
+	string(Similar):    Similar,
+	string(SeeAlso):    SeeAlso,
+	string(Entailment): Entailment,
+	string(Cause):      Cause,
+	string(Derivation): Derivation,
+	string(Pertainym):  Pertainym,
+}
+
+// WordNetPointer is a relation from a synset to another word.
+type WordNetPointer struct {
+	// Relation is the type of relation.
+	Relation WordNetRelation
+
+	// Word is the related word. The word can be looked up in the dictionary
+	// index.
+	Word string
+}
+
+// WordNetSynset is a WordNet synonym set.
+type WordNetSynset struct {
+	// PartOfSpeech is the synset's part of speech. It is one of "n" (noun),
+	// "v" (verb), "a" (adjective), "s" (adjective satellite), or "r"
+	// (adverb).
+	PartOfSpeech string
+
+	// Words are the words in the synset.
+	Words []string
+
+	// Gloss is the definition and example sentences for the synset.
+	Gloss string
+
+	// Pointers are relations to other words.
+	Pointers []*WordNetPointer
+}
+
+// Related returns the words related to the synset by the given relation.
+func (s *WordNetSynset) Related(rel WordNetRelation) []string {
+	var words []string
+	for _, p := range s.Pointers {
+		if p.Relation == rel {
+			words = append(words, p.Word)
+		}
+	}
+	return words
+}
+
+// WordNetEntry is decoded WordNet data.
+type WordNetEntry struct {
+	// Synsets are the synsets for the entry.
+	Synsets []*WordNetSynset
+}
+
+var errWordNet = errors.New("invalid WordNet data")
+
+// ParseWordNet decodes WordNet data as written by the StarDict WordNet
+// converter. Each synset is given by a <type> element with the part of
+// speech, a <wordgroup> element containing <word> elements, and a <gloss>
+// element. Relations are given by elements named after the relation (e.g.
+// <hypernym>) that contain the related word. A new synset is started by each
+// <type> element, or by a <wordgroup> element following a <gloss>.
+func ParseWordNet(data []byte) (*WordNetEntry, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = false
+	d.Entity = xml.HTMLEntity
+
+	e := &WordNetEntry{}
+	var cur *WordNetSynset
+	// field is the element whose text is being read.
+	var field string
+	var text strings.Builder
+
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		// Unclosed elements at the end of the data are implicitly closed.
+		var syntaxErr *xml.SyntaxError
+		if errors.As(err, &syntaxErr) && syntaxErr.Msg == "unexpected EOF" {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errWordNet, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := t.Name.Local
+			if cur == nil || name == "type" || (name == "wordgroup" && cur.Gloss != "") {
+				cur = &WordNetSynset{}
+				e.Synsets = append(e.Synsets, cur)
+			}
+			if _, ok := wordNetRelations[name]; ok || name == "type" || name == "word" || name == "gloss" {
+				field = name
+				text.Reset()
+			}
+		case xml.CharData:
+			if field != "" {
+				_, _ = text.Write(t)
+			}
+		case xml.EndElement:
+			if t.Name.Local == field {
+				cur.add(field, strings.TrimSpace(text.String()))
+				field = ""
+			}
+		}
+	}
+	if field != "" {
+		cur.add(field, strings.TrimSpace(text.String()))
+	}
+
+	return e, nil
+}
+
+// add adds the text for the given field to the synset.
+func (s *WordNetSynset) add(field, text string) {
+	if text == "" {
+		return
+	}
+
+	switch field {
+	case "type":
+		s.PartOfSpeech = text
+	case "word":
+		s.Words = append(s.Words, text)
+	case "gloss":
+		s.Gloss = text
+	default:
+		s.Pointers = append(s.Pointers, &WordNetPointer{
+			Relation: wordNetRelations[field],
+			Word:     text,
+		})
+	}
+}
+
+// Text returns the entry as plain text. Each synset is written with its part
+// of speech and words on the first line followed by the gloss and relations.
+func (e *WordNetEntry) Text() string {
+	var lines []string
+	for _, s := range e.Synsets {
+		var head []string
+		if s.PartOfSpeech != "" {
+			head = append(head, s.PartOfSpeech+".")
+		}
+		if len(s.Words) > 0 {
+			head = append(head, strings.Join(s.Words, ", "))
+		}
+		if len(head) > 0 {
+			lines = append(lines, strings.Join(head, " "))
+		}
+		if s.Gloss != "" {
+			lines = append(lines, "  "+s.Gloss)
+		}
+		for _, p := range s.Pointers {
+			lines = append(lines, "  "+string(p.Relation)+": "+p.Word)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dict_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/dict"
+)
+
+// TestParseWordNet tests ParseWordNet.
+func TestParseWordNet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data string
+
+		expected *dict.WordNetEntry
+		text     string
+	}{
+		{
+			name: "single synset",
+			data: "<type>n</type><wordgroup><word>dog</word><word>domestic dog</word></wordgroup>" +
+				"<gloss>a member of the genus Canis</gloss>" +
+				"<hypernym>canine</hypernym><meronym>flag</meronym>",
+			expected: &dict.WordNetEntry{
+				Synsets: []*dict.WordNetSynset{
+					{
+						PartOfSpeech: "n",
+						Words:        []string{"dog", "domestic dog"},
+						Gloss:        "a member of the genus Canis",
+						Pointers: []*dict.WordNetPointer{
+							{Relation: dict.Hypernym, Word: "canine"},
+							{Relation: dict.Meronym, Word: "flag"},
+						},
+					},
+				},
+			},
+			text: "n. dog, domestic dog\n  a member of the genus Canis\n  hypernym: canine\n  meronym: flag",
+		},
+		{
+			name: "multiple synsets",
+			data: "<type>a</type><wordgroup><word>good</word></wordgroup><gloss>having desirable qualities</gloss>" +
+				"<antonym>bad</antonym>" +
+				"<wordgroup><word>full</word></wordgroup><gloss>having the normally expected amount</gloss>",
+			expected: &dict.WordNetEntry{
+				Synsets: []*dict.WordNetSynset{
+					{
+						PartOfSpeech: "a",
+						Words:        []string{"good"},
+						Gloss:        "having desirable qualities",
+						Pointers: []*dict.WordNetPointer{
+							{Relation: dict.Antonym, Word: "bad"},
+						},
+					},
+					{
+						Words: []string{"full"},
+						Gloss: "having the normally expected amount",
+					},
+				},
+			},
+			text: "a. good\n  having desirable qualities\n  antonym: bad\nfull\n  having the normally expected amount",
+		},
+		{
+			name: "unknown elements",
+			data: "<type>v</type><foo>bar</foo><gloss>run fast</gloss>",
+			expected: &dict.WordNetEntry{
+				Synsets: []*dict.WordNetSynset{
+					{
+						PartOfSpeech: "v",
+						Gloss:        "run fast",
+					},
+				},
+			},
+			text: "v.\n  run fast",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			e, err := dict.ParseWordNet([]byte(test.data))
+			if err != nil {
+				t.Fatalf("ParseWordNet: %v", err)
+			}
+
+			if diff := cmp.Diff(test.expected, e); diff != "" {
+				t.Errorf("ParseWordNet (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.text, e.Text()); diff != "" {
+				t.Errorf("Text (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestWordNetSynset_Related tests WordNetSynset.Related.
+func TestWordNetSynset_Related(t *testing.T) {
+	t.Parallel()
+
+	s := &dict.WordNetSynset{
+		Pointers: []*dict.WordNetPointer{
+			{Relation: dict.Hypernym, Word: "canine"},
+			{Relation: dict.Hyponym, Word: "puppy"},
+			{Relation: dict.Hyponym, Word: "hound"},
+		},
+	}
+
+	if diff := cmp.Diff([]string{"puppy", "hound"}, s.Related(dict.Hyponym)); diff != "" {
+		t.Errorf("Related (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string(nil), s.Related(dict.Antonym)); diff != "" {
+		t.Errorf("Related (-want, +got):\n%s", diff)
+	}
+}
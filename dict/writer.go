@@ -0,0 +1,195 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dict
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/ianlewis/go-dictzip"
+
+	"github.com/ianlewis/go-stardict/idx"
+)
+
+var (
+	// ErrInvalidData indicates that word data cannot be written to a .dict
+	// file.
+	ErrInvalidData = errors.New("invalid data")
+
+	// ErrWriterClosed indicates that the Writer has already been closed.
+	ErrWriterClosed = errors.New("writer closed")
+)
+
+// WriterOptions are options for a Writer.
+type WriterOptions struct {
+	// SameTypeSequence is the sequence of data types for every word. If set,
+	// the data types are not written and the last data is written without a
+	// null terminator or size. This is equivalent to the sametypesequence
+	// option in the .ifo file.
+	SameTypeSequence []DataType
+
+	// DictZip indicates that the output should be compressed with dictzip.
+	DictZip bool
+}
+
+// Writer writes .dict files.
+type Writer struct {
+	w                io.Writer
+	dz               *dictzip.Writer
+	sametypesequence []DataType
+	offset           uint64
+	closed           bool
+}
+
+// NewWriter returns a new Writer that writes the .dict file to w. The Close
+// method must be called to finish writing the file.
+func NewWriter(w io.Writer, options *WriterOptions) (*Writer, error) {
+	if options == nil {
+		options = &WriterOptions{}
+	}
+	for _, t := range options.SameTypeSequence {
+		if !t.valid() {
+			return nil, fmt.Errorf("%w: %v", errInvalidType, t)
+		}
+	}
+
+	dw := &Writer{
+		w:                w,
+		sametypesequence: options.SameTypeSequence,
+	}
+	if options.DictZip {
+		z, err := dictzip.NewWriter(w)
+		if err != nil {
+			return nil, fmt.Errorf("creating dictzip writer: %w", err)
+		}
+		dw.dz = z
+		dw.w = z
+	}
+	return dw, nil
+}
+
+// Add writes the word's data to the .dict file and returns an index entry
+// with the offset and size of the data. The caller should set the index
+// entry's Word before adding it to an index.
+func (w *Writer) Add(word *Word) (*idx.Word, error) {
+	if w.closed {
+		return nil, ErrWriterClosed
+	}
+
+	b, err := w.encode(word)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > math.MaxUint32 {
+		return nil, fmt.Errorf("%w: word data too large: %d", ErrInvalidData, len(b))
+	}
+
+	if _, err := w.w.Write(b); err != nil {
+		return nil, fmt.Errorf("writing dict: %w", err)
+	}
+
+	e := &idx.Word{
+		Offset: w.offset,
+		Size:   uint32(len(b)),
+	}
+	w.offset += uint64(len(b))
+	return e, nil
+}
+
+// encode encodes the word's data.
+func (w *Writer) encode(word *Word) ([]byte, error) {
+	var b bytes.Buffer
+	if len(w.sametypesequence) > 0 {
+		if len(word.Data) != len(w.sametypesequence) {
+			return nil, fmt.Errorf("%w: expected %d data for sametypesequence, got %d",
+				ErrInvalidData, len(w.sametypesequence), len(word.Data))
+		}
+		for i, d := range word.Data {
+			if d.Type != w.sametypesequence[i] {
+				return nil, fmt.Errorf("%w: expected type %q, got %q", ErrInvalidData, w.sametypesequence[i], d.Type)
+			}
+			// The last data has no null terminator or size.
+			if err := encodeData(&b, d, i == len(word.Data)-1); err != nil {
+				return nil, err
+			}
+		}
+		return b.Bytes(), nil
+	}
+
+	for _, d := range word.Data {
+		if !d.Type.valid() {
+			return nil, fmt.Errorf("%w: %v", errInvalidType, d.Type)
+		}
+		_ = b.WriteByte(byte(d.Type))
+		if err := encodeData(&b, d, false); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// encodeData encodes the data without its type. String-like data is written
+// followed by a null terminator and file-like data is written preceded by its
+// size. The null terminator or size is omitted if the data is the last data
+// in a sametypesequence entry.
+func encodeData(b *bytes.Buffer, d *Data, last bool) error {
+	if d.Type.isString() {
+		if bytes.IndexByte(d.Data, 0) >= 0 {
+			return fmt.Errorf("%w: %q data contains null byte", ErrInvalidData, d.Type)
+		}
+		_, _ = b.Write(d.Data)
+		if !last {
+			_ = b.WriteByte(0)
+		}
+		return nil
+	}
+
+	if len(d.Data) > math.MaxUint32 {
+		return fmt.Errorf("%w: %q data too large: %d", ErrInvalidData, d.Type, len(d.Data))
+	}
+	if !last {
+		var size [4]byte
+		binary.BigEndian.PutUint32(size[:], uint32(len(d.Data)))
+		_, _ = b.Write(size[:])
+	}
+	_, _ = b.Write(d.Data)
+	return nil
+}
+
+// Size returns the uncompressed size of the data written.
+func (w *Writer) Size() int64 {
+	//nolint:gosec // offset is the sum of written slice lengths.
+	return int64(w.offset)
+}
+
+// Close finishes writing the .dict file. If the output is compressed the
+// dictzip data is flushed. Close does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return ErrWriterClosed
+	}
+	w.closed = true
+
+	if w.dz != nil {
+		if err := w.dz.Close(); err != nil {
+			return fmt.Errorf("closing dictzip writer: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dict_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/idx"
+)
+
+// TestWriter tests that words written with Writer can be read with Dict.
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		sameTypeSequence []dict.DataType
+		words            []*dict.Word
+		expected         []byte
+	}{
+		{
+			name: "typed",
+			words: []*dict.Word{
+				{
+					Data: []*dict.Data{
+						{Type: dict.UTFTextType, Data: []byte("hoge")},
+						{Type: dict.WavType, Data: []byte{1, 2}},
+					},
+				},
+				{
+					Data: []*dict.Data{
+						{Type: dict.PhoneticType, Data: []byte("fuga")},
+					},
+				},
+			},
+			expected: []byte("mhoge\x00W\x00\x00\x00\x02\x01\x02tfuga\x00"),
+		},
+		{
+			name:             "sametypesequence",
+			sameTypeSequence: []dict.DataType{dict.PhoneticType, dict.UTFTextType},
+			words: []*dict.Word{
+				{
+					Data: []*dict.Data{
+						{Type: dict.PhoneticType, Data: []byte("hoge")},
+						{Type: dict.UTFTextType, Data: []byte("fuga")},
+					},
+				},
+				{
+					Data: []*dict.Data{
+						{Type: dict.PhoneticType, Data: []byte("foo")},
+						{Type: dict.UTFTextType, Data: []byte("bar")},
+					},
+				},
+			},
+			// The last string data has no null terminator.
+			expected: []byte("hoge\x00fugafoo\x00bar"),
+		},
+		{
+			name:             "sametypesequence file",
+			sameTypeSequence: []dict.DataType{dict.UTFTextType, dict.PictureType},
+			words: []*dict.Word{
+				{
+					Data: []*dict.Data{
+						{Type: dict.UTFTextType, Data: []byte("hoge")},
+						{Type: dict.PictureType, Data: []byte{1}},
+					},
+				},
+			},
+			// The size of the last file data is omitted.
+			expected: []byte("hoge\x00\x01"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			w, err := dict.NewWriter(&buf, &dict.WriterOptions{
+				SameTypeSequence: test.sameTypeSequence,
+			})
+			if err != nil {
+				t.Fatalf("NewWriter: %v", err)
+			}
+
+			var entries []*idx.Word
+			for _, word := range test.words {
+				e, err := w.Add(word)
+				if err != nil {
+					t.Fatalf("Add: %v", err)
+				}
+				entries = append(entries, e)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			if diff := cmp.Diff(test.expected, buf.Bytes()); diff != "" {
+				t.Errorf("data (-want, +got):\n%s", diff)
+			}
+			if got, want := w.Size(), int64(buf.Len()); got != want {
+				t.Errorf("Size: expected %d, got %d", want, got)
+			}
+
+			d, err := dict.New(readerAtCloser{bytes.NewReader(buf.Bytes())}, &dict.Options{
+				SameTypeSequence: test.sameTypeSequence,
+			})
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			for i, e := range entries {
+				word, err := d.Word(e)
+				if err != nil {
+					t.Fatalf("Word: %v", err)
+				}
+				if diff := cmp.Diff(test.words[i], word); diff != "" {
+					t.Errorf("Word (-want, +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+// TestWriter_Add_invalid tests Writer.Add with invalid data.
+func TestWriter_Add_invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		sameTypeSequence []dict.DataType
+		word             *dict.Word
+	}{
+		{
+			name: "invalid type",
+			word: &dict.Word{
+				Data: []*dict.Data{{Type: dict.DataType('!')}},
+			},
+		},
+		{
+			name: "null byte",
+			word: &dict.Word{
+				Data: []*dict.Data{{Type: dict.UTFTextType, Data: []byte("a\x00b")}},
+			},
+		},
+		{
+			name:             "sametypesequence length",
+			sameTypeSequence: []dict.DataType{dict.UTFTextType, dict.PhoneticType},
+			word: &dict.Word{
+				Data: []*dict.Data{{Type: dict.UTFTextType, Data: []byte("a")}},
+			},
+		},
+		{
+			name:             "sametypesequence type",
+			sameTypeSequence: []dict.DataType{dict.UTFTextType},
+			word: &dict.Word{
+				Data: []*dict.Data{{Type: dict.HTMLType, Data: []byte("a")}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			w, err := dict.NewWriter(&bytes.Buffer{}, &dict.WriterOptions{
+				SameTypeSequence: test.sameTypeSequence,
+			})
+			if err != nil {
+				t.Fatalf("NewWriter: %v", err)
+			}
+			if _, err := w.Add(test.word); err == nil {
+				t.Fatal("Add: expected error")
+			}
+		})
+	}
+}
+
+// TestWriter_closed tests that a Writer can't be used after Close.
+func TestWriter_closed(t *testing.T) {
+	t.Parallel()
+
+	w, err := dict.NewWriter(&bytes.Buffer{}, nil)
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := w.Add(&dict.Word{}); !errors.Is(err, dict.ErrWriterClosed) {
+		t.Errorf("Add: expected %v, got %v", dict.ErrWriterClosed, err)
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dict
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// XDXFNodeType is the type of a node in an XDXF article.
+type XDXFNodeType int
+
+const (
+	// XDXFTextNode is a text node.
+	XDXFTextNode XDXFNodeType = iota
+
+	// XDXFKeyNode is a headword (<k>).
+	XDXFKeyNode
+
+	// XDXFTranscriptionNode is a transcription (<tr>).
+	XDXFTranscriptionNode
+
+	// XDXFTranslationNode is a translation (<dtrn>).
+	XDXFTranslationNode
+
+	// XDXFExampleNode is an example (<ex>).
+	XDXFExampleNode
+
+	// XDXFReferenceNode is a reference to another headword (<kref>).
+	XDXFReferenceNode
+
+	// XDXFAbbreviationNode is an abbreviation (<abr>).
+	XDXFAbbreviationNode
+
+	// XDXFColorNode is colored text (<c>). The color is stored in the node's
+	// Attr field.
+	XDXFColorNode
+
+	// XDXFElementNode is an element that is not otherwise supported. Its
+	// children are rendered without formatting.
+	XDXFElementNode
+)
+
+// xdxfDefaultColor is the color used for <c> elements without a c attribute.
+const xdxfDefaultColor = "green"
+
+// XDXFNode is a node in an XDXF article tree.
+type XDXFNode struct {
+	// Type is the type of the node.
+	Type XDXFNodeType
+
+	// Name is the element name for element nodes.
+	Name string
+
+	// Text is the text for text nodes.
+	Text string
+
+	// Attr is the value of the node's main attribute. For XDXFColorNode it is
+	// the color.
+	Attr string
+
+	// Children are the node's child nodes.
+	Children []*XDXFNode
+}
+
+// XDXFArticle is a parsed XDXF article.
+type XDXFArticle struct {
+	// Nodes are the top-level nodes in the article.
+	Nodes []*XDXFNode
+}
+
+var errXDXF = errors.New("invalid XDXF")
+
+// ParseXDXF parses XDXF article data. StarDict dictionaries store XDXF
+// article fragments so data does not need to have a single root element.
+// Parsing is lenient and unclosed or mismatched tags are tolerated.
+func ParseXDXF(data []byte) (*XDXFArticle, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = false
+	d.AutoClose = []string{"br"}
+	d.Entity = xml.HTMLEntity
+
+	root := &XDXFNode{Type: XDXFElementNode}
+	stack := []*XDXFNode{root}
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		// Unclosed elements at the end of the data are implicitly closed.
+		var syntaxErr *xml.SyntaxError
+		if errors.As(err, &syntaxErr) && syntaxErr.Msg == "unexpected EOF" {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errXDXF, err)
+		}
+
+		parent := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := newXDXFElement(t)
+			parent.Children = append(parent.Children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			parent.Children = append(parent.Children, &XDXFNode{
+				Type: XDXFTextNode,
+				Text: string(t),
+			})
+		}
+	}
+
+	return &XDXFArticle{Nodes: root.Children}, nil
+}
+
+// newXDXFElement returns a new node for the given element.
+func newXDXFElement(e xml.StartElement) *XDXFNode {
+	n := &XDXFNode{
+		Type: XDXFElementNode,
+		Name: e.Name.Local,
+	}
+	switch e.Name.Local {
+	case "k":
+		n.Type = XDXFKeyNode
+	case "tr":
+		n.Type = XDXFTranscriptionNode
+	case "dtrn":
+		n.Type = XDXFTranslationNode
+	case "ex":
+		n.Type = XDXFExampleNode
+	case "kref":
+		n.Type = XDXFReferenceNode
+	case "abr":
+		n.Type = XDXFAbbreviationNode
+	case "c":
+		n.Type = XDXFColorNode
+		n.Attr = xdxfDefaultColor
+		for _, a := range e.Attr {
+			if a.Name.Local == "c" && a.Value != "" {
+				n.Attr = a.Value
+			}
+		}
+	case "br":
+		// Line breaks are treated as newline text.
+		n.Type = XDXFTextNode
+		n.Name = ""
+		n.Text = "\n"
+	}
+	return n
+}
+
+// Text returns the article as plain text. Transcriptions are enclosed in
+// square brackets and all other markup is removed.
+func (a *XDXFArticle) Text() string {
+	var b strings.Builder
+	for _, n := range a.Nodes {
+		n.writeText(&b)
+	}
+	return b.String()
+}
+
+// HTML returns the article rendered as HTML. References are rendered as links
+// using the "bword://" scheme used by StarDict.
+func (a *XDXFArticle) HTML() string {
+	var b strings.Builder
+	for _, n := range a.Nodes {
+		n.writeHTML(&b)
+	}
+	return b.String()
+}
+
+// innerText returns the plain text of the node's children.
+func (n *XDXFNode) innerText() string {
+	var b strings.Builder
+	for _, c := range n.Children {
+		c.writeText(&b)
+	}
+	return b.String()
+}
+
+// writeText writes the plain text of the node to b.
+func (n *XDXFNode) writeText(b *strings.Builder) {
+	switch n.Type {
+	case XDXFTextNode:
+		_, _ = b.WriteString(n.Text)
+	case XDXFTranscriptionNode:
+		_, _ = b.WriteString("[" + n.innerText() + "]")
+	case XDXFKeyNode, XDXFTranslationNode, XDXFExampleNode, XDXFReferenceNode,
+		XDXFAbbreviationNode, XDXFColorNode, XDXFElementNode:
+		_, _ = b.WriteString(n.innerText())
+	}
+}
+
+// writeHTML writes the node and its children to b as HTML.
+func (n *XDXFNode) writeHTML(b *strings.Builder) {
+	var open, closing string
+	switch n.Type {
+	case XDXFTextNode:
+		_, _ = b.WriteString(strings.ReplaceAll(html.EscapeString(n.Text), "\n", "<br>"))
+		return
+	case XDXFKeyNode:
+		open, closing = `<b class="k">`, "</b>"
+	case XDXFTranscriptionNode:
+		open, closing = `<span class="tr">[`, "]</span>"
+	case XDXFTranslationNode:
+		open, closing = `<span class="dtrn">`, "</span>"
+	case XDXFExampleNode:
+		open, closing = `<i class="ex">`, "</i>"
+	case XDXFReferenceNode:
+		open = `<a class="kref" href="bword://` + html.EscapeString(url.PathEscape(n.innerText())) + `">`
+		closing = "</a>"
+	case XDXFAbbreviationNode:
+		open, closing = `<abbr class="abr">`, "</abbr>"
+	case XDXFColorNode:
+		open, closing = `<span style="color:`+html.EscapeString(cssColor(n.Attr, xdxfDefaultColor))+`">`, "</span>"
+	case XDXFElementNode:
+	}
+
+	_, _ = b.WriteString(open)
+	for _, c := range n.Children {
+		c.writeHTML(b)
+	}
+	_, _ = b.WriteString(closing)
+}
+
+// cssColor returns the color if it is a valid color name or hex value and def
+// otherwise.
+func cssColor(c, def string) string {
+	if c == "" {
+		return def
+	}
+	for i, r := range c {
+		switch {
+		case r == '#' && i == 0:
+		case r >= '0' && r <= '9', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		default:
+			return def
+		}
+	}
+	return c
+}
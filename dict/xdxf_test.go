@@ -0,0 +1,169 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dict_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/dict"
+)
+
+// TestParseXDXF tests ParseXDXF.
+func TestParseXDXF(t *testing.T) {
+	t.Parallel()
+
+	a, err := dict.ParseXDXF([]byte(`<k>cat</k> <tr>kæt</tr><c c="red">n.</c><kref>dog</kref>`))
+	if err != nil {
+		t.Fatalf("ParseXDXF: %v", err)
+	}
+
+	expected := &dict.XDXFArticle{
+		Nodes: []*dict.XDXFNode{
+			{
+				Type: dict.XDXFKeyNode,
+				Name: "k",
+				Children: []*dict.XDXFNode{
+					{Type: dict.XDXFTextNode, Text: "cat"},
+				},
+			},
+			{Type: dict.XDXFTextNode, Text: " "},
+			{
+				Type: dict.XDXFTranscriptionNode,
+				Name: "tr",
+				Children: []*dict.XDXFNode{
+					{Type: dict.XDXFTextNode, Text: "kæt"},
+				},
+			},
+			{
+				Type: dict.XDXFColorNode,
+				Name: "c",
+				Attr: "red",
+				Children: []*dict.XDXFNode{
+					{Type: dict.XDXFTextNode, Text: "n."},
+				},
+			},
+			{
+				Type: dict.XDXFReferenceNode,
+				Name: "kref",
+				Children: []*dict.XDXFNode{
+					{Type: dict.XDXFTextNode, Text: "dog"},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, a); diff != "" {
+		t.Errorf("ParseXDXF (-want, +got):\n%s", diff)
+	}
+}
+
+// TestXDXFArticle tests rendering XDXF articles.
+func TestXDXFArticle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data string
+
+		text string
+		html string
+	}{
+		{
+			name: "plain text",
+			data: "some text",
+			text: "some text",
+			html: "some text",
+		},
+		{
+			name: "key and transcription",
+			data: "<k>cat</k>\n<tr>kæt</tr>",
+			text: "cat\n[kæt]",
+			html: `<b class="k">cat</b><br><span class="tr">[kæt]</span>`,
+		},
+		{
+			name: "translation and example",
+			data: "<dtrn>neko</dtrn> <ex>the cat sat</ex>",
+			text: "neko the cat sat",
+			html: `<span class="dtrn">neko</span> <i class="ex">the cat sat</i>`,
+		},
+		{
+			name: "abbreviation",
+			data: "<abr>n.</abr>",
+			text: "n.",
+			html: `<abbr class="abr">n.</abbr>`,
+		},
+		{
+			name: "reference",
+			data: "see <kref>big cat</kref>",
+			text: "see big cat",
+			html: `see <a class="kref" href="bword://big%20cat">big cat</a>`,
+		},
+		{
+			name: "color",
+			data: `<c c="#ff0000">red</c> <c>green</c>`,
+			text: "red green",
+			html: `<span style="color:#ff0000">red</span> <span style="color:green">green</span>`,
+		},
+		{
+			name: "invalid color",
+			data: `<c c="red;background:url(x)">text</c>`,
+			text: "text",
+			html: `<span style="color:green">text</span>`,
+		},
+		{
+			name: "escaping",
+			data: "a &lt; b &amp; c",
+			text: "a < b & c",
+			html: "a &lt; b &amp; c",
+		},
+		{
+			name: "line break",
+			data: "a<br>b",
+			text: "a\nb",
+			html: "a<br>b",
+		},
+		{
+			name: "nested",
+			data: "<dtrn><b>bold</b> <c c=\"blue\"><kref>x</kref></c></dtrn>",
+			text: "bold x",
+			html: `<span class="dtrn">bold <span style="color:blue"><a class="kref" href="bword://x">x</a></span></span>`,
+		},
+		{
+			name: "unclosed",
+			data: "<k>cat",
+			text: "cat",
+			html: `<b class="k">cat</b>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			a, err := dict.ParseXDXF([]byte(test.data))
+			if err != nil {
+				t.Fatalf("ParseXDXF: %v", err)
+			}
+
+			if diff := cmp.Diff(test.text, a.Text()); diff != "" {
+				t.Errorf("Text (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.html, a.HTML()); diff != "" {
+				t.Errorf("HTML (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,468 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dictd implements reading and writing dictd (DICT protocol)
+// databases.
+//
+// A dictd database consists of a .index file and a .dict or .dict.dz data
+// file. Each line of the .index file contains a headword, the offset of its
+// definition in the data file, and the length of the definition separated by
+// tabs. Offsets and lengths are encoded as base64 numbers.
+//
+//	dictionary	BAb	r
+//
+// Database metadata is stored in entries with special headwords such as
+// 00-database-short (the database name) and 00-database-info (the database
+// description).
+package dictd
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/ianlewis/go-dictzip"
+
+	"github.com/ianlewis/go-stardict"
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/ifo"
+)
+
+var (
+	// ErrSyntax indicates a syntax error in the .index file.
+	ErrSyntax = errors.New("syntax error")
+
+	errInvalidNumber = errors.New("invalid base64 number")
+	errDataType      = errors.New("unsupported data type")
+	errShortRead     = errors.New("definition extends past end of data")
+	errTooLarge      = errors.New("definition too large")
+)
+
+// Headwords of database metadata entries.
+const (
+	shortHeadword = "00-database-short"
+	infoHeadword  = "00-database-info"
+	urlHeadword   = "00-database-url"
+	utf8Headword  = "00-database-utf8"
+
+	// metadataPrefix is the prefix of metadata headwords after
+	// normalization.
+	metadataPrefix = "00database"
+)
+
+// b64 is the alphabet used to encode numbers in .index files. Unlike
+// standard base64, numbers are encoded with the most significant digit first
+// and without padding.
+const b64 = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// encodeNumber encodes n as a base64 number.
+func encodeNumber(n uint64) string {
+	if n == 0 {
+		return b64[:1]
+	}
+	var buf [11]byte
+	i := len(buf)
+	for n > 0 {
+		i--
+		buf[i] = b64[n%64]
+		n /= 64
+	}
+	return string(buf[i:])
+}
+
+// decodeNumber decodes the base64 number s.
+func decodeNumber(s string) (uint64, error) {
+	if s == "" || len(s) > 11 {
+		return 0, fmt.Errorf("%w: %q", errInvalidNumber, s)
+	}
+	var n uint64
+	for i := range len(s) {
+		d := strings.IndexByte(b64, s[i])
+		if d < 0 {
+			return 0, fmt.Errorf("%w: %q", errInvalidNumber, s)
+		}
+		// The 11th digit may only use the lowest 4 bits.
+		if n > (1<<64-1)>>6 {
+			return 0, fmt.Errorf("%w: %q", errInvalidNumber, s)
+		}
+		n = n<<6 | uint64(d)
+	}
+	return n, nil
+}
+
+// IndexEntry is an entry in the .index file.
+type IndexEntry struct {
+	// Headword is the word.
+	Headword string
+
+	// Offset is the offset of the definition in the data file.
+	Offset uint64
+
+	// Length is the length of the definition in bytes.
+	Length uint64
+}
+
+// ReadIndex reads all entries in the .index file.
+func ReadIndex(r io.Reader) ([]*IndexEntry, error) {
+	var entries []*IndexEntry
+
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1024*1024)
+	line := 0
+	for s.Scan() {
+		line++
+		text := strings.TrimSuffix(s.Text(), "\r")
+		if text == "" {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("%w: line %d: expected 3 fields", ErrSyntax, line)
+		}
+		offset, err := decodeNumber(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrSyntax, line, err)
+		}
+		length, err := decodeNumber(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrSyntax, line, err)
+		}
+		entries = append(entries, &IndexEntry{
+			Headword: fields[0],
+			Offset:   offset,
+			Length:   length,
+		})
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("reading .index file: %w", err)
+	}
+	return entries, nil
+}
+
+// Database is a dictd database.
+type Database struct {
+	entries []*IndexEntry
+	r       io.ReaderAt
+	size    int64
+
+	// dz is the dictzip reader for compressed data files.
+	dz *dictzip.Reader
+	c  io.Closer
+}
+
+// NewDatabase reads the .index file from index and returns a Database that
+// reads definitions from data. size is the size of the data. An error is
+// returned if a definition extends past the end of the data or is larger
+// than [dict.DefaultMaxEntrySize].
+func NewDatabase(index io.Reader, data io.ReaderAt, size int64) (*Database, error) {
+	entries, err := ReadIndex(index)
+	if err != nil {
+		return nil, err
+	}
+	db := &Database{
+		entries: entries,
+		r:       data,
+		size:    size,
+	}
+	for _, e := range entries {
+		if err = db.check(e); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// Open opens the dictd database with the given .index file path. The data
+// file is the .dict.dz or .dict file with the same base name.
+func Open(path string) (*Database, error) {
+	index, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening .index file: %w", err)
+	}
+	defer index.Close()
+
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	f, err := os.Open(base + ".dict.dz")
+	if errors.Is(err, os.ErrNotExist) {
+		f, err = os.Open(base + ".dict")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening .dict file: %w", err)
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("opening .dict file: %w", err)
+	}
+	var r io.ReaderAt = f
+	size := fi.Size()
+	var dz *dictzip.Reader
+	if strings.HasSuffix(f.Name(), ".dz") {
+		dz, err = dictzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("opening dictzip: %w", err)
+		}
+		r = dz
+		size, err = dictzipSize(f, size)
+		if err != nil {
+			dz.Close()
+			f.Close()
+			return nil, err
+		}
+	}
+
+	db, err := NewDatabase(index, r, size)
+	if err != nil {
+		if dz != nil {
+			dz.Close()
+		}
+		f.Close()
+		return nil, err
+	}
+	db.dz = dz
+	db.c = f
+	return db, nil
+}
+
+// dictzipSize returns the uncompressed size of the dictzip file f of the given
+// compressed size. The size is read from the gzip trailer. It is stored modulo
+// 2^32 but dictzip files are limited to less than 4GiB of data.
+func dictzipSize(f io.ReaderAt, size int64) (int64, error) {
+	var b [4]byte
+	if size < int64(len(b)) {
+		return 0, fmt.Errorf("opening dictzip: %w", io.ErrUnexpectedEOF)
+	}
+	if _, err := f.ReadAt(b[:], size-int64(len(b))); err != nil {
+		return 0, fmt.Errorf("opening dictzip: %w", err)
+	}
+	return int64(binary.LittleEndian.Uint32(b[:])), nil
+}
+
+// Entries returns the .index file entries in file order.
+func (db *Database) Entries() []*IndexEntry {
+	return db.entries
+}
+
+// Definition returns the raw definition for the entry.
+func (db *Database) Definition(e *IndexEntry) (string, error) {
+	if err := db.check(e); err != nil {
+		return "", err
+	}
+	b := make([]byte, e.Length)
+	n, err := db.r.ReadAt(b, int64(e.Offset)) //nolint:gosec // offsets are limited by the data size.
+	if n < len(b) {
+		if err == nil || errors.Is(err, io.EOF) {
+			err = errShortRead
+		}
+		return "", fmt.Errorf("reading definition of %q: %w", e.Headword, err)
+	}
+	return string(b), nil
+}
+
+// check returns an error if the entry's definition is outside of the data or
+// is too large to be read.
+func (db *Database) check(e *IndexEntry) error {
+	if e.Length > dict.DefaultMaxEntrySize {
+		return fmt.Errorf("reading definition of %q: %w: %d bytes", e.Headword, errTooLarge, e.Length)
+	}
+	//nolint:gosec // size is not negative.
+	if e.Offset > uint64(db.size) || e.Length > uint64(db.size)-e.Offset {
+		return fmt.Errorf("reading definition of %q: %w: offset %d, length %d, data size %d",
+			e.Headword, errShortRead, e.Offset, e.Length, db.size)
+	}
+	return nil
+}
+
+// Info returns dictionary metadata from the database's metadata entries.
+// The BookName is read from 00-database-short, the Description from
+// 00-database-info, and the Website from 00-database-url.
+func (db *Database) Info() (*ifo.Info, error) {
+	info := &ifo.Info{}
+	for _, e := range db.entries {
+		var field *string
+		switch normalize(e.Headword) {
+		case normalize(shortHeadword):
+			field = &info.BookName
+		case normalize(infoHeadword):
+			field = &info.Description
+		case normalize(urlHeadword):
+			field = &info.Website
+		default:
+			continue
+		}
+		def, err := db.Definition(e)
+		if err != nil {
+			return nil, err
+		}
+		*field = Text(e.Headword, def)
+	}
+	return info, nil
+}
+
+// Close closes the data file and the dictzip reader if the data file is
+// compressed.
+func (db *Database) Close() error {
+	if db.dz != nil {
+		if err := db.dz.Close(); err != nil {
+			if db.c != nil {
+				_ = db.c.Close()
+			}
+			return fmt.Errorf("closing dictzip reader: %w", err)
+		}
+	}
+	if db.c == nil {
+		return nil
+	}
+	if err := db.c.Close(); err != nil {
+		return fmt.Errorf("closing .dict file: %w", err)
+	}
+	return nil
+}
+
+// Text returns the text of a raw definition. Definitions conventionally start
+// with the headword on its own line followed by the indented definition text.
+// The headword line is removed and the text is unindented.
+func Text(headword, def string) string {
+	lines := strings.Split(strings.ReplaceAll(def, "\r\n", "\n"), "\n")
+	if len(lines) > 1 && normalize(lines[0]) == normalize(headword) {
+		lines = lines[1:]
+	}
+
+	// Trim blank lines.
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	// Remove the common indentation.
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if len(line) >= indent && indent > 0 {
+			line = line[indent:]
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// normalize returns the headword as compared by dictd. Only letters, digits,
+// and spaces are significant and letters are compared case-insensitively.
+func normalize(s string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == ' ' {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// isMetadata returns whether the headword is a database metadata headword.
+func isMetadata(headword string) bool {
+	return strings.HasPrefix(normalize(headword), metadataPrefix)
+}
+
+// ImportOptions are options for Import.
+type ImportOptions struct {
+	// DataType is the type of the definition data. It must be a string type
+	// such as dict.UTFTextType ('m') or dict.HTMLType ('h'). The default is
+	// dict.UTFTextType.
+	DataType dict.DataType
+}
+
+// Import adds the entries in the database to the Builder and returns the
+// number of entries added. Headwords that share a definition are added as
+// synonyms of the first headword. Metadata entries are skipped.
+func Import(db *Database, b *stardict.Builder, options *ImportOptions) (int, error) {
+	if options == nil {
+		options = &ImportOptions{}
+	}
+	t := options.DataType
+	if t == 0 {
+		t = dict.UTFTextType
+	}
+	if t < 'a' || t > 'z' {
+		return 0, fmt.Errorf("%w: %q", errDataType, t)
+	}
+
+	// Group headwords by definition in file order.
+	type location struct {
+		offset, length uint64
+	}
+	groups := map[location][]*IndexEntry{}
+	var order []location
+	for _, e := range db.entries {
+		if isMetadata(e.Headword) {
+			continue
+		}
+		loc := location{e.Offset, e.Length}
+		if _, ok := groups[loc]; !ok {
+			order = append(order, loc)
+		}
+		groups[loc] = append(groups[loc], e)
+	}
+
+	n := 0
+	for _, loc := range order {
+		entries := groups[loc]
+		def, err := db.Definition(entries[0])
+		if err != nil {
+			return n, err
+		}
+
+		// The word is the headword on the first line of the definition if
+		// present and the first headword otherwise.
+		first, _, _ := strings.Cut(def, "\n")
+		i := slices.IndexFunc(entries, func(e *IndexEntry) bool {
+			return normalize(e.Headword) == normalize(first)
+		})
+		word := entries[max(i, 0)].Headword
+		var synonyms []string
+		for _, e := range entries {
+			if e.Headword != word && !slices.Contains(synonyms, e.Headword) {
+				synonyms = append(synonyms, e.Headword)
+			}
+		}
+
+		if err := b.AddEntry(word, synonyms, &dict.Data{
+			Type: t,
+			Data: []byte(Text(word, def)),
+		}); err != nil {
+			return n, fmt.Errorf("adding %q: %w", word, err)
+		}
+		n++
+	}
+	return n, nil
+}
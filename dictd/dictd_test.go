@@ -0,0 +1,331 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dictd
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/ianlewis/go-dictzip"
+
+	"github.com/ianlewis/go-stardict"
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/ifo"
+)
+
+func TestNumber(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		n       uint64
+		encoded string
+	}{
+		{0, "A"},
+		{1, "B"},
+		{63, "/"},
+		{64, "BA"},
+		{4095, "//"},
+		{123456, "eJA"},
+		{math.MaxUint64, "P//////////"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.encoded, func(t *testing.T) {
+			t.Parallel()
+
+			if got := encodeNumber(test.n); got != test.encoded {
+				t.Errorf("encodeNumber(%d): expected %q, got %q", test.n, test.encoded, got)
+			}
+			got, err := decodeNumber(test.encoded)
+			if err != nil {
+				t.Fatalf("decodeNumber(%q): %v", test.encoded, err)
+			}
+			if got != test.n {
+				t.Errorf("decodeNumber(%q): expected %d, got %d", test.encoded, test.n, got)
+			}
+		})
+	}
+
+	for _, s := range []string{"", "A=", "Q//////////", "AAAAAAAAAAAA"} {
+		if _, err := decodeNumber(s); !errors.Is(err, errInvalidNumber) {
+			t.Errorf("decodeNumber(%q): expected %v, got %v", s, errInvalidNumber, err)
+		}
+	}
+}
+
+func TestReadIndex(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []*IndexEntry
+		err      error
+	}{
+		{
+			name:  "entries",
+			input: "00-database-short\tA\te\ndictionary\te\tr\r\nlexicon\te\tr\n\n",
+			expected: []*IndexEntry{
+				{Headword: "00-database-short", Offset: 0, Length: 30},
+				{Headword: "dictionary", Offset: 30, Length: 43},
+				{Headword: "lexicon", Offset: 30, Length: 43},
+			},
+		},
+		{
+			name:  "missing field",
+			input: "dictionary\tA\n",
+			err:   ErrSyntax,
+		},
+		{
+			name:  "invalid number",
+			input: "dictionary\tA\t!\n",
+			err:   ErrSyntax,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			entries, err := ReadIndex(strings.NewReader(test.input))
+			if !errors.Is(err, test.err) {
+				t.Fatalf("ReadIndex: expected %v, got %v", test.err, err)
+			}
+			if diff := cmp.Diff(test.expected, entries); diff != "" {
+				t.Errorf("ReadIndex (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewDatabase_invalid(t *testing.T) {
+	t.Parallel()
+
+	data := "hoge\n   fuga\n"
+
+	tests := []struct {
+		name  string
+		index string
+		err   error
+	}{
+		{
+			name:  "oversized length",
+			index: "hoge\tA\tP//////////\n",
+			err:   errTooLarge,
+		},
+		{
+			name:  "past end of data",
+			index: "hoge\tA\tZ\n",
+			err:   errShortRead,
+		},
+		{
+			name:  "offset past end of data",
+			index: "hoge\tP//////////\tA\n",
+			err:   errShortRead,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewDatabase(strings.NewReader(test.index), strings.NewReader(data), int64(len(data)))
+			if !errors.Is(err, test.err) {
+				t.Fatalf("NewDatabase: expected %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestOpen_dictzip(t *testing.T) {
+	t.Parallel()
+
+	data := "hoge\n   fuga\n"
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "hoge.index"), []byte("hoge\tA\tN\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(filepath.Join(dir, "hoge.dict.dz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	z, err := dictzip.NewWriter(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = z.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err = z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := Open(filepath.Join(dir, "hoge.index"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	def, err := db.Definition(db.Entries()[0])
+	if err != nil {
+		t.Fatalf("Definition: %v", err)
+	}
+	if def != data {
+		t.Errorf("Definition: expected %q, got %q", data, def)
+	}
+	if err := db.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+
+	// The data size is the uncompressed size.
+	if err := os.WriteFile(filepath.Join(dir, "hoge.index"), []byte("hoge\tA\tO\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(filepath.Join(dir, "hoge.index")); !errors.Is(err, errShortRead) {
+		t.Errorf("Open: expected %v, got %v", errShortRead, err)
+	}
+}
+
+func TestText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		headword string
+		def      string
+		expected string
+	}{
+		{
+			name:     "headword line",
+			headword: "hoge",
+			def:      "hoge\n   hoge text\n     indented\n\n   more\n",
+			expected: "hoge text\n  indented\n\nmore",
+		},
+		{
+			name:     "normalized headword line",
+			headword: "00-database-short",
+			def:      "00databaseshort\r\n    Example\r\n",
+			expected: "Example",
+		},
+		{
+			name:     "no headword line",
+			headword: "hoge",
+			def:      "fuga\npiyo",
+			expected: "fuga\npiyo",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Text(test.headword, test.def); got != test.expected {
+				t.Errorf("Text: expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	data := "00-database-short\n   Example\n" +
+		"00-database-info\n   An example database.\n" +
+		"dictionary\n   A reference book of words.\n" +
+		"thesaurus\n   A book of synonyms.\n"
+	index := "00-database-info\td\tp\n" +
+		"00-database-short\tA\td\n" +
+		"book\tBG\tp\n" +
+		"dictionary\tBG\tp\n" +
+		"lexicon\tBG\tp\n" +
+		"thesaurus\tBv\th\n"
+	db, err := NewDatabase(strings.NewReader(index), strings.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("NewDatabase: %v", err)
+	}
+
+	info, err := db.Info()
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	expectedInfo := &ifo.Info{
+		BookName:    "Example",
+		Description: "An example database.",
+	}
+	if diff := cmp.Diff(expectedInfo, info); diff != "" {
+		t.Errorf("Info (-want, +got):\n%s", diff)
+	}
+
+	b, err := stardict.NewBuilder(info, nil)
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+	n, err := Import(db, b, nil)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if got, want := n, 2; got != want {
+		t.Errorf("Import: expected %d entries, got %d", want, got)
+	}
+
+	dir := t.TempDir()
+	if err := b.Finish(dir, "example"); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	s, err := stardict.Open(filepath.Join(dir, "example.ifo"), nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+
+	entries, err := s.Search("lexicon")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Search: expected 1 entry, got %d", len(entries))
+	}
+	if got, want := entries[0].Title(), "dictionary"; got != want {
+		t.Errorf("Title: expected %q, got %q", want, got)
+	}
+	expected := stardict.DataList{
+		{Type: dict.UTFTextType, Data: []byte("A reference book of words.")},
+	}
+	if diff := cmp.Diff(expected, entries[0].Data()); diff != "" {
+		t.Errorf("Data (-want, +got):\n%s", diff)
+	}
+
+	synonyms := map[string][]string{}
+	if err := s.Walk(func(e *stardict.Entry) error {
+		synonyms[e.Title()] = e.Synonyms()
+		return nil
+	}); err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+	expectedSynonyms := map[string][]string{
+		"dictionary": {"book", "lexicon"},
+		"thesaurus":  nil,
+	}
+	if diff := cmp.Diff(expectedSynonyms, synonyms, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("Synonyms (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dictd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/ianlewis/go-dictzip"
+
+	"github.com/ianlewis/go-stardict"
+	"github.com/ianlewis/go-stardict/ifo"
+)
+
+// ErrWriterClosed indicates that the Writer has already been closed.
+var ErrWriterClosed = errors.New("writer closed")
+
+// WriterOptions are options for a Writer.
+type WriterOptions struct {
+	// DictZip indicates that the data file should be compressed with
+	// dictzip.
+	DictZip bool
+}
+
+// Writer writes dictd databases.
+type Writer struct {
+	index   io.Writer
+	data    io.Writer
+	dz      *dictzip.Writer
+	offset  uint64
+	entries []*IndexEntry
+	closed  bool
+}
+
+// NewWriter returns a new Writer that writes the .index file to index and
+// the definitions to data. Metadata entries are written for the info's
+// BookName, Description, and Website. The Close method must be called to
+// finish writing the database.
+func NewWriter(index, data io.Writer, info *ifo.Info, options *WriterOptions) (*Writer, error) {
+	if options == nil {
+		options = &WriterOptions{}
+	}
+
+	w := &Writer{
+		index: index,
+		data:  data,
+	}
+	if options.DictZip {
+		z, err := dictzip.NewWriter(data)
+		if err != nil {
+			return nil, fmt.Errorf("creating dictzip writer: %w", err)
+		}
+		w.dz = z
+		w.data = z
+	}
+
+	// The database is always written in UTF-8.
+	if err := w.Add([]string{utf8Headword}, utf8Headword+"\n"); err != nil {
+		return nil, err
+	}
+	for _, m := range []struct {
+		headword, value string
+	}{
+		{shortHeadword, info.BookName},
+		{infoHeadword, info.Description},
+		{urlHeadword, info.Website},
+	} {
+		if m.value == "" {
+			continue
+		}
+		if err := w.Add([]string{m.headword}, m.headword+"\n"+indent(m.value)+"\n"); err != nil {
+			return nil, err
+		}
+	}
+
+	return w, nil
+}
+
+// Add writes the definition for the headwords. The definition is written as
+// is and is returned verbatim by dictd.
+func (w *Writer) Add(headwords []string, def string) error {
+	if w.closed {
+		return ErrWriterClosed
+	}
+
+	if _, err := io.WriteString(w.data, def); err != nil {
+		return fmt.Errorf("writing .dict file: %w", err)
+	}
+	for _, h := range headwords {
+		w.entries = append(w.entries, &IndexEntry{
+			// Tabs and newlines are not allowed in headwords.
+			Headword: strings.Join(strings.Fields(h), " "),
+			Offset:   w.offset,
+			Length:   uint64(len(def)),
+		})
+	}
+	w.offset += uint64(len(def))
+
+	return nil
+}
+
+// Close finishes writing the data file and writes the sorted .index file. It
+// does not close the underlying writers.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if w.dz != nil {
+		if err := w.dz.Close(); err != nil {
+			return fmt.Errorf("closing dictzip writer: %w", err)
+		}
+	}
+
+	slices.SortStableFunc(w.entries, func(a, b *IndexEntry) int {
+		return Compare(a.Headword, b.Headword)
+	})
+	bw := bufio.NewWriter(w.index)
+	for _, e := range w.entries {
+		if _, err := fmt.Fprintf(bw, "%s\t%s\t%s\n",
+			e.Headword, encodeNumber(e.Offset), encodeNumber(e.Length)); err != nil {
+			return fmt.Errorf("writing .index file: %w", err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("writing .index file: %w", err)
+	}
+	return nil
+}
+
+// Compare compares headwords in the order used by dictd for .index files.
+// Only letters, digits, and spaces are significant and letters are compared
+// case-insensitively. Headwords that are equal are ordered by their bytes.
+func Compare(a, b string) int {
+	if c := strings.Compare(normalize(a), normalize(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// indent indents each line of s.
+func indent(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = "   " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// NewDefinition returns a dictd definition for the dictionary entry. The
+// definition starts with the headword followed by the indented text of the
+// entry's data. Binary data is omitted.
+func NewDefinition(e *stardict.Entry) string {
+	var text []string
+	for _, d := range e.Data() {
+		if s := strings.TrimSpace(d.String()); s != "" {
+			text = append(text, s)
+		}
+	}
+	return e.Title() + "\n" + indent(strings.Join(text, "\n\n")) + "\n"
+}
+
+// Export writes every entry in the dictionary to a dictd database and
+// returns the number of entries written. The entry's synonyms are added to
+// the .index file.
+func Export(s *stardict.Stardict, index, data io.Writer, options *WriterOptions) (int, error) {
+	info, err := s.Info()
+	if err != nil {
+		//nolint:wrapcheck // error is already wrapped.
+		return 0, err
+	}
+
+	w, err := NewWriter(index, data, info, options)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	if err := s.Walk(func(e *stardict.Entry) error {
+		if err := w.Add(append([]string{e.Title()}, e.Synonyms()...), NewDefinition(e)); err != nil {
+			return err
+		}
+		n++
+		return nil
+	}); err != nil {
+		return n, fmt.Errorf("exporting %q: %w", s.Bookname(), err)
+	}
+	if err := w.Close(); err != nil {
+		return n, err
+	}
+	return n, nil
+}
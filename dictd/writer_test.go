@@ -0,0 +1,148 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dictd
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict"
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/ifo"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	var index, data bytes.Buffer
+	w, err := NewWriter(&index, &data, &ifo.Info{
+		BookName: "Example",
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	if err := w.Add([]string{"Zebra"}, "Zebra\n   An animal.\n"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := w.Add([]string{"apple", "a-pple"}, "apple\n   A fruit.\n"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := w.Add([]string{"hoge"}, "hoge"); !errors.Is(err, ErrWriterClosed) {
+		t.Errorf("Add: expected %v, got %v", ErrWriterClosed, err)
+	}
+
+	expectedData := "00-database-utf8\n" +
+		"00-database-short\n   Example\n" +
+		"Zebra\n   An animal.\n" +
+		"apple\n   A fruit.\n"
+	if diff := cmp.Diff(expectedData, data.String()); diff != "" {
+		t.Errorf("data (-want, +got):\n%s", diff)
+	}
+
+	expectedIndex := "00-database-short\tR\td\n" +
+		"00-database-utf8\tA\tR\n" +
+		"a-pple\tBC\tS\n" +
+		"apple\tBC\tS\n" +
+		"Zebra\tu\tU\n"
+	if diff := cmp.Diff(expectedIndex, index.String()); diff != "" {
+		t.Errorf("index (-want, +got):\n%s", diff)
+	}
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	b, err := stardict.NewBuilder(&ifo.Info{
+		BookName:    "hoge",
+		Description: "An example.",
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+	if err := b.AddEntry("dictionary", []string{"lexicon"},
+		&dict.Data{Type: dict.UTFTextType, Data: []byte("A reference book.\nSee thesaurus.")},
+		&dict.Data{Type: dict.WavType, Data: []byte{0, 1}},
+	); err != nil {
+		t.Fatalf("AddEntry: %v", err)
+	}
+	if err := b.AddEntry("thesaurus", nil,
+		&dict.Data{Type: dict.HTMLType, Data: []byte("<b>A book of synonyms.</b>")},
+	); err != nil {
+		t.Fatalf("AddEntry: %v", err)
+	}
+	dir := t.TempDir()
+	if err := b.Finish(dir, "hoge"); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+
+	s, err := stardict.Open(filepath.Join(dir, "hoge.ifo"), nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+
+	var index, data bytes.Buffer
+	n, err := Export(s, &index, &data, nil)
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if got, want := n, 2; got != want {
+		t.Errorf("Export: expected %d entries, got %d", want, got)
+	}
+
+	// Read the exported database back.
+	db, err := NewDatabase(&index, bytes.NewReader(data.Bytes()), int64(data.Len()))
+	if err != nil {
+		t.Fatalf("NewDatabase: %v", err)
+	}
+	info, err := db.Info()
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	expectedInfo := &ifo.Info{
+		BookName:    "hoge",
+		Description: "An example.",
+	}
+	if diff := cmp.Diff(expectedInfo, info); diff != "" {
+		t.Errorf("Info (-want, +got):\n%s", diff)
+	}
+
+	got := map[string]string{}
+	for _, e := range db.Entries() {
+		if isMetadata(e.Headword) {
+			continue
+		}
+		def, err := db.Definition(e)
+		if err != nil {
+			t.Fatalf("Definition: %v", err)
+		}
+		got[e.Headword] = def
+	}
+	expected := map[string]string{
+		"dictionary": "dictionary\n   A reference book.\n   See thesaurus.\n",
+		"lexicon":    "dictionary\n   A reference book.\n   See thesaurus.\n",
+		"thesaurus":  "thesaurus\n   A book of synonyms.\n",
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("definitions (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,515 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dsl implements reading and writing ABBYY Lingvo DSL dictionaries.
+//
+// A DSL file starts with header lines beginning with '#' followed by cards.
+// Each card has one or more headword lines that start at the beginning of
+// the line followed by body lines that are indented with spaces or tabs.
+// Body lines may contain markup tags such as [m1], [trn], and [ex]. Text in
+// {{double braces}} is a comment and is ignored. A body line starting with
+// '@' begins a subentry which is read as a separate card.
+//
+//	#NAME "Example"
+//	#INDEX_LANGUAGE "English"
+//	#CONTENTS_LANGUAGE "English"
+//
+//	colo(u)r
+//	    [m1][trn]the property of reflecting light[/trn][/m]
+//	    [m2][ex]the colour of the sky[/ex][/m]
+//	    @ colo(u)r blind
+//	    [m1]unable to distinguish colours[/m]
+//
+// DSL files are usually encoded in UTF-16 but UTF-8 files are also supported.
+package dsl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+
+	"github.com/ianlewis/go-stardict"
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/ifo"
+)
+
+var (
+	// ErrSyntax indicates a syntax error in the DSL file.
+	ErrSyntax = errors.New("syntax error")
+
+	errNoHeadword = errors.New("body without headword")
+	errNoBody     = errors.New("card has no body")
+	errDataType   = errors.New("unsupported data type")
+)
+
+// Header is the DSL file header.
+type Header struct {
+	// Name is the dictionary name (#NAME).
+	Name string
+
+	// IndexLanguage is the language of the headwords (#INDEX_LANGUAGE), e.g.
+	// "English".
+	IndexLanguage string
+
+	// ContentsLanguage is the language of the card bodies
+	// (#CONTENTS_LANGUAGE).
+	ContentsLanguage string
+}
+
+// Info returns dictionary metadata for the header. The languages are
+// converted to language codes if they are known.
+func (h *Header) Info() *ifo.Info {
+	return &ifo.Info{
+		BookName: h.Name,
+		From:     LanguageCode(h.IndexLanguage),
+		To:       LanguageCode(h.ContentsLanguage),
+	}
+}
+
+// Card is a DSL dictionary card.
+type Card struct {
+	// Headwords are the card's headwords. The first headword is the main
+	// headword and the rest are alternate forms. Unsorted parts in {braces}
+	// are removed and optional parts in (parentheses) are expanded into all
+	// of their variants.
+	Headwords []string
+
+	// Body is the card's body in DSL markup. Lines are separated by '\n'
+	// and their indentation is removed. Tildes ('~') are replaced with the
+	// main headword.
+	Body string
+}
+
+// Scanner scans a DSL file from start to end.
+type Scanner struct {
+	r      *bufio.Reader
+	header *Header
+
+	line      int
+	inComment bool
+
+	// peek is a line that was read but not yet consumed.
+	peek    string
+	hasPeek bool
+
+	// pending are subentry cards that have not been returned yet.
+	pending []*Card
+
+	card *Card
+	err  error
+}
+
+// NewScanner returns a new Scanner that reads the DSL file from r. The
+// header is read immediately. UTF-16 input is detected by its byte order
+// mark or, if there is none, by the zero bytes of ASCII characters.
+// Otherwise, the input is read as UTF-8.
+func NewScanner(r io.Reader) (*Scanner, error) {
+	s := &Scanner{
+		r:      bufio.NewReaderSize(decode(r), 64*1024),
+		header: &Header{},
+	}
+	if err := s.readHeader(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// decode returns a reader that decodes r to UTF-8.
+func decode(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	fallback := unicode.UTF8.NewDecoder()
+	if b, err := br.Peek(2); err == nil {
+		switch {
+		case b[0] != 0 && b[1] == 0:
+			fallback = unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder()
+		case b[0] == 0 && b[1] != 0:
+			fallback = unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewDecoder()
+		}
+	}
+	return transform.NewReader(br, unicode.BOMOverride(fallback))
+}
+
+// Header returns the DSL file header.
+func (s *Scanner) Header() *Header {
+	return s.header
+}
+
+// readHeader reads the header lines.
+func (s *Scanner) readHeader() error {
+	for {
+		line, ok, err := s.readLine()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "#") {
+			s.unreadLine(line)
+			return nil
+		}
+
+		key, value, _ := strings.Cut(line[1:], " ")
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		switch strings.ToUpper(key) {
+		case "NAME":
+			s.header.Name = value
+		case "INDEX_LANGUAGE":
+			s.header.IndexLanguage = value
+		case "CONTENTS_LANGUAGE":
+			s.header.ContentsLanguage = value
+		}
+	}
+}
+
+// readLine reads the next line with comments removed. Lines that only
+// contain comments are skipped. It returns false at the end of the file.
+func (s *Scanner) readLine() (string, bool, error) {
+	if s.hasPeek {
+		s.hasPeek = false
+		return s.peek, true, nil
+	}
+
+	for {
+		line, err := s.r.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", false, fmt.Errorf("reading DSL file: line %d: %w", s.line+1, err)
+		}
+		if line == "" && errors.Is(err, io.EOF) {
+			return "", false, nil
+		}
+		s.line++
+
+		line = strings.TrimRight(line, "\r\n")
+		stripped := s.stripComments(line)
+		if strings.TrimSpace(stripped) == "" && strings.TrimSpace(line) != "" {
+			// The line only contains comments.
+			if errors.Is(err, io.EOF) {
+				return "", false, nil
+			}
+			continue
+		}
+		return strings.TrimRight(stripped, " \t"), true, nil
+	}
+}
+
+// unreadLine causes the line to be returned by the next call to readLine.
+func (s *Scanner) unreadLine(line string) {
+	s.peek = line
+	s.hasPeek = true
+}
+
+// stripComments removes {{comments}} from the line. Comments may span
+// multiple lines.
+func (s *Scanner) stripComments(line string) string {
+	if !s.inComment && !strings.Contains(line, "{{") {
+		return line
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(line); i++ {
+		if s.inComment {
+			if strings.HasPrefix(line[i:], "}}") {
+				s.inComment = false
+				i++
+			}
+			continue
+		}
+		if line[i] == '\\' && i+1 < len(line) {
+			_ = b.WriteByte(line[i])
+			_ = b.WriteByte(line[i+1])
+			i++
+			continue
+		}
+		if strings.HasPrefix(line[i:], "{{") {
+			s.inComment = true
+			i++
+			continue
+		}
+		_ = b.WriteByte(line[i])
+	}
+	return b.String()
+}
+
+// Scan advances to the next card. It returns false if the scan stops either
+// by reaching the end of the file or an error.
+func (s *Scanner) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+	if len(s.pending) > 0 {
+		s.card = s.pending[0]
+		s.pending = s.pending[1:]
+		return true
+	}
+
+	card, err := s.readCard()
+	if err != nil {
+		s.err = err
+		return false
+	}
+	if card == nil {
+		return false
+	}
+	s.card = card
+	return true
+}
+
+// Card returns the current card.
+func (s *Scanner) Card() *Card {
+	return s.card
+}
+
+// Err returns the first error encountered.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// readCard reads the next card and queues its subentries. It returns nil at
+// the end of the file.
+func (s *Scanner) readCard() (*Card, error) {
+	var headwords []string
+	var body []string
+	cardLine := 0
+	for {
+		line, ok, err := s.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		indented := line[0] == ' ' || line[0] == '\t'
+		if !indented {
+			if len(body) > 0 {
+				// The start of the next card.
+				s.unreadLine(line)
+				break
+			}
+			if cardLine == 0 {
+				cardLine = s.line
+			}
+			headwords = append(headwords, line)
+			continue
+		}
+
+		if len(headwords) == 0 {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrSyntax, s.line, errNoHeadword)
+		}
+		body = append(body, strings.TrimLeft(line, " \t"))
+	}
+
+	if len(headwords) == 0 {
+		return nil, nil
+	}
+	if len(body) == 0 {
+		return nil, fmt.Errorf("%w: line %d: %w", ErrSyntax, cardLine, errNoBody)
+	}
+
+	card := &Card{}
+	for _, h := range headwords {
+		card.Headwords = appendUnique(card.Headwords, ParseHeadword(h)...)
+	}
+	if len(card.Headwords) == 0 {
+		return nil, fmt.Errorf("%w: line %d: %w", ErrSyntax, cardLine, errNoHeadword)
+	}
+	tilde := escapeText(card.Headwords[0])
+
+	var lines []string
+	var sub *Card
+	var subLines []string
+	endSub := func() {
+		if sub != nil && len(sub.Headwords) > 0 {
+			sub.Body = replaceTilde(strings.Join(subLines, "\n"), tilde)
+			s.pending = append(s.pending, sub)
+		}
+		sub = nil
+		subLines = nil
+	}
+	for _, line := range body {
+		if strings.HasPrefix(line, "@") {
+			endSub()
+			if h := strings.TrimSpace(line[1:]); h != "" {
+				sub = &Card{
+					Headwords: ParseHeadword(strings.ReplaceAll(h, "~", tilde)),
+				}
+			}
+			continue
+		}
+		if sub != nil {
+			subLines = append(subLines, line)
+			continue
+		}
+		lines = append(lines, line)
+	}
+	endSub()
+
+	card.Body = replaceTilde(strings.Join(lines, "\n"), tilde)
+	return card, nil
+}
+
+// replaceTilde replaces unescaped tildes in the body with the headword.
+func replaceTilde(body, headword string) string {
+	if !strings.Contains(body, "~") {
+		return body
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		switch {
+		case body[i] == '\\' && i+1 < len(body):
+			_ = b.WriteByte(body[i])
+			_ = b.WriteByte(body[i+1])
+			i++
+		case body[i] == '~':
+			_, _ = b.WriteString(headword)
+		default:
+			_ = b.WriteByte(body[i])
+		}
+	}
+	return b.String()
+}
+
+// ParseHeadword parses a DSL headword and returns its forms. Escape
+// sequences are unescaped, unsorted parts in {braces} are removed, and
+// optional parts in (parentheses) are expanded so that the first form
+// includes all optional parts and the last form includes none of them.
+func ParseHeadword(h string) []string {
+	// parts alternates between required and optional text.
+	parts := [][]byte{nil}
+	optional := false
+	unsorted := false
+	for i := 0; i < len(h); i++ {
+		c := h[i]
+		switch {
+		case c == '\\' && i+1 < len(h):
+			i++
+			if !unsorted {
+				parts[len(parts)-1] = append(parts[len(parts)-1], h[i])
+			}
+		case c == '{' && !unsorted:
+			unsorted = true
+		case c == '}' && unsorted:
+			unsorted = false
+		case unsorted:
+		case c == '(' && !optional:
+			optional = true
+			parts = append(parts, nil)
+		case c == ')' && optional:
+			optional = false
+			parts = append(parts, nil)
+		default:
+			parts[len(parts)-1] = append(parts[len(parts)-1], c)
+		}
+	}
+
+	// Expand the optional parts. Optional parts are at odd indexes.
+	forms := []string{""}
+	for i, p := range parts {
+		if i%2 == 0 {
+			for j := range forms {
+				forms[j] += string(p)
+			}
+			continue
+		}
+		with := make([]string, 0, len(forms)*2)
+		for _, f := range forms {
+			with = append(with, f+string(p))
+		}
+		forms = append(with, forms...)
+	}
+
+	var result []string
+	for _, f := range forms {
+		if f = strings.Join(strings.Fields(f), " "); f != "" {
+			result = appendUnique(result, f)
+		}
+	}
+	return result
+}
+
+// appendUnique appends the values that are not already in s.
+func appendUnique(s []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, e := range s {
+			if e == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			s = append(s, v)
+		}
+	}
+	return s
+}
+
+// ImportOptions are options for Import.
+type ImportOptions struct {
+	// DataType is the type of the card data. Cards are converted to HTML for
+	// dict.HTMLType ('h') and to plain text for dict.UTFTextType ('m'). The
+	// default is dict.HTMLType.
+	DataType dict.DataType
+}
+
+// Import reads the cards from the scanner and adds them to the Builder. The
+// first headword of each card is added as the entry word and the remaining
+// headwords as synonyms. It returns the number of entries added.
+func Import(s *Scanner, b *stardict.Builder, options *ImportOptions) (int, error) {
+	if options == nil {
+		options = &ImportOptions{}
+	}
+	t := options.DataType
+	if t == 0 {
+		t = dict.HTMLType
+	}
+	var convert func(string) string
+	switch t {
+	case dict.HTMLType:
+		convert = HTML
+	case dict.UTFTextType:
+		convert = Text
+	default:
+		return 0, fmt.Errorf("%w: %q", errDataType, t)
+	}
+
+	n := 0
+	for s.Scan() {
+		c := s.Card()
+		if err := b.AddEntry(c.Headwords[0], c.Headwords[1:], &dict.Data{
+			Type: t,
+			Data: []byte(convert(c.Body)),
+		}); err != nil {
+			return n, fmt.Errorf("adding %q: %w", c.Headwords[0], err)
+		}
+		n++
+	}
+	if err := s.Err(); err != nil {
+		return n, err
+	}
+	return n, nil
+}
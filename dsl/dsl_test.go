@@ -0,0 +1,351 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsl
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/text/encoding/unicode"
+
+	"github.com/ianlewis/go-stardict"
+	"github.com/ianlewis/go-stardict/dict"
+)
+
+func TestScanner(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		header   *Header
+		expected []*Card
+		err      error
+	}{
+		{
+			name:   "empty",
+			input:  "",
+			header: &Header{},
+		},
+		{
+			name: "header",
+			input: `#NAME "Example"
+#INDEX_LANGUAGE "English"
+#CONTENTS_LANGUAGE "Russian"
+
+hoge
+	[m1]hoge body[/m]
+`,
+			header: &Header{
+				Name:             "Example",
+				IndexLanguage:    "English",
+				ContentsLanguage: "Russian",
+			},
+			expected: []*Card{
+				{Headwords: []string{"hoge"}, Body: "[m1]hoge body[/m]"},
+			},
+		},
+		{
+			name: "multiple headwords",
+			input: `hoge
+fuga
+  [m1][trn]body[/trn][/m]
+  [m2][ex]example[/ex][/m]
+
+piyo
+	piyo body
+`,
+			header: &Header{},
+			expected: []*Card{
+				{Headwords: []string{"hoge", "fuga"}, Body: "[m1][trn]body[/trn][/m]\n[m2][ex]example[/ex][/m]"},
+				{Headwords: []string{"piyo"}, Body: "piyo body"},
+			},
+		},
+		{
+			name: "cards without blank lines",
+			input: `hoge
+	hoge body
+fuga
+	fuga body
+`,
+			header: &Header{},
+			expected: []*Card{
+				{Headwords: []string{"hoge"}, Body: "hoge body"},
+				{Headwords: []string{"fuga"}, Body: "fuga body"},
+			},
+		},
+		{
+			name: "comments",
+			input: `{{header comment}}
+hoge{{comment}}
+	hoge {{inline}}body
+	{{multi
+	line
+	comment}}
+	second \{{line
+`,
+			header: &Header{},
+			expected: []*Card{
+				{Headwords: []string{"hoge"}, Body: "hoge body\nsecond \\{{line"},
+			},
+		},
+		{
+			name: "headword variants",
+			input: `colo(u)r {the} \(escaped\)
+	body
+`,
+			header: &Header{},
+			expected: []*Card{
+				{Headwords: []string{"colour (escaped)", "color (escaped)"}, Body: "body"},
+			},
+		},
+		{
+			name: "tilde",
+			input: `hoge
+	~ and \~
+`,
+			header: &Header{},
+			expected: []*Card{
+				{Headwords: []string{"hoge"}, Body: "hoge and \\~"},
+			},
+		},
+		{
+			name: "subentries",
+			input: `hoge
+	[m1]hoge body[/m]
+	@ ~ fuga
+	[m1]fuga body[/m]
+	@
+	[m1]more hoge[/m]
+	@ piyo
+	[m1]piyo body[/m]
+`,
+			header: &Header{},
+			expected: []*Card{
+				{Headwords: []string{"hoge"}, Body: "[m1]hoge body[/m]\n[m1]more hoge[/m]"},
+				{Headwords: []string{"hoge fuga"}, Body: "[m1]fuga body[/m]"},
+				{Headwords: []string{"piyo"}, Body: "[m1]piyo body[/m]"},
+			},
+		},
+		{
+			name:   "body without headword",
+			input:  "\tbody\n",
+			header: &Header{},
+			err:    ErrSyntax,
+		},
+		{
+			name:   "card without body",
+			input:  "hoge\n\nfuga\n",
+			header: &Header{},
+			err:    ErrSyntax,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := NewScanner(strings.NewReader(test.input))
+			if err != nil {
+				t.Fatalf("NewScanner: %v", err)
+			}
+			if diff := cmp.Diff(test.header, s.Header()); diff != "" {
+				t.Errorf("Header (-want, +got):\n%s", diff)
+			}
+
+			var cards []*Card
+			for s.Scan() {
+				cards = append(cards, s.Card())
+			}
+			if diff := cmp.Diff(test.expected, cards); diff != "" {
+				t.Errorf("cards (-want, +got):\n%s", diff)
+			}
+			if err := s.Err(); !errors.Is(err, test.err) {
+				t.Errorf("Err: expected %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestScanner_encoding(t *testing.T) {
+	t.Parallel()
+
+	input := "#NAME \"例\"\r\n\r\n辞書\r\n\t[m1]じしょ[/m]\r\n"
+	expected := []*Card{
+		{Headwords: []string{"辞書"}, Body: "[m1]じしょ[/m]"},
+	}
+
+	tests := []struct {
+		name   string
+		encode func(string) []byte
+	}{
+		{
+			name: "utf-8",
+			encode: func(s string) []byte {
+				return []byte(s)
+			},
+		},
+		{
+			name: "utf-8 bom",
+			encode: func(s string) []byte {
+				return append([]byte{0xef, 0xbb, 0xbf}, s...)
+			},
+		},
+		{
+			name: "utf-16le bom",
+			encode: func(s string) []byte {
+				b, _ := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes([]byte(s))
+				return b
+			},
+		},
+		{
+			name: "utf-16le",
+			encode: func(s string) []byte {
+				b, _ := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder().Bytes([]byte(s))
+				return b
+			},
+		},
+		{
+			name: "utf-16be bom",
+			encode: func(s string) []byte {
+				b, _ := unicode.UTF16(unicode.BigEndian, unicode.UseBOM).NewEncoder().Bytes([]byte(s))
+				return b
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := NewScanner(bytes.NewReader(test.encode(input)))
+			if err != nil {
+				t.Fatalf("NewScanner: %v", err)
+			}
+			if got, want := s.Header().Name, "例"; got != want {
+				t.Errorf("Name: expected %q, got %q", want, got)
+			}
+
+			var cards []*Card
+			for s.Scan() {
+				cards = append(cards, s.Card())
+			}
+			if err := s.Err(); err != nil {
+				t.Fatalf("Err: %v", err)
+			}
+			if diff := cmp.Diff(expected, cards); diff != "" {
+				t.Errorf("cards (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseHeadword(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		headword string
+		expected []string
+	}{
+		{"hoge", []string{"hoge"}},
+		{"  hoge  fuga ", []string{"hoge fuga"}},
+		{"colo(u)r", []string{"colour", "color"}},
+		{"a(b)c(d)", []string{"abcd", "acd", "abc", "ac"}},
+		{"{to }go", []string{"go"}},
+		{`\{not unsorted\}`, []string{"{not unsorted}"}},
+		{"(optional)", []string{"optional"}},
+		{"{}", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.headword, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(test.expected, ParseHeadword(test.headword)); diff != "" {
+				t.Errorf("ParseHeadword (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	input := `#NAME "Example"
+#INDEX_LANGUAGE "English"
+#CONTENTS_LANGUAGE "Japanese"
+
+dictionary
+lexicon
+	[m1][trn]辞書[/trn][/m]
+	[m1]see <<thesaurus>>[/m]
+`
+	s, err := NewScanner(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("NewScanner: %v", err)
+	}
+
+	info := s.Header().Info()
+	if got, want := info.From, "en"; got != want {
+		t.Errorf("From: expected %q, got %q", want, got)
+	}
+	if got, want := info.To, "ja"; got != want {
+		t.Errorf("To: expected %q, got %q", want, got)
+	}
+
+	b, err := stardict.NewBuilder(info, nil)
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+	n, err := Import(s, b, nil)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if got, want := n, 1; got != want {
+		t.Errorf("Import: expected %d entries, got %d", want, got)
+	}
+
+	dir := t.TempDir()
+	if err := b.Finish(dir, "example"); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	sd, err := stardict.Open(filepath.Join(dir, "example.ifo"), nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer sd.Close()
+
+	entries, err := sd.Search("lexicon")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Search: expected 1 entry, got %d", len(entries))
+	}
+	expected := stardict.DataList{
+		{
+			Type: dict.HTMLType,
+			Data: []byte(`<div style="margin-left:1em"><span class="trn">辞書</span></div>` +
+				`<div style="margin-left:1em">see <a href="bword://thesaurus">thesaurus</a></div>`),
+		},
+	}
+	if diff := cmp.Diff(expected, entries[0].Data()); diff != "" {
+		t.Errorf("Data (-want, +got):\n%s", diff)
+	}
+}
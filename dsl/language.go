@@ -0,0 +1,71 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsl
+
+import (
+	"strings"
+)
+
+// languages maps DSL language names to ISO 639-1 language codes.
+var languages = map[string]string{
+	"Arabic":     "ar",
+	"Chinese":    "zh",
+	"Czech":      "cs",
+	"Danish":     "da",
+	"Dutch":      "nl",
+	"English":    "en",
+	"Finnish":    "fi",
+	"French":     "fr",
+	"German":     "de",
+	"Greek":      "el",
+	"Hebrew":     "he",
+	"Hungarian":  "hu",
+	"Italian":    "it",
+	"Japanese":   "ja",
+	"Korean":     "ko",
+	"Latin":      "la",
+	"Polish":     "pl",
+	"Portuguese": "pt",
+	"Russian":    "ru",
+	"Spanish":    "es",
+	"Swedish":    "sv",
+	"Turkish":    "tr",
+	"Ukrainian":  "uk",
+}
+
+// LanguageCode returns the ISO 639-1 language code for a DSL language name
+// (e.g. "en" for "English"). It returns an empty string if the language is
+// unknown.
+func LanguageCode(name string) string {
+	for n, code := range languages {
+		if strings.EqualFold(n, name) {
+			return code
+		}
+	}
+	return ""
+}
+
+// LanguageName returns the DSL language name for an ISO 639-1 language code
+// (e.g. "English" for "en"). Region and script subtags are ignored. It
+// returns an empty string if the language is unknown.
+func LanguageName(code string) string {
+	code, _, _ = strings.Cut(strings.ReplaceAll(code, "_", "-"), "-")
+	for n, c := range languages {
+		if strings.EqualFold(c, code) {
+			return n
+		}
+	}
+	return ""
+}
@@ -0,0 +1,262 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsl
+
+import (
+	"html"
+	"path"
+	"strings"
+)
+
+// classes are the HTML classes of DSL tags that are converted to spans.
+var classes = map[string]string{
+	"trn":  "trn",
+	"!trs": "trs",
+	"com":  "com",
+	"ex":   "ex",
+	"p":    "abbr",
+	"t":    "transcription",
+	"lang": "lang",
+	"*":    "opt",
+}
+
+// imageExts are the file extensions of [s] media that are shown as images.
+var imageExts = map[string]bool{
+	".bmp":  true,
+	".gif":  true,
+	".jpeg": true,
+	".jpg":  true,
+	".png":  true,
+	".svg":  true,
+	".webp": true,
+}
+
+// token is a DSL markup token.
+type token struct {
+	// text is the unescaped text for text tokens. It is the link target for
+	// reference tokens.
+	text string
+
+	// tag is the tag name for tag tokens. It is empty for text tokens.
+	tag string
+
+	// attr is the tag's attribute (e.g. the color in [c red]).
+	attr string
+
+	// closing is true for closing tags (e.g. [/b]).
+	closing bool
+
+	// ref is true for <<reference>> tokens.
+	ref bool
+}
+
+// tokenize splits DSL markup into text, tag, and reference tokens.
+func tokenize(s string) []token {
+	var tokens []token
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			tokens = append(tokens, token{text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			i++
+			_ = text.WriteByte(s[i])
+		case c == '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				_ = text.WriteByte(c)
+				continue
+			}
+			flush()
+			t := token{}
+			name := s[i+1 : i+end]
+			if strings.HasPrefix(name, "/") {
+				t.closing = true
+				name = name[1:]
+			}
+			t.tag, t.attr, _ = strings.Cut(name, " ")
+			t.attr = strings.TrimSpace(t.attr)
+			tokens = append(tokens, t)
+			i += end
+		case strings.HasPrefix(s[i:], "<<"):
+			end := strings.Index(s[i:], ">>")
+			if end < 0 {
+				_ = text.WriteByte(c)
+				continue
+			}
+			flush()
+			tokens = append(tokens, token{text: unescape(s[i+2 : i+end]), ref: true})
+			i += end + 1
+		default:
+			_ = text.WriteByte(c)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// unescape removes DSL escape backslashes.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		_ = b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// contentUntil returns the text content of tokens up to the closing tag and
+// the index of the closing tag.
+func contentUntil(tokens []token, start int, tag string) (string, int) {
+	var b strings.Builder
+	i := start
+	for ; i < len(tokens); i++ {
+		t := tokens[i]
+		if t.tag == tag && t.closing {
+			break
+		}
+		_, _ = b.WriteString(t.text)
+	}
+	return b.String(), i
+}
+
+// HTML converts DSL markup to HTML. Formatting tags are converted to their
+// HTML equivalents, [mN] indentation is converted to divs with a margin,
+// references are converted to bword:// links, and unknown tags are removed.
+func HTML(body string) string {
+	var b strings.Builder
+	tokens := tokenize(body)
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch {
+		case t.ref:
+			writeLink(&b, "bword://"+t.text, t.text)
+		case t.tag == "":
+			lines := strings.Split(t.text, "\n")
+			for j, line := range lines {
+				if j > 0 && !strings.HasSuffix(b.String(), "</div>") {
+					_, _ = b.WriteString("<br>")
+				}
+				_, _ = b.WriteString(html.EscapeString(line))
+			}
+		case t.closing:
+			writeClosingTag(&b, t.tag)
+		case t.tag == "ref":
+			var word string
+			word, i = contentUntil(tokens, i+1, t.tag)
+			writeLink(&b, "bword://"+word, word)
+		case t.tag == "url":
+			var url string
+			url, i = contentUntil(tokens, i+1, t.tag)
+			writeLink(&b, url, url)
+		case t.tag == "s":
+			var file string
+			file, i = contentUntil(tokens, i+1, t.tag)
+			if imageExts[strings.ToLower(path.Ext(file))] {
+				_, _ = b.WriteString(`<img src="` + html.EscapeString(file) + `">`)
+			} else {
+				writeLink(&b, file, file)
+			}
+		default:
+			writeOpeningTag(&b, t)
+		}
+	}
+	return b.String()
+}
+
+// writeLink writes an HTML link.
+func writeLink(b *strings.Builder, href, text string) {
+	_, _ = b.WriteString(`<a href="` + html.EscapeString(href) + `">` + html.EscapeString(text) + "</a>")
+}
+
+// writeOpeningTag writes the HTML for a DSL opening tag.
+func writeOpeningTag(b *strings.Builder, t token) {
+	switch t.tag {
+	case "b", "i", "u", "sup", "sub":
+		_, _ = b.WriteString("<" + t.tag + ">")
+	case "c":
+		color := t.attr
+		if color == "" {
+			color = "green"
+		}
+		_, _ = b.WriteString(`<font color="` + html.EscapeString(color) + `">`)
+	case "m", "m0", "m1", "m2", "m3", "m4", "m5", "m6", "m7", "m8", "m9":
+		indent := "0"
+		if len(t.tag) > 1 {
+			indent = t.tag[1:]
+		}
+		_, _ = b.WriteString(`<div style="margin-left:` + indent + `em">`)
+	default:
+		if class, ok := classes[t.tag]; ok {
+			_, _ = b.WriteString(`<span class="` + class + `">`)
+		}
+	}
+}
+
+// writeClosingTag writes the HTML for a DSL closing tag.
+func writeClosingTag(b *strings.Builder, tag string) {
+	switch tag {
+	case "b", "i", "u", "sup", "sub":
+		_, _ = b.WriteString("</" + tag + ">")
+	case "c":
+		_, _ = b.WriteString("</font>")
+	case "m":
+		_, _ = b.WriteString("</div>")
+	default:
+		if _, ok := classes[tag]; ok {
+			_, _ = b.WriteString("</span>")
+		}
+	}
+}
+
+// Text converts DSL markup to plain text. Tags are removed and escape
+// sequences are unescaped. Media files in [s] tags are omitted.
+func Text(body string) string {
+	var b strings.Builder
+	tokens := tokenize(body)
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if t.tag == "s" && !t.closing {
+			_, i = contentUntil(tokens, i+1, t.tag)
+			continue
+		}
+		_, _ = b.WriteString(t.text)
+	}
+	return b.String()
+}
+
+// escapeText escapes DSL special characters in text.
+func escapeText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\\', '[', ']', '{', '}', '(', ')', '~', '@', '<', '>':
+			_ = b.WriteByte('\\')
+		}
+		_, _ = b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsl
+
+import (
+	"testing"
+)
+
+func TestHTML(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "text",
+			body:     "a < b\nc",
+			expected: "a &lt; b<br>c",
+		},
+		{
+			name:     "formatting",
+			body:     "[b]bold[/b] [i]italic[/i] [c red]red[/c] [c]green[/c]",
+			expected: `<b>bold</b> <i>italic</i> <font color="red">red</font> <font color="green">green</font>`,
+		},
+		{
+			name:     "indentation",
+			body:     "[m1]one[/m]\n[m2]two[/m]",
+			expected: `<div style="margin-left:1em">one</div><div style="margin-left:2em">two</div>`,
+		},
+		{
+			name:     "classes",
+			body:     "[trn]translation[/trn] [ex]example[/ex] [p]n[/p]",
+			expected: `<span class="trn">translation</span> <span class="ex">example</span> <span class="abbr">n</span>`,
+		},
+		{
+			name:     "references",
+			body:     "<<hoge>> [ref]fuga[/ref] [url]https://example.com[/url]",
+			expected: `<a href="bword://hoge">hoge</a> <a href="bword://fuga">fuga</a> <a href="https://example.com">https://example.com</a>`,
+		},
+		{
+			name:     "media",
+			body:     "[s]picture.png[/s][s]sound.wav[/s]",
+			expected: `<img src="picture.png"><a href="sound.wav">sound.wav</a>`,
+		},
+		{
+			name:     "unknown tags and escapes",
+			body:     `[lang id=1033]\[text\][/lang] [foo]bar[/foo]`,
+			expected: `<span class="lang">[text]</span> bar`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := HTML(test.body); got != test.expected {
+				t.Errorf("HTML: expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	t.Parallel()
+
+	body := "[m1][b]hoge[/b] \\[1\\][/m]\n[m2]see <<fuga>>[s]sound.wav[/s][/m]"
+	expected := "hoge [1]\nsee fuga"
+	if got := Text(body); got != expected {
+		t.Errorf("Text: expected %q, got %q", expected, got)
+	}
+}
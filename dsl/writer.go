@@ -0,0 +1,196 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+
+	"github.com/ianlewis/go-stardict"
+)
+
+// defaultLanguage is the language written to the header when the language
+// is unknown.
+const defaultLanguage = "English"
+
+// WriterOptions are options for a Writer.
+type WriterOptions struct {
+	// UTF8 indicates that the file should be written in UTF-8 rather than
+	// UTF-16 (little-endian) which is the default. In both cases, the file
+	// starts with a byte order mark.
+	UTF8 bool
+}
+
+// Writer writes DSL files.
+type Writer struct {
+	w  *bufio.Writer
+	tw *transform.Writer
+}
+
+// NewWriter returns a new Writer that writes the header to w. The Close
+// method must be called to finish writing the file.
+func NewWriter(w io.Writer, h *Header, options *WriterOptions) (*Writer, error) {
+	if options == nil {
+		options = &WriterOptions{}
+	}
+
+	enc := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder()
+	if options.UTF8 {
+		enc = unicode.UTF8BOM.NewEncoder()
+	}
+	tw := transform.NewWriter(w, enc)
+	dw := &Writer{
+		w:  bufio.NewWriter(tw),
+		tw: tw,
+	}
+
+	indexLang := h.IndexLanguage
+	if indexLang == "" {
+		indexLang = defaultLanguage
+	}
+	contentsLang := h.ContentsLanguage
+	if contentsLang == "" {
+		contentsLang = indexLang
+	}
+	for _, line := range []string{
+		`#NAME "` + strings.ReplaceAll(h.Name, `"`, "'") + `"`,
+		`#INDEX_LANGUAGE "` + indexLang + `"`,
+		`#CONTENTS_LANGUAGE "` + contentsLang + `"`,
+	} {
+		if err := dw.writeLine(line); err != nil {
+			return nil, err
+		}
+	}
+
+	return dw, nil
+}
+
+// Write writes the card. Headwords are escaped. The body must be in DSL
+// markup and each line of the body is indented.
+func (w *Writer) Write(c *Card) error {
+	if err := w.writeLine(""); err != nil {
+		return err
+	}
+	for _, h := range c.Headwords {
+		h = escapeText(h)
+		if strings.HasPrefix(h, "#") {
+			h = `\` + h
+		}
+		if err := w.writeLine(h); err != nil {
+			return err
+		}
+	}
+	for _, line := range strings.Split(c.Body, "\n") {
+		if err := w.writeLine("\t" + line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeLine writes a line with a CRLF line ending.
+func (w *Writer) writeLine(line string) error {
+	if _, err := w.w.WriteString(line + "\r\n"); err != nil {
+		return fmt.Errorf("writing DSL file: %w", err)
+	}
+	return nil
+}
+
+// Close flushes the data to the underlying writer. It does not close the
+// underlying writer.
+func (w *Writer) Close() error {
+	if err := w.w.Flush(); err != nil {
+		return fmt.Errorf("writing DSL file: %w", err)
+	}
+	if err := w.tw.Close(); err != nil {
+		return fmt.Errorf("writing DSL file: %w", err)
+	}
+	return nil
+}
+
+// NewCard returns a DSL card for the dictionary entry. The entry's synonyms
+// are added as headwords. Each line of the entry's text data is written as
+// an [m1] paragraph. Binary data is omitted.
+func NewCard(e *stardict.Entry) *Card {
+	c := &Card{
+		Headwords: append([]string{e.Title()}, e.Synonyms()...),
+	}
+
+	var lines []string
+	for _, d := range e.Data() {
+		for _, line := range strings.Split(d.String(), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, "[m1]"+escapeText(line)+"[/m]")
+			}
+		}
+	}
+	if len(lines) == 0 {
+		// Cards must have a body.
+		lines = append(lines, "[m1][/m]")
+	}
+	c.Body = strings.Join(lines, "\n")
+
+	return c
+}
+
+// Export writes every entry in the dictionary to w as a DSL file and returns
+// the number of cards written. Entries are read in .idx file order.
+func Export(s *stardict.Stardict, w io.Writer, options *WriterOptions) (int, error) {
+	info, err := s.Info()
+	if err != nil {
+		//nolint:wrapcheck // error is already wrapped.
+		return 0, err
+	}
+
+	// Fall back to the lang key which may contain a language pair (e.g.
+	// "en-ja").
+	from, to := info.From, info.To
+	if from == "" && to == "" {
+		var ok bool
+		from, to, ok = strings.Cut(info.Lang, "-")
+		if !ok {
+			to = from
+		}
+	}
+
+	dw, err := NewWriter(w, &Header{
+		Name:             s.Bookname(),
+		IndexLanguage:    LanguageName(from),
+		ContentsLanguage: LanguageName(to),
+	}, options)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	if err := s.Walk(func(e *stardict.Entry) error {
+		if err := dw.Write(NewCard(e)); err != nil {
+			return err
+		}
+		n++
+		return nil
+	}); err != nil {
+		return n, fmt.Errorf("exporting %q: %w", s.Bookname(), err)
+	}
+	if err := dw.Close(); err != nil {
+		return n, err
+	}
+	return n, nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsl
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict"
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/ifo"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, &Header{
+		Name: "hoge",
+	}, &WriterOptions{
+		UTF8: true,
+	})
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	if err := w.Write(&Card{
+		Headwords: []string{"#hoge", "a(b)"},
+		Body:      "[m1]body[/m]\n[m2]more[/m]",
+	}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	expected := "\uFEFF" + `#NAME "hoge"` + "\r\n" +
+		`#INDEX_LANGUAGE "English"` + "\r\n" +
+		`#CONTENTS_LANGUAGE "English"` + "\r\n" +
+		"\r\n" +
+		`\#hoge` + "\r\n" +
+		`a\(b\)` + "\r\n" +
+		"\t[m1]body[/m]\r\n" +
+		"\t[m2]more[/m]\r\n"
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Errorf("Write (-want, +got):\n%s", diff)
+	}
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	b, err := stardict.NewBuilder(&ifo.Info{
+		BookName: "hoge",
+		Lang:     "en-ja",
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+	if err := b.AddEntry("dictionary", []string{"lexicon"},
+		&dict.Data{Type: dict.UTFTextType, Data: []byte("辞書 [じしょ]\nsee ~thesaurus")},
+		&dict.Data{Type: dict.WavType, Data: []byte{0, 1}},
+	); err != nil {
+		t.Fatalf("AddEntry: %v", err)
+	}
+	if err := b.AddEntry("thesaurus", nil,
+		&dict.Data{Type: dict.HTMLType, Data: []byte("<b>類語辞典</b>")},
+	); err != nil {
+		t.Fatalf("AddEntry: %v", err)
+	}
+	dir := t.TempDir()
+	if err := b.Finish(dir, "hoge"); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+
+	s, err := stardict.Open(filepath.Join(dir, "hoge.ifo"), nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+
+	var buf bytes.Buffer
+	n, err := Export(s, &buf, nil)
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if got, want := n, 2; got != want {
+		t.Errorf("Export: expected %d cards, got %d", want, got)
+	}
+
+	// Read the exported UTF-16 file back.
+	sc, err := NewScanner(&buf)
+	if err != nil {
+		t.Fatalf("NewScanner: %v", err)
+	}
+	expectedHeader := &Header{
+		Name:             "hoge",
+		IndexLanguage:    "English",
+		ContentsLanguage: "Japanese",
+	}
+	if diff := cmp.Diff(expectedHeader, sc.Header()); diff != "" {
+		t.Errorf("Header (-want, +got):\n%s", diff)
+	}
+
+	var cards []*Card
+	for sc.Scan() {
+		cards = append(cards, sc.Card())
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	expected := []*Card{
+		{
+			Headwords: []string{"dictionary", "lexicon"},
+			Body:      "[m1]辞書 \\[じしょ\\][/m]\n[m1]see \\~thesaurus[/m]",
+		},
+		{
+			Headwords: []string{"thesaurus"},
+			Body:      "[m1]類語辞典[/m]",
+		},
+	}
+	if diff := cmp.Diff(expected, cards); diff != "" {
+		t.Errorf("cards (-want, +got):\n%s", diff)
+	}
+	if got, want := Text(cards[0].Body), "辞書 [じしょ]\nsee ~thesaurus"; got != want {
+		t.Errorf("Text: expected %q, got %q", want, got)
+	}
+}
@@ -35,8 +35,9 @@ func (l DataList) String() string {
 
 // Entry is a dictionary entry.
 type Entry struct {
-	word string
-	data DataList
+	word     string
+	synonyms []string
+	data     DataList
 }
 
 // Title return the entry's title.
@@ -44,6 +45,12 @@ func (e *Entry) Title() string {
 	return e.word
 }
 
+// Synonyms returns the synonyms that refer to the entry. Synonyms are only
+// set for entries returned by Walk.
+func (e *Entry) Synonyms() []string {
+	return e.synonyms
+}
+
 // Data returns the entry's data entries.
 func (e *Entry) Data() DataList {
 	return e.data
@@ -0,0 +1,242 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package export implements exporting dictionaries to JSON Lines and CSV.
+//
+// Each entry is exported with its word, synonyms, and typed data. String data
+// is exported as text and binary data (e.g. wav or picture data) is base64
+// encoded. String data that isn't valid UTF-8 is also base64 encoded so that
+// it is exported without loss.
+package export
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ianlewis/go-stardict"
+	"github.com/ianlewis/go-stardict/dict"
+)
+
+// Format is an export format.
+type Format string
+
+const (
+	// JSONL is the JSON Lines format. Each line contains a JSON encoded
+	// Record.
+	JSONL Format = "jsonl"
+
+	// CSV is the CSV format. Each row contains the word, synonyms separated
+	// by '|', data type, data encoding, and data. Entries with multiple data
+	// items are written as multiple rows. Entries without data are written as
+	// a single row with empty type, encoding, and data.
+	CSV Format = "csv"
+)
+
+// Base64Encoding is the Encoding of base64 encoded binary data.
+const Base64Encoding = "base64"
+
+// ErrUnsupportedFormat indicates that the export format is not supported.
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// csvHeader is the header row of CSV exports.
+var csvHeader = []string{"word", "synonyms", "type", "encoding", "data"}
+
+// Record is an exported dictionary entry.
+type Record struct {
+	// Word is the entry's headword.
+	Word string `json:"word"`
+
+	// Synonyms are the synonyms that refer to the entry.
+	Synonyms []string `json:"synonyms,omitempty"`
+
+	// Data is the entry's data.
+	Data []*Data `json:"data"`
+}
+
+// Data is an exported data item.
+type Data struct {
+	// Type is the data type (e.g. "m" or "h").
+	Type string `json:"type"`
+
+	// Encoding is Base64Encoding for binary data and string data that isn't
+	// valid UTF-8 and is empty otherwise.
+	Encoding string `json:"encoding,omitempty"`
+
+	// Data is the data as text or base64 encoded binary data.
+	Data string `json:"data"`
+}
+
+// NewRecord returns a new Record for the entry.
+func NewRecord(e *stardict.Entry) *Record {
+	r := &Record{
+		Word:     e.Title(),
+		Synonyms: e.Synonyms(),
+		Data:     []*Data{},
+	}
+	for _, d := range e.Data() {
+		r.Data = append(r.Data, newData(d))
+	}
+	return r
+}
+
+// newData returns the exported data. File-like data types are upper case.
+// String data that isn't valid UTF-8 (e.g. legacy encoded locale text) is
+// base64 encoded because JSON can only hold valid UTF-8 text.
+func newData(d *dict.Data) *Data {
+	if ('A' <= d.Type && d.Type <= 'Z') || !utf8.Valid(d.Data) {
+		return &Data{
+			Type:     string(d.Type),
+			Encoding: Base64Encoding,
+			Data:     base64.StdEncoding.EncodeToString(d.Data),
+		}
+	}
+	return &Data{
+		Type: string(d.Type),
+		Data: string(d.Data),
+	}
+}
+
+// Writer writes exported entries.
+type Writer interface {
+	// Write writes the entry.
+	Write(e *stardict.Entry) error
+
+	// Flush writes any buffered data to the underlying writer.
+	Flush() error
+}
+
+// NewWriter returns a new Writer for the format.
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case JSONL:
+		return NewJSONLWriter(w), nil
+	case CSV:
+		return NewCSVWriter(w), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// JSONLWriter writes entries in the JSON Lines format.
+type JSONLWriter struct {
+	enc *json.Encoder
+}
+
+// NewJSONLWriter returns a new JSONLWriter that writes to w.
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &JSONLWriter{
+		enc: enc,
+	}
+}
+
+// Write writes the entry as a line of JSON.
+func (w *JSONLWriter) Write(e *stardict.Entry) error {
+	if err := w.enc.Encode(NewRecord(e)); err != nil {
+		return fmt.Errorf("writing JSON: %w", err)
+	}
+	return nil
+}
+
+// Flush does nothing as JSONLWriter is not buffered.
+func (w *JSONLWriter) Flush() error {
+	return nil
+}
+
+// CSVWriter writes entries in the CSV format. A header row is written before
+// the first entry.
+type CSVWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// NewCSVWriter returns a new CSVWriter that writes to w.
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{
+		w: csv.NewWriter(w),
+	}
+}
+
+// Write writes a row for each of the entry's data items. A single row with
+// empty data fields is written for entries without data.
+func (w *CSVWriter) Write(e *stardict.Entry) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	r := NewRecord(e)
+	synonyms := strings.Join(r.Synonyms, "|")
+	data := r.Data
+	if len(data) == 0 {
+		data = []*Data{{}}
+	}
+	for _, d := range data {
+		if err := w.w.Write([]string{r.Word, synonyms, d.Type, d.Encoding, d.Data}); err != nil {
+			return fmt.Errorf("writing CSV: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeHeader writes the header row if it hasn't been written yet.
+func (w *CSVWriter) writeHeader() error {
+	if w.wroteHeader {
+		return nil
+	}
+	w.wroteHeader = true
+	if err := w.w.Write(csvHeader); err != nil {
+		return fmt.Errorf("writing CSV: %w", err)
+	}
+	return nil
+}
+
+// Flush writes any buffered data to the underlying writer. The header row is
+// written if no entries were written.
+func (w *CSVWriter) Flush() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.w.Flush()
+	if err := w.w.Error(); err != nil {
+		return fmt.Errorf("writing CSV: %w", err)
+	}
+	return nil
+}
+
+// Export writes every entry in the dictionary to w in .idx file order and
+// returns the number of entries written. Entries are read one at a time so
+// the full index is not loaded into memory.
+func Export(s *stardict.Stardict, w Writer) (int, error) {
+	n := 0
+	if err := s.Walk(func(e *stardict.Entry) error {
+		if err := w.Write(e); err != nil {
+			return err
+		}
+		n++
+		return nil
+	}); err != nil {
+		return n, fmt.Errorf("exporting %q: %w", s.Bookname(), err)
+	}
+	if err := w.Flush(); err != nil {
+		return n, err
+	}
+	return n, nil
+}
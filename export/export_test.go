@@ -0,0 +1,135 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict"
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/ifo"
+)
+
+// makeDict creates a dictionary for testing and opens it.
+func makeDict(t *testing.T) *stardict.Stardict {
+	t.Helper()
+
+	b, err := stardict.NewBuilder(&ifo.Info{
+		BookName: "hoge",
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+	if err := b.AddEntry("hoge", []string{"piyo"},
+		&dict.Data{Type: dict.HTMLType, Data: []byte("<b>hoge</b>\nline2")},
+		&dict.Data{Type: dict.WavType, Data: []byte{0, 1, 2}},
+	); err != nil {
+		t.Fatalf("AddEntry: %v", err)
+	}
+	if err := b.AddEntry("fuga", nil,
+		&dict.Data{Type: dict.UTFTextType, Data: []byte("fuga, \"data\"")},
+	); err != nil {
+		t.Fatalf("AddEntry: %v", err)
+	}
+	// Text with a stray byte that isn't valid UTF-8.
+	if err := b.AddEntry("bar", nil,
+		&dict.Data{Type: dict.UTFTextType, Data: []byte{'b', 0xe4, 'r'}},
+	); err != nil {
+		t.Fatalf("AddEntry: %v", err)
+	}
+	// An entry without data.
+	if err := b.AddEntry("piyo", []string{"pico"}); err != nil {
+		t.Fatalf("AddEntry: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := b.Finish(dir, "hoge"); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+
+	s, err := stardict.Open(filepath.Join(dir, "hoge.ifo"), nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{
+			format: JSONL,
+			expected: `{"word":"bar","data":[{"type":"m","encoding":"base64","data":"YuRy"}]}
+{"word":"fuga","data":[{"type":"m","data":"fuga, \"data\""}]}
+{"word":"hoge","synonyms":["piyo"],"data":[{"type":"h","data":"<b>hoge</b>\nline2"},` +
+				`{"type":"W","encoding":"base64","data":"AAEC"}]}
+{"word":"piyo","synonyms":["pico"],"data":[]}
+`,
+		},
+		{
+			format: CSV,
+			expected: `word,synonyms,type,encoding,data
+bar,,m,base64,YuRy
+fuga,,m,,"fuga, ""data"""
+hoge,piyo,h,,"<b>hoge</b>
+line2"
+hoge,piyo,W,base64,AAEC
+piyo,pico,,,
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.format), func(t *testing.T) {
+			t.Parallel()
+
+			s := makeDict(t)
+
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, test.format)
+			if err != nil {
+				t.Fatalf("NewWriter: %v", err)
+			}
+			n, err := Export(s, w)
+			if err != nil {
+				t.Fatalf("Export: %v", err)
+			}
+			if got, want := n, 4; got != want {
+				t.Errorf("Export: expected %d entries, got %d", want, got)
+			}
+			if diff := cmp.Diff(test.expected, buf.String()); diff != "" {
+				t.Errorf("Export (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewWriter_unsupported(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewWriter(&bytes.Buffer{}, Format("xml")); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("NewWriter: expected %v, got %v", ErrUnsupportedFormat, err)
+	}
+}
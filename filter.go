@@ -0,0 +1,73 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stardict
+
+import (
+	"fmt"
+
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/idx"
+)
+
+// FilterFunc reports whether the entry with the index word and dict word
+// should be kept.
+type FilterFunc func(idxWord *idx.Word, dictWord *dict.Word) bool
+
+// Filter adds the entries in the dictionary for which keep returns true to
+// the Builder and returns the number of entries added. Synonyms are kept
+// only if the entry they refer to is kept and are indexed to the entry's new
+// position when the Builder is finished.
+//
+// Unlike [Stardict.Walk], Filter reads all of the dictionary's synonyms into
+// memory before reading the entries so its memory use grows with the size of
+// the .syn file. Entry data is read one entry at a time.
+func Filter(s *Stardict, b *Builder, keep FilterFunc) (int, error) {
+	synonyms, err := s.synonyms()
+	if err != nil {
+		return 0, err
+	}
+
+	d, err := s.Dict()
+	if err != nil {
+		return 0, err
+	}
+
+	sc, err := s.IndexScanner()
+	if err != nil {
+		return 0, err
+	}
+	defer sc.Close()
+
+	n := 0
+	var i uint32
+	for sc.Scan() {
+		idxWord := sc.Word()
+		dictWord, err := d.Word(idxWord)
+		if err != nil {
+			return n, fmt.Errorf("reading word %q: %w", idxWord.Word, err)
+		}
+		if keep(idxWord, dictWord) {
+			if err := b.AddEntry(idxWord.Word, synonyms[i], dictWord.Data...); err != nil {
+				return n, fmt.Errorf("adding %q: %w", idxWord.Word, err)
+			}
+			n++
+		}
+		i++
+	}
+	if err := sc.Err(); err != nil {
+		return n, fmt.Errorf("scanning index: %w", err)
+	}
+	return n, nil
+}
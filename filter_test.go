@@ -0,0 +1,98 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stardict
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/idx"
+	"github.com/ianlewis/go-stardict/ifo"
+)
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	s := buildDict(t, &ifo.Info{BookName: "hoge"}, []mergeTestEntry{
+		{word: "apple", synonyms: []string{"ringo"}, data: "a fruit"},
+		{word: "banana", synonyms: []string{"plantain"}, data: "a fruit"},
+		{word: "carrot", synonyms: []string{"ninjin"}, data: "a vegetable"},
+		{word: "durian", synonyms: []string{"dorian"}, data: "a fruit"},
+	})
+
+	b, err := NewBuilder(&ifo.Info{BookName: "filtered"}, nil)
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+	n, err := Filter(s, b, func(idxWord *idx.Word, dictWord *dict.Word) bool {
+		return idxWord.Word != "banana" && strings.Contains(dictWord.Data[0].String(), "fruit")
+	})
+	if err != nil {
+		t.Fatalf("Filter: %v", err)
+	}
+	if got, want := n, 2; got != want {
+		t.Errorf("Filter: expected %d entries, got %d", want, got)
+	}
+	dir := t.TempDir()
+	if err := b.Finish(dir, "filtered"); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+
+	filtered, err := Open(filepath.Join(dir, "filtered.ifo"), nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer filtered.Close()
+
+	if got, want := filtered.SynWordCount(), int64(2); got != want {
+		t.Errorf("SynWordCount: expected %d, got %d", want, got)
+	}
+
+	var entries []*Entry
+	if err := filtered.Walk(func(e *Entry) error {
+		entries = append(entries, e)
+		return nil
+	}); err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+	expected := []*Entry{
+		{
+			word:     "apple",
+			synonyms: []string{"ringo"},
+			data:     DataList{{Type: dict.UTFTextType, Data: []byte("a fruit")}},
+		},
+		{
+			word:     "durian",
+			synonyms: []string{"dorian"},
+			data:     DataList{{Type: dict.UTFTextType, Data: []byte("a fruit")}},
+		},
+	}
+	if diff := cmp.Diff(expected, entries, cmp.AllowUnexported(Entry{})); diff != "" {
+		t.Errorf("Walk (-want, +got):\n%s", diff)
+	}
+
+	// Synonyms refer to the entry's new position.
+	found, err := filtered.Search("dorian")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(found) != 1 || found[0].Title() != "durian" {
+		t.Errorf("Search: expected durian, got %v", found)
+	}
+}
@@ -0,0 +1,408 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package idx
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// cacheMagic is the header written at the start of folded index cache files.
+const cacheMagic = "StarDict folded index cache\n"
+
+// cacheVersion is the version of the folded index cache file format. It must
+// be incremented when the format changes.
+const cacheVersion = 1
+
+const (
+	// cacheWordSize is the size of a word record. Each record holds the
+	// offset and length of the word in the string table followed by the
+	// entry's .dict offset and size.
+	cacheWordSize = 4 + 4 + 8 + 4
+
+	// cacheEntrySize is the size of an index entry record. Each record holds
+	// the offset and length of the folded word in the string table, the
+	// number of the word record, and flags.
+	cacheEntrySize = 4 + 4 + 4 + 4
+
+	// cacheSynFlag is set in the flags of entries for synonyms.
+	cacheSynFlag = 1
+)
+
+var errInvalidFoldedCache = errors.New("invalid folded index cache")
+
+// foldedCache is a folded and sorted index read from a memory-mapped cache
+// file.
+//
+// The cache file begins with the magic string followed by the little-endian
+// 32-bit format version, the SHA-256 checksum of the .idx and .syn data, the
+// length and value of the folder ID, and the number of word and index entry
+// records. The word records, index entry records (sorted by folded word),
+// and the string table follow.
+type foldedCache struct {
+	words   []byte
+	entries []byte
+	strings []byte
+
+	// unmap releases the memory-mapped data.
+	unmap func() error
+}
+
+// cacheKey is the key that a cache file must match to be used.
+type cacheKey struct {
+	checksum [sha256.Size]byte
+	folderID string
+}
+
+// cacheChecksum returns the SHA-256 checksum of the .idx data read from
+// idxReader and the .syn data read from synReader, which may be nil.
+func cacheChecksum(idxReader, synReader io.Reader) ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+	h := sha256.New()
+
+	n, err := io.Copy(h, idxReader)
+	if err != nil {
+		return sum, fmt.Errorf("reading .idx file: %w", err)
+	}
+	// Include the size of the .idx data so that data can't move between
+	// the .idx and .syn files without changing the checksum.
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(n)) //nolint:gosec // n is not negative.
+	_, _ = h.Write(b[:])
+
+	if synReader != nil {
+		if _, err := io.Copy(h, synReader); err != nil {
+			return sum, fmt.Errorf("reading .syn file: %w", err)
+		}
+	}
+
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
+
+// openFoldedCache memory-maps the cache file at path and returns the cache
+// if it matches the key.
+func openFoldedCache(path string, key *cacheKey) (*foldedCache, error) {
+	data, unmap, err := mmapFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := newFoldedCache(data, key)
+	if err != nil {
+		_ = unmap()
+		return nil, err
+	}
+	c.unmap = unmap
+	return c, nil
+}
+
+// newFoldedCache returns the cache with the given data if it matches the key.
+func newFoldedCache(data []byte, key *cacheKey) (*foldedCache, error) {
+	b := data
+	next := func(n int) ([]byte, error) {
+		if n < 0 || n > len(b) {
+			return nil, fmt.Errorf("%w: unexpected end of file", errInvalidFoldedCache)
+		}
+		v := b[:n]
+		b = b[n:]
+		return v, nil
+	}
+	nextUint32 := func() (int, error) {
+		v, err := next(4)
+		if err != nil {
+			return 0, err
+		}
+		return int(binary.LittleEndian.Uint32(v)), nil
+	}
+
+	magic, err := next(len(cacheMagic))
+	if err != nil {
+		return nil, err
+	}
+	if string(magic) != cacheMagic {
+		return nil, fmt.Errorf("%w: invalid magic data", errInvalidFoldedCache)
+	}
+	version, err := nextUint32()
+	if err != nil {
+		return nil, err
+	}
+	if version != cacheVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", errInvalidFoldedCache, version)
+	}
+	checksum, err := next(sha256.Size)
+	if err != nil {
+		return nil, err
+	}
+	if string(checksum) != string(key.checksum[:]) {
+		return nil, fmt.Errorf("%w: checksum mismatch", errInvalidFoldedCache)
+	}
+	folderIDLen, err := nextUint32()
+	if err != nil {
+		return nil, err
+	}
+	folderID, err := next(folderIDLen)
+	if err != nil {
+		return nil, err
+	}
+	if string(folderID) != key.folderID {
+		return nil, fmt.Errorf("%w: folder mismatch", errInvalidFoldedCache)
+	}
+
+	numWords, err := nextUint32()
+	if err != nil {
+		return nil, err
+	}
+	numEntries, err := nextUint32()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &foldedCache{}
+	if c.words, err = next(numWords * cacheWordSize); err != nil {
+		return nil, err
+	}
+	if c.entries, err = next(numEntries * cacheEntrySize); err != nil {
+		return nil, err
+	}
+	c.strings = b
+
+	// Validate the records so that the index can be read without errors.
+	for i := range numWords {
+		r := c.words[i*cacheWordSize:]
+		if !c.validString(r) {
+			return nil, fmt.Errorf("%w: word %d out of range", errInvalidFoldedCache, i)
+		}
+	}
+	for i := range numEntries {
+		r := c.entries[i*cacheEntrySize:]
+		if !c.validString(r) || int(binary.LittleEndian.Uint32(r[8:])) >= numWords {
+			return nil, fmt.Errorf("%w: entry %d out of range", errInvalidFoldedCache, i)
+		}
+	}
+
+	return c, nil
+}
+
+// validString returns true if the string offset and length at the start of
+// the record are within the string table.
+func (c *foldedCache) validString(r []byte) bool {
+	off := uint64(binary.LittleEndian.Uint32(r))
+	n := uint64(binary.LittleEndian.Uint32(r[4:]))
+	return off+n <= uint64(len(c.strings))
+}
+
+// string returns the string at the offset and length at the start of the
+// record.
+func (c *foldedCache) string(r []byte) string {
+	off := uint64(binary.LittleEndian.Uint32(r))
+	n := uint64(binary.LittleEndian.Uint32(r[4:]))
+	return string(c.strings[off : off+n])
+}
+
+// len returns the number of index entries.
+func (c *foldedCache) len() int {
+	return len(c.entries) / cacheEntrySize
+}
+
+// folded returns the folded word of the i-th index entry.
+func (c *foldedCache) folded(i int) string {
+	return c.string(c.entries[i*cacheEntrySize:])
+}
+
+// entry returns the i-th index entry.
+func (c *foldedCache) entry(i int) *foldedWord {
+	r := c.entries[i*cacheEntrySize:]
+	w := c.words[int(binary.LittleEndian.Uint32(r[8:]))*cacheWordSize:]
+	return &foldedWord{
+		folded: c.string(r),
+		word: &Word{
+			Word:   c.string(w),
+			Offset: binary.LittleEndian.Uint64(w[8:]),
+			Size:   binary.LittleEndian.Uint32(w[16:]),
+		},
+		syn: binary.LittleEndian.Uint32(r[12:])&cacheSynFlag != 0,
+	}
+}
+
+// all returns all index entries in sorted order.
+func (c *foldedCache) all() []*foldedWord {
+	words := make([]*foldedWord, c.len())
+	for i := range words {
+		words[i] = c.entry(i)
+	}
+	return words
+}
+
+// search performs a binary search over the index entries and returns the
+// matching entries like [index.Index.Search].
+func (c *foldedCache) search(query string) []*foldedWord {
+	n := c.len()
+	i, found := sort.Find(n, func(i int) int {
+		return prefixCmp(query, c.folded(i))
+	})
+	if !found {
+		return nil
+	}
+
+	var words []*foldedWord
+	for ; i < n && prefixCmp(query, c.folded(i)) == 0; i++ {
+		words = append(words, c.entry(i))
+	}
+	return words
+}
+
+// close releases the memory-mapped data.
+func (c *foldedCache) close() error {
+	if c.unmap == nil {
+		return nil
+	}
+	err := c.unmap()
+	c.unmap = nil
+	if err != nil {
+		return fmt.Errorf("unmapping folded index cache: %w", err)
+	}
+	return nil
+}
+
+// writeFoldedCache writes the sorted folded index entries to the cache file
+// at path. The file is written to a temporary file and renamed so that
+// readers never see a partially written cache.
+func writeFoldedCache(path string, key *cacheKey, entries []*foldedWord) (retErr error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("creating folded index cache: %w", err)
+	}
+	defer func() {
+		if retErr != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	if err := encodeFoldedCache(f, key, entries); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing folded index cache: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("renaming folded index cache: %w", err)
+	}
+	return nil
+}
+
+// encodeFoldedCache writes the cache file data for the sorted folded index
+// entries to w.
+func encodeFoldedCache(w io.Writer, key *cacheKey, entries []*foldedWord) error {
+	// Number the words. Synonyms share the word of the entry they refer to.
+	wordNums := map[*Word]uint32{}
+	var words []*Word
+	for _, e := range entries {
+		if _, ok := wordNums[e.word]; !ok {
+			if len(words) == math.MaxUint32 {
+				return fmt.Errorf("%w: too many words", errInvalidFoldedCache)
+			}
+			wordNums[e.word] = uint32(len(words)) //nolint:gosec // checked above.
+			words = append(words, e.word)
+		}
+	}
+	if len(entries) > math.MaxUint32 {
+		return fmt.Errorf("%w: too many entries", errInvalidFoldedCache)
+	}
+
+	var stringsSize uint64
+	addString := func(s string) ([8]byte, error) {
+		var r [8]byte
+		if stringsSize+uint64(len(s)) > math.MaxUint32 {
+			return r, fmt.Errorf("%w: string table too large", errInvalidFoldedCache)
+		}
+		//nolint:gosec // The string table size is checked above.
+		binary.LittleEndian.PutUint32(r[:], uint32(stringsSize))
+		//nolint:gosec // The string table size is checked above.
+		binary.LittleEndian.PutUint32(r[4:], uint32(len(s)))
+		stringsSize += uint64(len(s))
+		return r, nil
+	}
+
+	bw := bufio.NewWriter(w)
+	write := func(b []byte) {
+		// Errors are returned by Flush.
+		_, _ = bw.Write(b)
+	}
+	var b [8]byte
+	writeUint32 := func(v uint32) {
+		binary.LittleEndian.PutUint32(b[:], v)
+		write(b[:4])
+	}
+
+	write([]byte(cacheMagic))
+	writeUint32(cacheVersion)
+	write(key.checksum[:])
+	writeUint32(uint32(len(key.folderID))) //nolint:gosec // folder IDs are short.
+	write([]byte(key.folderID))
+	writeUint32(uint32(len(words)))   //nolint:gosec // checked above.
+	writeUint32(uint32(len(entries))) //nolint:gosec // checked above.
+
+	for _, word := range words {
+		r, err := addString(word.Word)
+		if err != nil {
+			return err
+		}
+		write(r[:])
+		binary.LittleEndian.PutUint64(b[:], word.Offset)
+		write(b[:])
+		writeUint32(word.Size)
+	}
+	for _, e := range entries {
+		r, err := addString(e.folded)
+		if err != nil {
+			return err
+		}
+		write(r[:])
+		writeUint32(wordNums[e.word])
+		var flags uint32
+		if e.syn {
+			flags |= cacheSynFlag
+		}
+		writeUint32(flags)
+	}
+
+	// The string table holds the words followed by the folded words in the
+	// same order as the records.
+	for _, word := range words {
+		_, _ = bw.WriteString(word.Word)
+	}
+	for _, e := range entries {
+		_, _ = bw.WriteString(e.folded)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("writing folded index cache: %w", err)
+	}
+	return nil
+}
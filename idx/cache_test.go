@@ -0,0 +1,138 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package idx_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/transform"
+
+	"github.com/ianlewis/go-stardict/idx"
+	"github.com/ianlewis/go-stardict/internal/testutil"
+	"github.com/ianlewis/go-stardict/syn"
+)
+
+// TestNewFromIfoPath_cache tests that NewFromIfoPath writes, uses, and
+// invalidates the folded index cache.
+func TestNewFromIfoPath_cache(t *testing.T) {
+	t.Parallel()
+
+	idxWords := []*idx.Word{
+		{Word: "bar", Offset: 0, Size: 1},
+		{Word: "Baz", Offset: 1, Size: 2},
+		{Word: "foo", Offset: 3, Size: 3},
+		{Word: "hoge", Offset: 6, Size: 4},
+	}
+	synWords := []*syn.Word{
+		{Word: "bazooka", OriginalWordIndex: 3},
+		{Word: "fuga", OriginalWordIndex: 2},
+	}
+
+	dir := t.TempDir()
+	ifoPath := filepath.Join(dir, "dictionary.ifo")
+	idxPath := filepath.Join(dir, "dictionary.idx")
+	synPath := filepath.Join(dir, "dictionary.syn")
+	cachePath := filepath.Join(dir, "cache", "dictionary.idx.cache")
+
+	if err := os.WriteFile(idxPath, testutil.MakeIndex(idxWords, 32), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(synPath, testutil.MakeSyn(t, synWords), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	options := &idx.Options{
+		Folder: func() transform.Transformer {
+			return cases.Fold()
+		},
+		CachePath: cachePath,
+		FolderID:  "fold",
+	}
+
+	// search opens the index and checks the results of a search and that
+	// the index holds all words.
+	search := func(t *testing.T, query string, expected, all []*idx.Word) {
+		t.Helper()
+
+		index, err := idx.NewFromIfoPath(ifoPath, options)
+		if err != nil {
+			t.Fatalf("NewFromIfoPath: %v", err)
+		}
+		defer index.Close()
+
+		words, err := index.Search(query)
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		if diff := cmp.Diff(expected, words); diff != "" {
+			t.Errorf("Search (-want, +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(all, index.Words()); diff != "" {
+			t.Errorf("Words (-want, +got):\n%s", diff)
+		}
+	}
+
+	// cacheFile returns the file info of the cache file. The cache file is
+	// replaced when it is rewritten.
+	cacheFile := func(t *testing.T) os.FileInfo {
+		t.Helper()
+
+		fi, err := os.Stat(cachePath)
+		if err != nil {
+			t.Fatalf("stat cache: %v", err)
+		}
+		return fi
+	}
+
+	search(t, "baz*", []*idx.Word{idxWords[1], idxWords[3]}, idxWords)
+	written := cacheFile(t)
+
+	// The cache file is used and not rewritten.
+	search(t, "FUGA", idxWords[2:3], idxWords)
+	if !os.SameFile(written, cacheFile(t)) {
+		t.Errorf("cache file was rewritten")
+	}
+
+	// The cache file is rewritten if the folder ID changes.
+	options.FolderID = "fold2"
+	search(t, "baz*", []*idx.Word{idxWords[1], idxWords[3]}, idxWords)
+	if os.SameFile(written, cacheFile(t)) {
+		t.Errorf("cache file was not rewritten after the folder ID changed")
+	}
+	written = cacheFile(t)
+
+	// The cache file is rewritten if the .idx file changes.
+	newWords := append(append([]*idx.Word{}, idxWords...), &idx.Word{Word: "piyo", Offset: 10, Size: 5})
+	if err := os.WriteFile(idxPath, testutil.MakeIndex(newWords, 32), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	search(t, "piyo", newWords[4:], newWords)
+	if os.SameFile(written, cacheFile(t)) {
+		t.Errorf("cache file was not rewritten after the .idx file changed")
+	}
+
+	// An invalid cache file is ignored and rewritten.
+	if err := os.Truncate(cachePath, 10); err != nil {
+		t.Fatal(err)
+	}
+	search(t, "fuga", newWords[2:3], newWords)
+	if got := cacheFile(t).Size(); got <= 10 {
+		t.Errorf("cache file was not rewritten: size %d", got)
+	}
+}
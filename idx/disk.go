@@ -0,0 +1,391 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package idx
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/gobwas/glob/syntax"
+
+	"github.com/ianlewis/go-stardict/collation"
+	"github.com/ianlewis/go-stardict/internal/gzipfile"
+	"github.com/ianlewis/go-stardict/syn"
+)
+
+var errCorruptEntry = errors.New("corrupt index entry")
+
+// DiskIdx is an index that searches the .idx and .syn files on disk rather
+// than loading them into memory. Only the offset of each entry is held in
+// memory. Entries are found by binary search in the order used by StarDict
+// for .idx and .syn files ([collation.StardictCompare]) so words are matched
+// ignoring the case of ASCII letters and no other folding is performed. The
+// .idx and .syn files must be uncompressed and sorted.
+type DiskIdx struct {
+	idx *diskTable
+	syn *diskTable
+
+	idxoffsetbits int
+}
+
+// diskTable is a table of the offsets of the entries in an index file.
+type diskTable struct {
+	r io.ReaderAt
+
+	// offsets holds the offset of each entry followed by the size of the
+	// file.
+	offsets []uint32
+
+	// trailer is the number of bytes following the word's null terminator in
+	// each entry.
+	trailer int
+
+	// size is the size of the entries added so far.
+	size uint64
+}
+
+// NewDisk returns a new DiskIdx that reads the .idx data from idxReader and
+// the .syn data from synReader. synReader may be nil if the dictionary has
+// no .syn file. The readers are scanned once to build the offset tables. The
+// DiskIdx assumes ownership of the readers and Close closes them if they
+// implement [io.Closer].
+func NewDisk(idxReader, synReader io.ReaderAt, options *ScannerOptions) (*DiskIdx, error) {
+	if options == nil {
+		options = DefaultScannerOptions
+	}
+	if options.OffsetBits != 32 && options.OffsetBits != 64 {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIdxOffset, options.OffsetBits)
+	}
+
+	i := &DiskIdx{
+		idxoffsetbits: options.OffsetBits,
+	}
+
+	s, err := NewScanner(io.NopCloser(io.NewSectionReader(idxReader, 0, math.MaxInt64)), options)
+	if err != nil {
+		return nil, err
+	}
+	i.idx = &diskTable{
+		r:       idxReader,
+		trailer: options.OffsetBits/8 + 4,
+	}
+	for s.Scan() {
+		if err = i.idx.add(len(s.Word().Word)); err != nil {
+			return nil, err
+		}
+	}
+	if err = s.Err(); err != nil {
+		return nil, fmt.Errorf("scanning index: %w", err)
+	}
+	if err = i.idx.finish(); err != nil {
+		return nil, err
+	}
+
+	if synReader != nil {
+		var synScanner *syn.Scanner
+		synScanner, err = syn.NewScanner(io.NopCloser(io.NewSectionReader(synReader, 0, math.MaxInt64)))
+		if err != nil {
+			return nil, fmt.Errorf("scanning synonym index: %w", err)
+		}
+		i.syn = &diskTable{
+			r:       synReader,
+			trailer: 4,
+		}
+		for synScanner.Scan() {
+			if err = i.syn.add(len(synScanner.Word().Word)); err != nil {
+				return nil, err
+			}
+		}
+		if err = synScanner.Err(); err != nil {
+			return nil, fmt.Errorf("scanning synonym index: %w", err)
+		}
+		if err = i.syn.finish(); err != nil {
+			return nil, err
+		}
+	}
+
+	return i, nil
+}
+
+// NewDiskFromIfoPath opens the uncompressed .idx and .syn files for the given
+// .ifo file and returns a DiskIdx. The DiskIdx assumes ownership of the files
+// and should be closed with the Close method.
+func NewDiskFromIfoPath(ifoPath string, options *ScannerOptions) (*DiskIdx, error) {
+	idxFile, err := Open(ifoPath)
+	if err != nil {
+		return nil, err
+	}
+	if gzipfile.IsCompressed(idxFile.Name()) {
+		_ = idxFile.Close()
+		return nil, fmt.Errorf("%w: %q", ErrCompressed, idxFile.Name())
+	}
+
+	var synReader io.ReaderAt
+	synFile, err := syn.Open(ifoPath)
+	if !errors.Is(err, os.ErrNotExist) {
+		if err != nil {
+			_ = idxFile.Close()
+			//nolint:wrapcheck // it isn't necessary to wrap this error.
+			return nil, err
+		}
+		if gzipfile.IsCompressed(synFile.Name()) {
+			_ = idxFile.Close()
+			_ = synFile.Close()
+			return nil, fmt.Errorf("%w: %q", ErrCompressed, synFile.Name())
+		}
+		synReader = synFile
+	}
+
+	i, err := NewDisk(idxFile, synReader, options)
+	if err != nil {
+		_ = idxFile.Close()
+		if synFile != nil {
+			_ = synFile.Close()
+		}
+		return nil, err
+	}
+	return i, nil
+}
+
+// Len returns the number of entries in the .idx file.
+func (i *DiskIdx) Len() int {
+	return i.idx.len()
+}
+
+// Word returns the n-th entry in the .idx file.
+func (i *DiskIdx) Word(n int) (*Word, error) {
+	if n < 0 || n >= i.idx.len() {
+		return nil, fmt.Errorf("%w: %d", ErrOutOfRange, n)
+	}
+	word, trailer, err := i.idx.entry(n)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Word{
+		Word: word,
+	}
+	if i.idxoffsetbits == 64 {
+		w.Offset = binary.BigEndian.Uint64(trailer)
+	} else {
+		w.Offset = uint64(binary.BigEndian.Uint32(trailer))
+	}
+	w.Size = binary.BigEndian.Uint32(trailer[i.idxoffsetbits/8:])
+	return w, nil
+}
+
+// Search performs a query of the index and returns matching words. The query
+// supports the same glob patterns as [Idx.Search]. Words and the query are
+// matched ignoring the case of ASCII letters. Words matching synonyms in the
+// .syn file are included in the results. Results are returned in StarDict
+// order of the matching word or synonym.
+func (i *DiskIdx) Search(query string) ([]*Word, error) {
+	foldedQuery := asciiFold(query)
+	g, err := glob.Compile(foldedQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrGlob, query)
+	}
+
+	// Get the prefix to the query.
+	prefix := foldedQuery
+	for j := range len(foldedQuery) {
+		if syntax.Special(foldedQuery[j]) {
+			prefix = foldedQuery[:j]
+			break
+		}
+	}
+	if prefix == "" {
+		return nil, fmt.Errorf("%w: %q", ErrPrefix, query)
+	}
+
+	return i.find(prefix, func(folded string) bool {
+		return g.Match(folded)
+	})
+}
+
+// Lookup returns the words in the index, including words with a matching
+// synonym, that are equal to word ignoring the case of ASCII letters. Unlike
+// Search, the word is not treated as a glob pattern.
+func (i *DiskIdx) Lookup(word string) ([]*Word, error) {
+	folded := asciiFold(word)
+	if folded == "" {
+		return nil, nil
+	}
+	return i.find(folded, func(w string) bool {
+		return w == folded
+	})
+}
+
+// diskMatch is a word matched by a word or synonym.
+type diskMatch struct {
+	matched string
+	word    *Word
+}
+
+// find returns the words whose folded value or folded synonym has the given
+// folded prefix and for which match returns true.
+func (i *DiskIdx) find(prefix string, match func(folded string) bool) ([]*Word, error) {
+	var matches []diskMatch
+
+	err := i.idx.scanPrefix(prefix, func(n int, word string, _ []byte) error {
+		if !match(asciiFold(word)) {
+			return nil
+		}
+		w, err := i.Word(n)
+		if err != nil {
+			return err
+		}
+		matches = append(matches, diskMatch{matched: word, word: w})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if i.syn != nil {
+		err = i.syn.scanPrefix(prefix, func(_ int, word string, trailer []byte) error {
+			if !match(asciiFold(word)) {
+				return nil
+			}
+			w, wordErr := i.Word(int(binary.BigEndian.Uint32(trailer)))
+			if wordErr != nil {
+				return fmt.Errorf("reading synonym %q: %w", word, wordErr)
+			}
+			matches = append(matches, diskMatch{matched: word, word: w})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b diskMatch) int {
+		return collation.StardictCompare(a.matched, b.matched)
+	})
+	var words []*Word
+	for _, m := range matches {
+		words = append(words, m.word)
+	}
+	return words, nil
+}
+
+// Close closes the underlying readers if they implement [io.Closer].
+func (i *DiskIdx) Close() error {
+	if c, ok := i.idx.r.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			return fmt.Errorf("closing idx file: %w", err)
+		}
+	}
+	if i.syn != nil {
+		if c, ok := i.syn.r.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				return fmt.Errorf("closing syn file: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// add adds the next entry to the table given the length of its word.
+func (t *diskTable) add(wordLen int) error {
+	if t.size > math.MaxUint32 {
+		return fmt.Errorf("%w: %d", errIdxTooLarge, t.size)
+	}
+	t.offsets = append(t.offsets, uint32(t.size))
+	t.size += uint64(wordLen) + 1 + uint64(t.trailer)
+	return nil
+}
+
+// finish adds the size of the file to the end of the table.
+func (t *diskTable) finish() error {
+	if t.size > math.MaxUint32 {
+		return fmt.Errorf("%w: %d", errIdxTooLarge, t.size)
+	}
+	t.offsets = append(t.offsets, uint32(t.size))
+	return nil
+}
+
+// len returns the number of entries in the table.
+func (t *diskTable) len() int {
+	return len(t.offsets) - 1
+}
+
+// entry reads the n-th entry and returns its word and the data following
+// the word's null terminator.
+func (t *diskTable) entry(n int) (string, []byte, error) {
+	start, end := t.offsets[n], t.offsets[n+1]
+	b := make([]byte, end-start)
+	if _, err := t.r.ReadAt(b, int64(start)); err != nil {
+		return "", nil, fmt.Errorf("reading entry %d: %w", n, err)
+	}
+	i := len(b) - t.trailer - 1
+	if i < 0 || b[i] != 0 {
+		return "", nil, fmt.Errorf("%w: entry %d at offset %d", errCorruptEntry, n, start)
+	}
+	return string(b[:i]), b[i+1:], nil
+}
+
+// scanPrefix calls fn for each entry whose folded word has the folded
+// prefix. The entries are found by binary search so the file must be sorted
+// in StarDict order.
+func (t *diskTable) scanPrefix(prefix string, fn func(n int, word string, trailer []byte) error) error {
+	// Find the first entry whose folded word is not less than the prefix.
+	lo, hi := 0, t.len()
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		word, _, err := t.entry(mid)
+		if err != nil {
+			return err
+		}
+		if asciiFold(word) < prefix {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+
+	for n := lo; n < t.len(); n++ {
+		word, trailer, err := t.entry(n)
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(asciiFold(word), prefix) {
+			break
+		}
+		if err = fn(n, word, trailer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// asciiFold returns s with ASCII upper case letters converted to lower case.
+// Other bytes are unchanged.
+func asciiFold(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + ('a' - 'A')
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,203 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package idx_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/idx"
+	"github.com/ianlewis/go-stardict/internal/testutil"
+	"github.com/ianlewis/go-stardict/syn"
+)
+
+// TestDiskIdx_Search tests DiskIdx.Search.
+func TestDiskIdx_Search(t *testing.T) {
+	t.Parallel()
+
+	// Words are in StarDict order.
+	idxWords := []*idx.Word{
+		{Word: "bar", Offset: 0, Size: 1},
+		{Word: "Baz", Offset: 1, Size: 2},
+		{Word: "baz", Offset: 3, Size: 3},
+		{Word: "foo", Offset: 6, Size: 4},
+		{Word: "fuga", Offset: 10, Size: 5},
+		{Word: "hoge", Offset: 15, Size: 6},
+	}
+	synWords := []*syn.Word{
+		{Word: "bazooka", OriginalWordIndex: 5},
+		{Word: "piyo", OriginalWordIndex: 4},
+	}
+
+	tests := []struct {
+		name          string
+		query         string
+		idxoffsetbits int
+		syn           []*syn.Word
+
+		expected []*idx.Word
+		err      error
+	}{
+		{
+			name:          "no match",
+			query:         "pico",
+			idxoffsetbits: 32,
+		},
+		{
+			name:          "single match first",
+			query:         "bar",
+			idxoffsetbits: 32,
+			expected:      idxWords[:1],
+		},
+		{
+			name:          "single match last",
+			query:         "hoge",
+			idxoffsetbits: 64,
+			expected:      idxWords[5:],
+		},
+		{
+			name:          "ascii case insensitive",
+			query:         "BAZ",
+			idxoffsetbits: 32,
+			expected:      idxWords[1:3],
+		},
+		{
+			name:          "glob",
+			query:         "f*",
+			idxoffsetbits: 32,
+			expected:      idxWords[3:5],
+		},
+		{
+			name:          "synonym",
+			query:         "piyo",
+			idxoffsetbits: 32,
+			syn:           synWords,
+			expected:      idxWords[4:5],
+		},
+		{
+			name:          "synonym glob",
+			query:         "baz*",
+			idxoffsetbits: 64,
+			syn:           synWords,
+			expected:      []*idx.Word{idxWords[1], idxWords[2], idxWords[5]},
+		},
+		{
+			name:          "wildcard prefix",
+			query:         "*oo",
+			idxoffsetbits: 32,
+			err:           idx.ErrPrefix,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var synReader io.ReaderAt
+			if test.syn != nil {
+				synReader = bytes.NewReader(testutil.MakeSyn(t, test.syn))
+			}
+			idxReader := bytes.NewReader(testutil.MakeIndex(idxWords, test.idxoffsetbits))
+			i, err := idx.NewDisk(idxReader, synReader, &idx.ScannerOptions{
+				OffsetBits: test.idxoffsetbits,
+			})
+			if err != nil {
+				t.Fatalf("NewDisk: %v", err)
+			}
+			defer i.Close()
+
+			if got, want := i.Len(), len(idxWords); got != want {
+				t.Errorf("Len: expected %d, got %d", want, got)
+			}
+
+			words, err := i.Search(test.query)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("Search: expected error %v, got %v", test.err, err)
+			}
+			if diff := cmp.Diff(test.expected, words); diff != "" {
+				t.Errorf("Search (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestDiskIdx_Lookup tests DiskIdx.Lookup.
+func TestDiskIdx_Lookup(t *testing.T) {
+	t.Parallel()
+
+	idxWords := []*idx.Word{
+		{Word: "foo", Offset: 0, Size: 1},
+		{Word: "foo*", Offset: 1, Size: 2},
+		{Word: "Foobar", Offset: 3, Size: 3},
+	}
+	i, err := idx.NewDisk(bytes.NewReader(testutil.MakeIndex(idxWords, 32)), nil, nil)
+	if err != nil {
+		t.Fatalf("NewDisk: %v", err)
+	}
+	defer i.Close()
+
+	words, err := i.Lookup("FOO*")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if diff := cmp.Diff(idxWords[1:2], words); diff != "" {
+		t.Errorf("Lookup (-want, +got):\n%s", diff)
+	}
+}
+
+// TestDiskIdx_Word tests DiskIdx.Word.
+func TestDiskIdx_Word(t *testing.T) {
+	t.Parallel()
+
+	idxWords := []*idx.Word{
+		{Word: "foo", Offset: 0, Size: 1},
+		{Word: "hoge", Offset: 1 << 40, Size: 2},
+	}
+	i, err := idx.NewDisk(bytes.NewReader(testutil.MakeIndex(idxWords, 64)), nil, &idx.ScannerOptions{
+		OffsetBits: 64,
+	})
+	if err != nil {
+		t.Fatalf("NewDisk: %v", err)
+	}
+	defer i.Close()
+
+	w, err := i.Word(1)
+	if err != nil {
+		t.Fatalf("Word: %v", err)
+	}
+	if diff := cmp.Diff(idxWords[1], w); diff != "" {
+		t.Errorf("Word (-want, +got):\n%s", diff)
+	}
+
+	if _, err := i.Word(2); !errors.Is(err, idx.ErrOutOfRange) {
+		t.Errorf("Word: expected %v, got %v", idx.ErrOutOfRange, err)
+	}
+}
+
+// TestNewDisk_truncated tests that NewDisk returns an error for a truncated
+// .idx file.
+func TestNewDisk_truncated(t *testing.T) {
+	t.Parallel()
+
+	b := testutil.MakeIndex([]*idx.Word{{Word: "foo"}}, 32)
+	_, err := idx.NewDisk(bytes.NewReader(b[:len(b)-1]), nil, nil)
+	if !errors.Is(err, idx.ErrTruncated) {
+		t.Errorf("NewDisk: expected %v, got %v", idx.ErrTruncated, err)
+	}
+}
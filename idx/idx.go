@@ -16,18 +16,19 @@
 package idx
 
 import (
-	"compress/gzip"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/gobwas/glob"
 	"github.com/gobwas/glob/syntax"
 	"golang.org/x/text/transform"
 
+	"github.com/ianlewis/go-stardict/internal/gzipfile"
 	"github.com/ianlewis/go-stardict/internal/index"
 	"github.com/ianlewis/go-stardict/syn"
 )
@@ -55,6 +56,9 @@ type Word struct {
 type foldedWord struct {
 	folded string
 	word   *Word
+
+	// syn is true if the folded word is a synonym of word.
+	syn bool
 }
 
 func (w *foldedWord) String() string {
@@ -69,6 +73,26 @@ type Options struct {
 
 	// ScannerOptions are the options to use when reading the .idx file.
 	ScannerOptions *ScannerOptions
+
+	// Compare is the comparison function used to order the folded words
+	// returned by Search and Words. Compare returns a negative number when
+	// a < b, a positive number when a > b and zero when a == b. Defaults to
+	// [strings.Compare]. See the collation package for locale specific
+	// comparison functions. The index itself is always sorted and searched
+	// in byte order so that words with a common prefix are contiguous.
+	Compare func(a, b string) int
+
+	// CachePath is the path to a folded index cache file used by
+	// NewFromIfoPath. If the cache file was written for the same .idx and
+	// .syn data and FolderID it is memory-mapped instead of folding and
+	// sorting the index. Otherwise the index is built and written to the
+	// cache file. Failures to write the cache file are ignored.
+	CachePath string
+
+	// FolderID identifies the folding performed by Folder. It is stored in
+	// the cache file so that a cache written with a different Folder is not
+	// used. The cache file is not used if FolderID is empty.
+	FolderID string
 }
 
 // DefaultOptions is the default options for an Idx.
@@ -79,6 +103,7 @@ var DefaultOptions = &Options{
 	ScannerOptions: &ScannerOptions{
 		OffsetBits: 32,
 	},
+	Compare: strings.Compare,
 }
 
 // Idx is a very basic implementation of an in memory search index.
@@ -89,8 +114,15 @@ type Idx struct {
 	// index is sorted by the folded word value.
 	index *index.Index[*foldedWord]
 
+	// cache is the memory-mapped folded index cache used instead of index
+	// if not nil.
+	cache *foldedCache
+
 	// foldTransformer performs folding on text.
 	foldTransformer func() transform.Transformer
+
+	// compare orders the results of Search and Words.
+	compare func(a, b string) int
 }
 
 // New returns a new in-memory index.
@@ -106,10 +138,14 @@ func NewWithSyn(idxReader, synReader io.ReadCloser, options *Options) (*Idx, err
 
 	idx := &Idx{
 		foldTransformer: DefaultOptions.Folder,
+		compare:         DefaultOptions.Compare,
 	}
 	if options.Folder != nil {
 		idx.foldTransformer = options.Folder
 	}
+	if options.Compare != nil {
+		idx.compare = options.Compare
+	}
 
 	i := 0
 	s, err := NewScanner(idxReader, options.ScannerOptions)
@@ -149,6 +185,7 @@ func NewWithSyn(idxReader, synReader io.ReadCloser, options *Options) (*Idx, err
 			words = append(words, &foldedWord{
 				folded: folded,
 				word:   words[word.OriginalWordIndex].word,
+				syn:    true,
 			})
 		}
 	}
@@ -158,22 +195,31 @@ func NewWithSyn(idxReader, synReader io.ReadCloser, options *Options) (*Idx, err
 	return idx, nil
 }
 
+// idxExts are the file extensions of .idx files in the order that Open looks
+// for them.
+var idxExts = []string{
+	".idx",
+	".idx.gz",
+	".idx.GZ",
+	".idx.dz",
+	".idx.DZ",
+	".IDX",
+	".IDX.gz",
+	".IDX.GZ",
+	".IDX.dz",
+	".IDX.DZ",
+}
+
+// Extensions returns the file extensions of .idx files in the order that
+// Open looks for them.
+func Extensions() []string {
+	return slices.Clone(idxExts)
+}
+
 // Open opens the .idx file given the path to the .ifo file.
 func Open(ifoPath string) (*os.File, error) {
 	baseName := strings.TrimSuffix(ifoPath, filepath.Ext(ifoPath))
 
-	idxExts := []string{
-		".idx",
-		".idx.gz",
-		".idx.GZ",
-		".idx.dz",
-		".idx.DZ",
-		".IDX",
-		".IDX.gz",
-		".IDX.GZ",
-		".IDX.dz",
-		".IDX.DZ",
-	}
 	var f *os.File
 	var err error
 	for _, ext := range idxExts {
@@ -194,41 +240,99 @@ func Open(ifoPath string) (*os.File, error) {
 	return f, nil
 }
 
-// NewFromIfoPath returns a new in-memory index.
+// NewFromIfoPath returns a new in-memory index. If options.CachePath and
+// options.FolderID are set, the folded index cache file is used if it is up to
+// date and is otherwise written.
 func NewFromIfoPath(ifoPath string, options *Options) (*Idx, error) {
-	var idxReader, synReader io.ReadCloser
-	idxFile, err := Open(ifoPath)
+	if options == nil || options.CachePath == "" || options.FolderID == "" {
+		return newFromIfoPath(ifoPath, options)
+	}
+
+	idxReader, synReader, err := openIfoPath(ifoPath)
+	if err != nil {
+		return nil, err
+	}
+	key := &cacheKey{
+		folderID: options.FolderID,
+	}
+	key.checksum, err = cacheChecksum(idxReader, synReader)
+	_ = idxReader.Close()
+	if synReader != nil {
+		_ = synReader.Close()
+	}
 	if err != nil {
 		return nil, err
 	}
-	idxReader = idxFile
 
-	idxExt := strings.ToLower(filepath.Ext(idxFile.Name()))
-	if idxExt == ".gz" || idxExt == ".dz" {
-		idxReader, err = gzip.NewReader(idxReader)
-		if err != nil {
-			return nil, fmt.Errorf("creating .idx gzip reader: %w", err)
+	cache, err := openFoldedCache(options.CachePath, key)
+	if err == nil {
+		idx := &Idx{
+			cache:           cache,
+			foldTransformer: DefaultOptions.Folder,
+			compare:         DefaultOptions.Compare,
+		}
+		if options.Folder != nil {
+			idx.foldTransformer = options.Folder
+		}
+		if options.Compare != nil {
+			idx.compare = options.Compare
 		}
+		return idx, nil
+	}
+
+	// The cache is missing, out of date, or invalid so the index is rebuilt.
+	idx, err := newFromIfoPath(ifoPath, options)
+	if err != nil {
+		return nil, err
+	}
+	_ = writeFoldedCache(options.CachePath, key, idx.index.All())
+	return idx, nil
+}
+
+// newFromIfoPath returns a new in-memory index without using a cache file.
+func newFromIfoPath(ifoPath string, options *Options) (*Idx, error) {
+	idxReader, synReader, err := openIfoPath(ifoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer idxReader.Close()
+	if synReader != nil {
+		defer synReader.Close()
+	}
+	return NewWithSyn(idxReader, synReader, options)
+}
+
+// openIfoPath opens the .idx and .syn files for the given .ifo file and
+// decompresses them if necessary. The returned .syn reader is nil if there is
+// no .syn file.
+func openIfoPath(ifoPath string) (io.ReadCloser, io.ReadCloser, error) {
+	idxFile, err := Open(ifoPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	idxReader, err := gzipfile.NewReader(idxFile)
+	if err != nil {
+		//nolint:wrapcheck // error is already wrapped.
+		return nil, nil, err
 	}
 
+	var synReader io.ReadCloser
 	synFile, err := syn.Open(ifoPath)
 	if !errors.Is(err, os.ErrNotExist) {
 		if err != nil {
+			_ = idxReader.Close()
 			//nolint:wrapcheck // it isn't necessary to wrap this error.
-			return nil, err
+			return nil, nil, err
 		}
-		synReader = synFile
-
-		synExt := strings.ToLower(filepath.Ext(synFile.Name()))
-		if synExt == ".gz" || synExt == ".dz" {
-			synReader, err = gzip.NewReader(synReader)
-			if err != nil {
-				return nil, fmt.Errorf("creating .syn gzip reader: %w", err)
-			}
+		synReader, err = gzipfile.NewReader(synFile)
+		if err != nil {
+			_ = idxReader.Close()
+			//nolint:wrapcheck // error is already wrapped.
+			return nil, nil, err
 		}
 	}
 
-	return NewWithSyn(idxReader, synReader, options)
+	return idxReader, synReader, nil
 }
 
 // Search performs a query of the index and returns matching words. The query
@@ -284,15 +388,30 @@ func (idx *Idx) Search(query string) ([]*Word, error) {
 	}
 
 	// Get all results with the static prefix.
-	result := idx.index.Search(prefix)
+	var result []*foldedWord
+	for _, w := range idx.search(prefix) {
+		if g.Match(w.folded) {
+			result = append(result, w)
+		}
+	}
+
+	return idx.sorted(result), nil
+}
+
+// Lookup returns the words in the index whose folded value is equal to the
+// folded word. Unlike Search, the word is not treated as a glob pattern.
+func (idx *Idx) Lookup(word string) ([]*Word, error) {
+	folded, _, err := transform.String(idx.foldTransformer(), word)
+	if err != nil {
+		return nil, fmt.Errorf("folding %q: %w", word, err)
+	}
 
 	var words []*Word
-	for _, w := range result {
-		if g.Match(w.folded) {
+	for _, w := range idx.search(folded) {
+		if w.folded == folded {
 			words = append(words, w.word)
 		}
 	}
-
 	return words, nil
 }
 
@@ -346,6 +465,58 @@ func (idx *Idx) foldGlob(q string) (string, error) {
 	return strings.Join(s, ""), nil
 }
 
+// Words returns the words in the index ordered by their folded value using
+// Options.Compare. Synonyms merged into the index are not included.
+func (idx *Idx) Words() []*Word {
+	var entries []*foldedWord
+	for _, w := range idx.all() {
+		if !w.syn {
+			entries = append(entries, w)
+		}
+	}
+	return idx.sorted(entries)
+}
+
+// sorted sorts the entries by their folded value using the compare function
+// and returns their words. Entries that compare equal keep their byte order.
+func (idx *Idx) sorted(entries []*foldedWord) []*Word {
+	slices.SortStableFunc(entries, func(a, b *foldedWord) int {
+		return idx.compare(a.folded, b.folded)
+	})
+	var words []*Word
+	for _, w := range entries {
+		words = append(words, w.word)
+	}
+	return words
+}
+
+// search returns the index entries matching the folded query.
+func (idx *Idx) search(query string) []*foldedWord {
+	if idx.cache != nil {
+		return idx.cache.search(query)
+	}
+	return idx.index.Search(query)
+}
+
+// all returns all index entries ordered by their folded value in byte order.
+func (idx *Idx) all() []*foldedWord {
+	if idx.cache != nil {
+		return idx.cache.all()
+	}
+	return idx.index.All()
+}
+
+// Close releases the memory-mapped folded index cache if one is used. The
+// index can't be used after Close is called.
+func (idx *Idx) Close() error {
+	if idx.cache != nil {
+		return idx.cache.close()
+	}
+	return nil
+}
+
+// prefixCmp compares a query a with an index entry b. a is considered equal to
+// b if b has the prefix a and the values are otherwise compared in byte order.
 func prefixCmp(a, b string) int {
 	if strings.HasPrefix(b, a) {
 		return 0
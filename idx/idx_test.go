@@ -24,8 +24,10 @@ import (
 	"golang.org/x/text/cases"
 	"golang.org/x/text/transform"
 
+	"github.com/ianlewis/go-stardict/collation"
 	"github.com/ianlewis/go-stardict/idx"
 	"github.com/ianlewis/go-stardict/internal/testutil"
+	"github.com/ianlewis/go-stardict/syn"
 )
 
 // TestIdx_Search tests Idx.Search.
@@ -370,3 +372,144 @@ func TestIdx_Search(t *testing.T) {
 		})
 	}
 }
+
+// TestIdx_Lookup tests Idx.Lookup.
+func TestIdx_Lookup(t *testing.T) {
+	t.Parallel()
+
+	b := testutil.MakeIndex([]*idx.Word{
+		{Word: "Hoge"},
+		{Word: "hoge*"},
+		{Word: "hogefuga"},
+	}, 32)
+
+	index, err := idx.New(io.NopCloser(bytes.NewReader(b)), &idx.Options{
+		Folder: func() transform.Transformer {
+			return cases.Fold()
+		},
+	})
+	if err != nil {
+		t.Fatalf("idx.New: %v", err)
+	}
+
+	result, err := index.Lookup("hoge")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if diff := cmp.Diff([]*idx.Word{{Word: "Hoge"}}, result); diff != "" {
+		t.Errorf("Lookup (-want, +got):\n%s", diff)
+	}
+
+	// Glob characters are matched literally.
+	result, err = index.Lookup("hoge*")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if diff := cmp.Diff([]*idx.Word{{Word: "hoge*"}}, result); diff != "" {
+		t.Errorf("Lookup (-want, +got):\n%s", diff)
+	}
+}
+
+// TestIdx_Words tests Idx.Words.
+func TestIdx_Words(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		idxWords []*idx.Word
+		synWords []*syn.Word
+		options  *idx.Options
+
+		expected []string
+	}{
+		{
+			name: "default order",
+			idxWords: []*idx.Word{
+				{Word: "zebra"},
+				{Word: "äpple"},
+				{Word: "apa"},
+			},
+			expected: []string{"apa", "zebra", "äpple"},
+		},
+		{
+			name: "collation order",
+			idxWords: []*idx.Word{
+				{Word: "zebra"},
+				{Word: "aber"},
+				{Word: "apa"},
+			},
+			options: &idx.Options{
+				Compare: collation.UTF8SwedishCI.Compare(),
+			},
+			expected: []string{"aber", "apa", "zebra"},
+		},
+		{
+			name: "synonyms excluded",
+			idxWords: []*idx.Word{
+				{Word: "foo"},
+				{Word: "bar"},
+			},
+			synWords: []*syn.Word{
+				{Word: "baz", OriginalWordIndex: 0},
+			},
+			expected: []string{"bar", "foo"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var synReader io.ReadCloser
+			if len(test.synWords) > 0 {
+				synReader = io.NopCloser(bytes.NewReader(testutil.MakeSyn(t, test.synWords)))
+			}
+			b := testutil.MakeIndex(test.idxWords, 32)
+
+			index, err := idx.NewWithSyn(io.NopCloser(bytes.NewReader(b)), synReader, test.options)
+			if err != nil {
+				t.Fatalf("idx.NewWithSyn: %v", err)
+			}
+
+			var words []string
+			for _, w := range index.Words() {
+				words = append(words, w.Word)
+			}
+			if diff := cmp.Diff(test.expected, words); diff != "" {
+				t.Fatalf("Words (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestIdx_Search_collation tests that Idx.Search uses the collation order.
+func TestIdx_Search_collation(t *testing.T) {
+	t.Parallel()
+
+	b := testutil.MakeIndex([]*idx.Word{
+		{Word: "äpple"},
+		{Word: "zebra"},
+		{Word: "apa"},
+		{Word: "äppelpaj"},
+	}, 32)
+
+	index, err := idx.New(io.NopCloser(bytes.NewReader(b)), &idx.Options{
+		Compare: collation.UTF8SwedishCI.Compare(),
+	})
+	if err != nil {
+		t.Fatalf("idx.New: %v", err)
+	}
+
+	result, err := index.Search("äpp*")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	var words []string
+	for _, w := range result {
+		words = append(words, w.Word)
+	}
+	if diff := cmp.Diff([]string{"äppelpaj", "äpple"}, words); diff != "" {
+		t.Fatalf("Search (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,342 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package idx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EntriesPerPage is the number of .idx entries in each page of an offset
+// cache.
+const EntriesPerPage = 32
+
+// oftMagic is the header written at the start of offset cache files.
+const oftMagic = "StarDict's oft file\nversion=2.4.8\n"
+
+var (
+	// ErrOutOfRange indicates that an entry index is out of range.
+	ErrOutOfRange = errors.New("index out of range")
+
+	// ErrCompressed indicates that the .idx file is compressed and does not
+	// support random access.
+	ErrCompressed = errors.New("compressed index does not support random access")
+
+	errInvalidCache = errors.New("invalid offset cache")
+	errIdxTooLarge  = errors.New("idx file too large for offset cache")
+)
+
+// OffsetCache is the contents of an offset cache (.oft) file. The offset cache
+// holds the offset of every EntriesPerPage-th entry in an .idx file so that
+// entries can be read without scanning the full .idx file.
+//
+// The offset cache file begins with a text header containing the magic string
+// and the url of the .idx file. The header is followed by the page offsets as
+// 32-bit integers in little-endian byte order.
+type OffsetCache struct {
+	// URL is the path to the .idx file that the offset cache was generated
+	// for.
+	URL string
+
+	// Offsets holds the offset of the first entry of each page followed by
+	// the size of the .idx file.
+	Offsets []uint32
+}
+
+// NewOffsetCache creates a new offset cache by scanning the .idx data from r.
+func NewOffsetCache(r io.Reader, options *ScannerOptions) (*OffsetCache, error) {
+	s, err := NewScanner(io.NopCloser(r), options)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &OffsetCache{}
+	var offset uint64
+	var i int
+	for s.Scan() {
+		if offset > math.MaxUint32 {
+			return nil, fmt.Errorf("%w: %d", errIdxTooLarge, offset)
+		}
+		if i%EntriesPerPage == 0 {
+			c.Offsets = append(c.Offsets, uint32(offset))
+		}
+		offset += uint64(len(s.s.Bytes()))
+		i++
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("scanning index: %w", err)
+	}
+	if offset > math.MaxUint32 {
+		return nil, fmt.Errorf("%w: %d", errIdxTooLarge, offset)
+	}
+	c.Offsets = append(c.Offsets, uint32(offset))
+
+	return c, nil
+}
+
+// ReadOffsetCache reads an offset cache file from r.
+func ReadOffsetCache(r io.Reader) (*OffsetCache, error) {
+	br := bufio.NewReader(r)
+
+	magic := make([]byte, len(oftMagic))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return nil, fmt.Errorf("%w: reading magic: %w", errInvalidCache, err)
+	}
+	if string(magic) != oftMagic {
+		return nil, fmt.Errorf("%w: invalid magic data", errInvalidCache)
+	}
+
+	line, err := br.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading url: %w", errInvalidCache, err)
+	}
+	url, ok := strings.CutPrefix(strings.TrimSuffix(line, "\n"), "url=")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing url", errInvalidCache)
+	}
+
+	b, err := io.ReadAll(br)
+	if err != nil {
+		return nil, fmt.Errorf("reading offset cache: %w", err)
+	}
+	if len(b) == 0 || len(b)%4 != 0 {
+		return nil, fmt.Errorf("%w: invalid size %d", errInvalidCache, len(b))
+	}
+
+	c := &OffsetCache{
+		URL:     url,
+		Offsets: make([]uint32, len(b)/4),
+	}
+	for i := range c.Offsets {
+		c.Offsets[i] = binary.LittleEndian.Uint32(b[i*4:])
+		if i > 0 && c.Offsets[i] < c.Offsets[i-1] {
+			return nil, fmt.Errorf("%w: offsets not increasing", errInvalidCache)
+		}
+	}
+
+	return c, nil
+}
+
+// WriteOffsetCache writes the offset cache file to w.
+func WriteOffsetCache(w io.Writer, c *OffsetCache) error {
+	bw := bufio.NewWriter(w)
+	if _, err := fmt.Fprintf(bw, "%surl=%s\n", oftMagic, c.URL); err != nil {
+		return fmt.Errorf("writing offset cache: %w", err)
+	}
+	var b [4]byte
+	for _, o := range c.Offsets {
+		binary.LittleEndian.PutUint32(b[:], o)
+		if _, err := bw.Write(b[:]); err != nil {
+			return fmt.Errorf("writing offset cache: %w", err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("writing offset cache: %w", err)
+	}
+	return nil
+}
+
+// OffsetIndex provides random access to the entries in an uncompressed .idx
+// file using an offset cache. Only a single page of entries is read for each
+// lookup.
+type OffsetIndex struct {
+	r             io.ReaderAt
+	cache         *OffsetCache
+	wordcount     int64
+	idxoffsetbits int
+}
+
+// OffsetIndexOptions are options for an OffsetIndex.
+type OffsetIndexOptions struct {
+	// ScannerOptions are the options to use when reading the .idx file.
+	ScannerOptions *ScannerOptions
+
+	// WriteCache indicates that the offset cache should be written to the
+	// .oft file if it is missing or out of date. Failures to write the cache
+	// file are ignored.
+	WriteCache bool
+}
+
+// NewOffsetIndex returns a new OffsetIndex that reads entries from r using
+// the given offset cache. wordcount is the number of entries in the .idx file.
+func NewOffsetIndex(r io.ReaderAt, c *OffsetCache, wordcount int64, options *ScannerOptions) (*OffsetIndex, error) {
+	if options == nil {
+		options = DefaultScannerOptions
+	}
+	if options.OffsetBits != 32 && options.OffsetBits != 64 {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIdxOffset, options.OffsetBits)
+	}
+	pages := (wordcount + EntriesPerPage - 1) / EntriesPerPage
+	if wordcount < 0 || int64(len(c.Offsets)) < pages+1 {
+		return nil, fmt.Errorf("%w: %d pages for %d words", errInvalidCache, len(c.Offsets)-1, wordcount)
+	}
+
+	return &OffsetIndex{
+		r:             r,
+		cache:         c,
+		wordcount:     wordcount,
+		idxoffsetbits: options.OffsetBits,
+	}, nil
+}
+
+// NewOffsetIndexFromIfoPath opens the uncompressed .idx file for the given .ifo
+// file and returns an OffsetIndex. The offset cache is read from the .oft file
+// (e.g. dictionary.idx.oft) if it exists and is newer than the .idx file.
+// Otherwise it is built by scanning the .idx file. The OffsetIndex assumes
+// ownership of the .idx file and should be closed with the Close method.
+func NewOffsetIndexFromIfoPath(ifoPath string, wordcount int64, options *OffsetIndexOptions) (*OffsetIndex, error) {
+	if options == nil {
+		options = &OffsetIndexOptions{}
+	}
+
+	f, err := Open(ifoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	idxExt := strings.ToLower(filepath.Ext(f.Name()))
+	if idxExt == ".gz" || idxExt == ".dz" {
+		_ = f.Close()
+		return nil, fmt.Errorf("%w: %q", ErrCompressed, f.Name())
+	}
+
+	c, err := loadOffsetCache(f, options)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	i, err := NewOffsetIndex(f, c, wordcount, options.ScannerOptions)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return i, nil
+}
+
+// loadOffsetCache reads the offset cache for the .idx file f if it is up to
+// date or builds the offset cache and optionally writes it to the cache file.
+func loadOffsetCache(f *os.File, options *OffsetIndexOptions) (*OffsetCache, error) {
+	idxInfo, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("reading .idx file info: %w", err)
+	}
+
+	// Use the cache file if it is newer than the .idx file and matches its
+	// size. Invalid cache files are ignored and regenerated.
+	oftPath := f.Name() + ".oft"
+	oftInfo, statErr := os.Stat(oftPath)
+	if statErr == nil && !oftInfo.ModTime().Before(idxInfo.ModTime()) {
+		c, readErr := readOffsetCacheFile(oftPath)
+		if readErr == nil && int64(c.Offsets[len(c.Offsets)-1]) == idxInfo.Size() {
+			return c, nil
+		}
+	}
+
+	c, err := NewOffsetCache(io.NewSectionReader(f, 0, idxInfo.Size()), options.ScannerOptions)
+	if err != nil {
+		return nil, err
+	}
+	c.URL = f.Name()
+
+	if options.WriteCache {
+		_ = writeOffsetCacheFile(oftPath, c)
+	}
+
+	return c, nil
+}
+
+// readOffsetCacheFile reads the offset cache file at the given path.
+func readOffsetCacheFile(path string) (*OffsetCache, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening offset cache: %w", err)
+	}
+	defer f.Close()
+	return ReadOffsetCache(f)
+}
+
+// writeOffsetCacheFile writes the offset cache file at the given path.
+func writeOffsetCacheFile(path string, c *OffsetCache) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating offset cache: %w", err)
+	}
+	if err := WriteOffsetCache(f, c); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing offset cache: %w", err)
+	}
+	return nil
+}
+
+// Len returns the number of entries in the index.
+func (i *OffsetIndex) Len() int64 {
+	return i.wordcount
+}
+
+// Word returns the n-th entry in the .idx file.
+func (i *OffsetIndex) Word(n int64) (*Word, error) {
+	if n < 0 || n >= i.wordcount {
+		return nil, fmt.Errorf("%w: %d", ErrOutOfRange, n)
+	}
+
+	page := n / EntriesPerPage
+	start, end := i.cache.Offsets[page], i.cache.Offsets[page+1]
+	if end < start {
+		return nil, fmt.Errorf("%w: offsets not increasing", errInvalidCache)
+	}
+	b := make([]byte, end-start)
+	if _, err := i.r.ReadAt(b, int64(start)); err != nil {
+		return nil, fmt.Errorf("reading index page: %w", err)
+	}
+
+	s, err := NewScanner(io.NopCloser(bytes.NewReader(b)), &ScannerOptions{
+		OffsetBits: i.idxoffsetbits,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for j := page * EntriesPerPage; s.Scan(); j++ {
+		if j == n {
+			return s.Word(), nil
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("scanning index page: %w", err)
+	}
+
+	return nil, fmt.Errorf("%w: %d", ErrOutOfRange, n)
+}
+
+// Close closes the underlying reader if it implements [io.Closer].
+func (i *OffsetIndex) Close() error {
+	if c, ok := i.r.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			return fmt.Errorf("closing idx file: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package idx_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/ianlewis/go-stardict/idx"
+	"github.com/ianlewis/go-stardict/internal/testutil"
+)
+
+// makeWords returns n sorted index words.
+func makeWords(n int) []*idx.Word {
+	var words []*idx.Word
+	for i := range n {
+		words = append(words, &idx.Word{
+			Word:   fmt.Sprintf("word%04d", i),
+			Offset: uint64(i * 10),
+			Size:   10,
+		})
+	}
+	return words
+}
+
+// TestNewOffsetCache tests NewOffsetCache.
+func TestNewOffsetCache(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		words         int
+		idxoffsetbits int
+
+		expected []uint32
+	}{
+		{
+			name:          "empty",
+			idxoffsetbits: 32,
+			expected:      []uint32{0},
+		},
+		{
+			name:          "single page",
+			words:         3,
+			idxoffsetbits: 32,
+			// "wordNNNN\0" + 4 (offset) + 4 (size)
+			expected: []uint32{0, 51},
+		},
+		{
+			name:          "multiple pages",
+			words:         33,
+			idxoffsetbits: 32,
+			expected:      []uint32{0, 32 * 17, 33 * 17},
+		},
+		{
+			name:          "64-bit offsets",
+			words:         64,
+			idxoffsetbits: 64,
+			expected:      []uint32{0, 32 * 21, 64 * 21},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := testutil.MakeIndex(makeWords(test.words), test.idxoffsetbits)
+			c, err := idx.NewOffsetCache(bytes.NewReader(b), &idx.ScannerOptions{
+				OffsetBits: test.idxoffsetbits,
+			})
+			if err != nil {
+				t.Fatalf("NewOffsetCache: %v", err)
+			}
+
+			if diff := cmp.Diff(test.expected, c.Offsets); diff != "" {
+				t.Errorf("Offsets (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestOffsetCache_roundTrip tests writing and reading offset cache files.
+func TestOffsetCache_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	c := &idx.OffsetCache{
+		URL:     "/usr/share/stardict/dic/dictionary.idx",
+		Offsets: []uint32{0, 1234, 5678},
+	}
+
+	var buf bytes.Buffer
+	if err := idx.WriteOffsetCache(&buf, c); err != nil {
+		t.Fatalf("WriteOffsetCache: %v", err)
+	}
+
+	expected := "StarDict's oft file\nversion=2.4.8\nurl=/usr/share/stardict/dic/dictionary.idx\n" +
+		"\x00\x00\x00\x00\xd2\x04\x00\x00\x2e\x16\x00\x00"
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Fatalf("WriteOffsetCache (-want, +got):\n%s", diff)
+	}
+
+	got, err := idx.ReadOffsetCache(&buf)
+	if err != nil {
+		t.Fatalf("ReadOffsetCache: %v", err)
+	}
+	if diff := cmp.Diff(c, got); diff != "" {
+		t.Errorf("ReadOffsetCache (-want, +got):\n%s", diff)
+	}
+}
+
+// TestReadOffsetCache_invalid tests ReadOffsetCache with invalid data.
+func TestReadOffsetCache_invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "bad magic",
+			data: "StarDict's clt file\nversion=2.4.8\nurl=foo\n\x00\x00\x00\x00",
+		},
+		{
+			name: "missing url",
+			data: "StarDict's oft file\nversion=2.4.8\nfoo\n\x00\x00\x00\x00",
+		},
+		{
+			name: "no offsets",
+			data: "StarDict's oft file\nversion=2.4.8\nurl=foo\n",
+		},
+		{
+			name: "truncated offset",
+			data: "StarDict's oft file\nversion=2.4.8\nurl=foo\n\x00\x00",
+		},
+		{
+			name: "decreasing offsets",
+			data: "StarDict's oft file\nversion=2.4.8\nurl=foo\n\x02\x00\x00\x00\x01\x00\x00\x00",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := idx.ReadOffsetCache(bytes.NewReader([]byte(test.data))); err == nil {
+				t.Fatal("ReadOffsetCache: expected error")
+			}
+		})
+	}
+}
+
+// TestOffsetIndex_Word tests OffsetIndex.Word.
+func TestOffsetIndex_Word(t *testing.T) {
+	t.Parallel()
+
+	words := makeWords(70)
+	b := testutil.MakeIndex(words, 32)
+	c, err := idx.NewOffsetCache(bytes.NewReader(b), nil)
+	if err != nil {
+		t.Fatalf("NewOffsetCache: %v", err)
+	}
+
+	index, err := idx.NewOffsetIndex(bytes.NewReader(b), c, int64(len(words)), nil)
+	if err != nil {
+		t.Fatalf("NewOffsetIndex: %v", err)
+	}
+
+	for _, n := range []int64{0, 1, 31, 32, 33, 63, 64, 69} {
+		w, err := index.Word(n)
+		if err != nil {
+			t.Fatalf("Word(%d): %v", n, err)
+		}
+		if diff := cmp.Diff(words[n], w); diff != "" {
+			t.Errorf("Word(%d) (-want, +got):\n%s", n, diff)
+		}
+	}
+
+	for _, n := range []int64{-1, 70} {
+		if _, err := index.Word(n); !cmp.Equal(idx.ErrOutOfRange, err, cmpopts.EquateErrors()) {
+			t.Errorf("Word(%d): expected %v, got %v", n, idx.ErrOutOfRange, err)
+		}
+	}
+}
+
+// TestNewOffsetIndex_invalid tests NewOffsetIndex with a cache that does not
+// match the word count.
+func TestNewOffsetIndex_invalid(t *testing.T) {
+	t.Parallel()
+
+	b := testutil.MakeIndex(makeWords(40), 32)
+	c, err := idx.NewOffsetCache(bytes.NewReader(b), nil)
+	if err != nil {
+		t.Fatalf("NewOffsetCache: %v", err)
+	}
+
+	if _, err := idx.NewOffsetIndex(bytes.NewReader(b), c, 100, nil); err == nil {
+		t.Fatal("NewOffsetIndex: expected error")
+	}
+}
+
+// TestNewOffsetIndexFromIfoPath tests NewOffsetIndexFromIfoPath.
+func TestNewOffsetIndexFromIfoPath(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ifoPath := filepath.Join(dir, "dictionary.ifo")
+	idxPath := filepath.Join(dir, "dictionary.idx")
+	oftPath := idxPath + ".oft"
+
+	words := makeWords(50)
+	if err := os.WriteFile(idxPath, testutil.MakeIndex(words, 32), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	index, err := idx.NewOffsetIndexFromIfoPath(ifoPath, int64(len(words)), &idx.OffsetIndexOptions{
+		WriteCache: true,
+	})
+	if err != nil {
+		t.Fatalf("NewOffsetIndexFromIfoPath: %v", err)
+	}
+	w, err := index.Word(40)
+	if err != nil {
+		t.Fatalf("Word: %v", err)
+	}
+	if diff := cmp.Diff(words[40], w); diff != "" {
+		t.Errorf("Word (-want, +got):\n%s", diff)
+	}
+	if err := index.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// The cache file should have been written.
+	f, err := os.Open(oftPath)
+	if err != nil {
+		t.Fatalf("opening cache: %v", err)
+	}
+	defer f.Close()
+	c, err := idx.ReadOffsetCache(f)
+	if err != nil {
+		t.Fatalf("ReadOffsetCache: %v", err)
+	}
+	if diff := cmp.Diff([]uint32{0, 32 * 17, 50 * 17}, c.Offsets); diff != "" {
+		t.Errorf("Offsets (-want, +got):\n%s", diff)
+	}
+
+	// The index should be usable when read from the cache file.
+	index, err = idx.NewOffsetIndexFromIfoPath(ifoPath, int64(len(words)), nil)
+	if err != nil {
+		t.Fatalf("NewOffsetIndexFromIfoPath: %v", err)
+	}
+	defer index.Close()
+	w, err = index.Word(49)
+	if err != nil {
+		t.Fatalf("Word: %v", err)
+	}
+	if diff := cmp.Diff(words[49], w); diff != "" {
+		t.Errorf("Word (-want, +got):\n%s", diff)
+	}
+}
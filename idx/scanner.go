@@ -21,10 +21,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
+
+	"github.com/ianlewis/go-stardict/internal/gzipfile"
 )
 
-// ErrInvalidIdxOffset indicates that the OffsetBits is an invalid value.
-var ErrInvalidIdxOffset = errors.New("invalid idxoffsetbits")
+var (
+	// ErrInvalidIdxOffset indicates that the OffsetBits is an invalid value.
+	ErrInvalidIdxOffset = errors.New("invalid idxoffsetbits")
+
+	// ErrTruncated indicates that the index ends with an incomplete entry.
+	ErrTruncated = errors.New("truncated index entry")
+)
 
 // Scanner scans an index from start to end.
 type Scanner struct {
@@ -65,13 +72,20 @@ func NewScanner(r io.ReadCloser, options *ScannerOptions) (*Scanner, error) {
 	return s, nil
 }
 
-// NewScannerFromIfoPath returns a new in-memory index.
+// NewScannerFromIfoPath returns a new index scanner for the .idx file given
+// the path to the .ifo file. Compressed .idx.gz files are decompressed.
 func NewScannerFromIfoPath(ifoPath string, options *ScannerOptions) (*Scanner, error) {
 	f, err := Open(ifoPath)
 	if err != nil {
 		return nil, err
 	}
-	return NewScanner(f, options)
+
+	r, err := gzipfile.NewReader(f)
+	if err != nil {
+		//nolint:wrapcheck // error is already wrapped.
+		return nil, err
+	}
+	return NewScanner(r, options)
 }
 
 // Scan advances the index to the next index entry. It returns false if the
@@ -126,7 +140,7 @@ func (s *Scanner) splitIndex(data []byte, atEOF bool) (advance int, token []byte
 	}
 
 	if atEOF {
-		return len(data), data, nil
+		return 0, nil, fmt.Errorf("%w: %d trailing bytes", ErrTruncated, len(data))
 	}
 
 	// Request more data.
@@ -16,7 +16,11 @@ package idx_test
 
 import (
 	"bytes"
+	"compress/gzip"
+	"errors"
 	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/ianlewis/go-stardict/idx"
@@ -103,3 +107,84 @@ func TestIdxScanner(t *testing.T) {
 		})
 	}
 }
+
+// TestIdxScanner_truncated tests that a truncated final entry is an error.
+func TestIdxScanner_truncated(t *testing.T) {
+	t.Parallel()
+
+	expected := []*idx.Word{
+		{
+			Word:   "hoge",
+			Offset: 123,
+			Size:   456,
+		},
+	}
+	b := testutil.MakeIndex(append(expected, &idx.Word{
+		Word:   "fuga",
+		Offset: 12,
+		Size:   45,
+	}), 32)
+
+	s, err := idx.NewScanner(io.NopCloser(bytes.NewReader(b[:len(b)-2])), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var words []*idx.Word
+	for s.Scan() {
+		words = append(words, s.Word())
+	}
+	if err := s.Err(); !errors.Is(err, idx.ErrTruncated) {
+		t.Errorf("Err: expected %v, got %v", idx.ErrTruncated, err)
+	}
+	expectWordsEqual(t, expected, words)
+}
+
+// TestNewScannerFromIfoPath tests that NewScannerFromIfoPath reads
+// uncompressed and gzip compressed .idx files.
+func TestNewScannerFromIfoPath(t *testing.T) {
+	t.Parallel()
+
+	expected := []*idx.Word{
+		{Word: "fuga", Offset: 0, Size: 3},
+		{Word: "hoge", Offset: 3, Size: 4},
+	}
+	b := testutil.MakeIndex(expected, 32)
+
+	var gz bytes.Buffer
+	z := gzip.NewWriter(&gz)
+	if _, err := z.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for ext, data := range map[string][]byte{
+		".idx":    b,
+		".idx.gz": gz.Bytes(),
+	} {
+		t.Run(ext, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "dictionary"+ext), data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			s, err := idx.NewScannerFromIfoPath(filepath.Join(dir, "dictionary.ifo"), nil)
+			if err != nil {
+				t.Fatalf("NewScannerFromIfoPath: %v", err)
+			}
+			defer s.Close()
+
+			var words []*idx.Word
+			for s.Scan() {
+				words = append(words, s.Word())
+			}
+			if err := s.Err(); err != nil {
+				t.Fatalf("Err: %v", err)
+			}
+			expectWordsEqual(t, expected, words)
+		})
+	}
+}
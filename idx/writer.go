@@ -0,0 +1,166 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package idx
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+
+	"github.com/ianlewis/go-stardict/collation"
+	"github.com/ianlewis/go-stardict/internal/headword"
+)
+
+// MaxWordLength is the maximum length of a word in bytes.
+const MaxWordLength = headword.MaxLength
+
+var (
+	// ErrInvalidWord indicates that a word cannot be written to an index.
+	ErrInvalidWord = errors.New("invalid word")
+
+	// ErrWriterClosed indicates that the Writer has already been flushed.
+	ErrWriterClosed = errors.New("writer already flushed")
+
+	errOffsetTooLarge = errors.New("offset too large")
+)
+
+// WriterOptions are options for a Writer.
+type WriterOptions struct {
+	// OffsetBits are the number of bits in the offset fields. Valid values for
+	// OffsetBits are either 32 or 64.
+	OffsetBits int
+}
+
+// DefaultWriterOptions is the default options for a Writer.
+var DefaultWriterOptions = &WriterOptions{
+	OffsetBits: 32,
+}
+
+// Writer writes .idx files. Words are buffered and are written when Flush
+// is called sorted in the order used by StarDict (see
+// [collation.StardictCompare]).
+type Writer struct {
+	w             io.Writer
+	idxoffsetbits int
+	words         []*Word
+	order         []int
+	size          int64
+	flushed       bool
+}
+
+// NewWriter returns a new Writer that writes the index to w.
+func NewWriter(w io.Writer, options *WriterOptions) (*Writer, error) {
+	if options == nil {
+		options = DefaultWriterOptions
+	}
+	if options.OffsetBits != 32 && options.OffsetBits != 64 {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIdxOffset, options.OffsetBits)
+	}
+
+	return &Writer{
+		w:             w,
+		idxoffsetbits: options.OffsetBits,
+	}, nil
+}
+
+// Add adds a word to the index. Words must be non-empty, must not contain a
+// null byte, and must be no longer than MaxWordLength bytes.
+func (w *Writer) Add(word *Word) error {
+	if w.flushed {
+		return ErrWriterClosed
+	}
+	if !headword.Valid(word.Word) {
+		return fmt.Errorf("%w: %q", ErrInvalidWord, word.Word)
+	}
+	if w.idxoffsetbits == 32 && word.Offset > math.MaxUint32 {
+		return fmt.Errorf("%w: %d", errOffsetTooLarge, word.Offset)
+	}
+
+	w.words = append(w.words, &Word{
+		Word:   word.Word,
+		Offset: word.Offset,
+		Size:   word.Size,
+	})
+	w.size += int64(len(word.Word) + 1 + w.idxoffsetbits/8 + 4)
+	return nil
+}
+
+// Flush sorts and writes the index. No words may be added after Flush is
+// called.
+func (w *Writer) Flush() error {
+	if w.flushed {
+		return ErrWriterClosed
+	}
+	w.flushed = true
+
+	sorted := make([]int, len(w.words))
+	for i := range sorted {
+		sorted[i] = i
+	}
+	slices.SortStableFunc(sorted, func(a, b int) int {
+		return collation.StardictCompare(w.words[a].Word, w.words[b].Word)
+	})
+
+	w.order = make([]int, len(w.words))
+	bw := bufio.NewWriter(w.w)
+	buf := make([]byte, w.idxoffsetbits/8+4)
+	for i, n := range sorted {
+		w.order[n] = i
+		word := w.words[n]
+
+		if w.idxoffsetbits == 64 {
+			binary.BigEndian.PutUint64(buf, word.Offset)
+		} else {
+			binary.BigEndian.PutUint32(buf, uint32(word.Offset))
+		}
+		binary.BigEndian.PutUint32(buf[w.idxoffsetbits/8:], word.Size)
+
+		if _, err := bw.WriteString(word.Word); err != nil {
+			return fmt.Errorf("writing index: %w", err)
+		}
+		if err := bw.WriteByte(0); err != nil {
+			return fmt.Errorf("writing index: %w", err)
+		}
+		if _, err := bw.Write(buf); err != nil {
+			return fmt.Errorf("writing index: %w", err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("writing index: %w", err)
+	}
+	return nil
+}
+
+// WordCount returns the number of words in the index. This is the value of
+// the wordcount key in the .ifo file.
+func (w *Writer) WordCount() int64 {
+	return int64(len(w.words))
+}
+
+// FileSize returns the size of the index in bytes. This is the value of the
+// idxfilesize key in the .ifo file.
+func (w *Writer) FileSize() int64 {
+	return w.size
+}
+
+// Order returns the position in the written index of each word in the order
+// it was added. Order returns nil until Flush is called.
+func (w *Writer) Order() []int {
+	return slices.Clone(w.order)
+}
@@ -0,0 +1,210 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package idx_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/idx"
+)
+
+// TestWriter tests Writer.
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		words         []*idx.Word
+		idxoffsetbits int
+
+		expected []*idx.Word
+		order    []int
+	}{
+		{
+			name:          "empty",
+			idxoffsetbits: 32,
+			order:         []int{},
+		},
+		{
+			name: "sorted",
+			words: []*idx.Word{
+				{Word: "foo", Offset: 0, Size: 1},
+				{Word: "Bar", Offset: 1, Size: 2},
+				{Word: "bar", Offset: 3, Size: 3},
+				{Word: "BAZ", Offset: 6, Size: 4},
+				{Word: "_x", Offset: 10, Size: 5},
+			},
+			idxoffsetbits: 32,
+			expected: []*idx.Word{
+				{Word: "_x", Offset: 10, Size: 5},
+				{Word: "Bar", Offset: 1, Size: 2},
+				{Word: "bar", Offset: 3, Size: 3},
+				{Word: "BAZ", Offset: 6, Size: 4},
+				{Word: "foo", Offset: 0, Size: 1},
+			},
+			order: []int{4, 1, 2, 3, 0},
+		},
+		{
+			name: "duplicates are stable",
+			words: []*idx.Word{
+				{Word: "foo", Offset: 5, Size: 1},
+				{Word: "foo", Offset: 0, Size: 1},
+			},
+			idxoffsetbits: 32,
+			expected: []*idx.Word{
+				{Word: "foo", Offset: 5, Size: 1},
+				{Word: "foo", Offset: 0, Size: 1},
+			},
+			order: []int{0, 1},
+		},
+		{
+			name: "64-bit offsets",
+			words: []*idx.Word{
+				{Word: "foo", Offset: math.MaxUint32 + 1, Size: 1},
+				{Word: "bar", Offset: 0, Size: 2},
+			},
+			idxoffsetbits: 64,
+			expected: []*idx.Word{
+				{Word: "bar", Offset: 0, Size: 2},
+				{Word: "foo", Offset: math.MaxUint32 + 1, Size: 1},
+			},
+			order: []int{1, 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			w, err := idx.NewWriter(&buf, &idx.WriterOptions{
+				OffsetBits: test.idxoffsetbits,
+			})
+			if err != nil {
+				t.Fatalf("NewWriter: %v", err)
+			}
+			for _, word := range test.words {
+				if err := w.Add(word); err != nil {
+					t.Fatalf("Add: %v", err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatalf("Flush: %v", err)
+			}
+
+			if got, want := w.WordCount(), int64(len(test.words)); got != want {
+				t.Errorf("WordCount: expected %d, got %d", want, got)
+			}
+			if got, want := w.FileSize(), int64(buf.Len()); got != want {
+				t.Errorf("FileSize: expected %d, got %d", want, got)
+			}
+			if diff := cmp.Diff(test.order, w.Order()); diff != "" {
+				t.Errorf("Order (-want, +got):\n%s", diff)
+			}
+
+			s, err := idx.NewScanner(io.NopCloser(&buf), &idx.ScannerOptions{
+				OffsetBits: test.idxoffsetbits,
+			})
+			if err != nil {
+				t.Fatalf("NewScanner: %v", err)
+			}
+			var words []*idx.Word
+			for s.Scan() {
+				words = append(words, s.Word())
+			}
+			if err := s.Err(); err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			if diff := cmp.Diff(test.expected, words); diff != "" {
+				t.Errorf("words (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestWriter_Add_invalid tests Writer.Add with invalid words.
+func TestWriter_Add_invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		word *idx.Word
+		err  error
+	}{
+		{
+			name: "empty",
+			word: &idx.Word{},
+			err:  idx.ErrInvalidWord,
+		},
+		{
+			name: "null byte",
+			word: &idx.Word{Word: "foo\x00bar"},
+			err:  idx.ErrInvalidWord,
+		},
+		{
+			name: "too long",
+			word: &idx.Word{Word: strings.Repeat("a", idx.MaxWordLength+1)},
+			err:  idx.ErrInvalidWord,
+		},
+		{
+			name: "offset too large",
+			word: &idx.Word{Word: "foo", Offset: math.MaxUint32 + 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			w, err := idx.NewWriter(&bytes.Buffer{}, nil)
+			if err != nil {
+				t.Fatalf("NewWriter: %v", err)
+			}
+			err = w.Add(test.word)
+			if err == nil {
+				t.Fatal("Add: expected error")
+			}
+			if test.err != nil && !errors.Is(err, test.err) {
+				t.Errorf("Add: expected %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+// TestWriter_flushed tests that a Writer can't be used after Flush.
+func TestWriter_flushed(t *testing.T) {
+	t.Parallel()
+
+	w, err := idx.NewWriter(&bytes.Buffer{}, nil)
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if err := w.Add(&idx.Word{Word: "foo"}); !errors.Is(err, idx.ErrWriterClosed) {
+		t.Errorf("Add: expected %v, got %v", idx.ErrWriterClosed, err)
+	}
+	if err := w.Flush(); !errors.Is(err, idx.ErrWriterClosed) {
+		t.Errorf("Flush: expected %v, got %v", idx.ErrWriterClosed, err)
+	}
+}
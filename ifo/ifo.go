@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package ifo implements reading .ifo files.
+// Package ifo implements reading and writing .ifo files.
 package ifo
 
 import (
@@ -21,58 +21,80 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"slices"
 	"strings"
 )
 
 var keyRegex = regexp.MustCompile("[a-zA-Z0-9-_]+")
 
+// utf8BOM is the UTF-8 byte order mark.
+const utf8BOM = "\uFEFF"
+
 var (
-	errNoVersion  = errors.New("missing version")
-	errInvalidKey = errors.New("invalid key")
+	// ErrSyntax indicates a syntax error in the .ifo file.
+	ErrSyntax = errors.New("syntax error")
+
+	// ErrInvalidIdxOffsetBits indicates that the idxoffsetbits value is not
+	// 32 or 64.
+	ErrInvalidIdxOffsetBits = errors.New("invalid idxoffsetbits")
+
+	errNoVersion    = errors.New("missing version")
+	errInvalidKey   = errors.New("invalid key")
+	errInvalidValue = errors.New("invalid value")
+	errInvalidMagic = errors.New("invalid magic")
 )
 
-// Ifo holds metadata read from .ifo files.
+// Ifo holds metadata read from .ifo files. The order of keys is preserved and
+// unknown keys are retained so that the file can be written back unchanged.
 type Ifo struct {
 	magic    string
+	keys     []string
 	metadata map[string]string
 }
 
-// New returns a new metadata object.
+// New returns a new metadata object read from r. A leading byte order mark
+// and CRLF line endings are accepted. Errors include the line number of the
+// offending line.
 func New(r io.Reader) (*Ifo, error) {
 	ifo := &Ifo{
 		metadata: map[string]string{},
 	}
 
 	s := bufio.NewScanner(bufio.NewReader(r))
+	lineNum := 1
 	if s.Scan() {
-		ifo.magic = s.Text()
+		ifo.magic = strings.TrimSuffix(strings.TrimPrefix(s.Text(), utf8BOM), "\r")
 	}
 
 	i := 0
 	for s.Scan() {
-		line := s.Text()
+		lineNum++
+		line := strings.TrimSuffix(s.Text(), "\r")
 		if strings.Trim(line, " ") == "" {
 			continue
 		}
-		v := strings.SplitN(line, "=", 2)
-		key := strings.TrimRight(v[0], " ")
-		value := strings.TrimLeft(v[1], " ")
-		if !keyRegex.MatchString(key) {
-			return nil, fmt.Errorf("%w: %v", errInvalidKey, key)
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: line %d: missing '='", ErrSyntax, lineNum)
+		}
+		key := strings.TrimRight(k, " ")
+		value := strings.TrimLeft(v, " ")
+		if !validKey(key) {
+			return nil, fmt.Errorf("%w: line %d: %w: %q", ErrSyntax, lineNum, errInvalidKey, key)
 		}
 		if i == 0 && key != "version" {
-			return nil, errNoVersion
+			return nil, fmt.Errorf("%w: line %d: %w", ErrSyntax, lineNum, errNoVersion)
 		}
 
-		ifo.metadata[key] = value
+		ifo.Set(key, value)
 		i++
 	}
-	if len(ifo.metadata) == 0 {
-		return nil, errNoVersion
-	}
 	if err := s.Err(); err != nil {
 		return nil, fmt.Errorf("scanning ifo file: %w", err)
 	}
+	if len(ifo.metadata) == 0 {
+		return nil, errNoVersion
+	}
 
 	return ifo, nil
 }
@@ -86,3 +108,76 @@ func (i *Ifo) Magic() string {
 func (i *Ifo) Value(key string) string {
 	return i.metadata[key]
 }
+
+// Keys returns the metadata keys in the order they appear in the file.
+func (i *Ifo) Keys() []string {
+	return slices.Clone(i.keys)
+}
+
+// Set sets the value for the key. New keys are added after existing keys.
+func (i *Ifo) Set(key, value string) {
+	if i.metadata == nil {
+		i.metadata = map[string]string{}
+	}
+	if _, ok := i.metadata[key]; !ok {
+		i.keys = append(i.keys, key)
+	}
+	i.metadata[key] = value
+}
+
+// Delete removes the key from the metadata.
+func (i *Ifo) Delete(key string) {
+	if _, ok := i.metadata[key]; !ok {
+		return
+	}
+	delete(i.metadata, key)
+	i.keys = slices.DeleteFunc(i.keys, func(k string) bool {
+		return k == key
+	})
+}
+
+// validKey returns true if key is a valid key. Keys are accepted if they
+// contain a letter, digit, '-', or '_' for compatibility with existing files
+// and must not contain characters that would change how the key is read.
+func validKey(key string) bool {
+	return keyRegex.MatchString(key) && !strings.ContainsAny(key, "=\n") && key == strings.TrimRight(key, " ")
+}
+
+// Write writes the .ifo file to w. The version key is always written first
+// as required by StarDict. Other keys are written in order.
+func Write(w io.Writer, i *Ifo) error {
+	if i.magic == "" || strings.ContainsAny(i.magic, "\r\n") {
+		return fmt.Errorf("%w: %q", errInvalidMagic, i.magic)
+	}
+	if i.metadata["version"] == "" {
+		return errNoVersion
+	}
+
+	keys := []string{"version"}
+	for _, k := range i.keys {
+		if k != "version" {
+			keys = append(keys, k)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(i.magic + "\n"); err != nil {
+		return fmt.Errorf("writing ifo file: %w", err)
+	}
+	for _, k := range keys {
+		v := i.metadata[k]
+		if !validKey(k) {
+			return fmt.Errorf("%w: %q", errInvalidKey, k)
+		}
+		if strings.ContainsAny(v, "\r\n") {
+			return fmt.Errorf("%w: %s: contains newline", errInvalidValue, k)
+		}
+		if _, err := bw.WriteString(k + "=" + v + "\n"); err != nil {
+			return fmt.Errorf("writing ifo file: %w", err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("writing ifo file: %w", err)
+	}
+	return nil
+}
@@ -16,7 +16,11 @@ package ifo
 
 import (
 	"bytes"
+	"errors"
+	"strings"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 // TestIfo tests the Ifo type.
@@ -48,6 +52,37 @@ version=1.0.0`,
 			data: `test magic`,
 			err:  true,
 		},
+		{
+			name: "version not first",
+			data: "test magic\nbookname=foo\nversion=1.0.0",
+			err:  true,
+		},
+		{
+			name: "missing equals",
+			data: "test magic\nversion=1.0.0\nbookname",
+			err:  true,
+		},
+		{
+			name: "invalid key",
+			data: "test magic\nversion=1.0.0\n...=foo",
+			err:  true,
+		},
+		{
+			name: "crlf and bom",
+			data: "\uFEFFtest magic\r\nversion=1.0.0\r\nbookname = foo\r\n\r\n",
+			expect: func(t *testing.T, i *Ifo) {
+				t.Helper()
+				if want, got := "test magic", i.Magic(); want != got {
+					t.Fatalf("magic; want: %q, got: %q", want, got)
+				}
+				if want, got := "1.0.0", i.Value("version"); want != got {
+					t.Fatalf("version; want: %q, got: %q", want, got)
+				}
+				if want, got := "foo", i.Value("bookname"); want != got {
+					t.Fatalf("bookname; want: %q, got: %q", want, got)
+				}
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -67,3 +102,250 @@ version=1.0.0`,
 		})
 	}
 }
+
+// TestNew_lineNumber tests that syntax errors include the line number.
+func TestNew_lineNumber(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(strings.NewReader("test magic\nversion=1.0.0\n\nbookname"))
+	if !errors.Is(err, ErrSyntax) {
+		t.Fatalf("New: expected %v, got %v", ErrSyntax, err)
+	}
+	if !strings.Contains(err.Error(), "line 4") {
+		t.Errorf("New: expected line number in error: %v", err)
+	}
+}
+
+// TestNew_keys tests that keys containing characters other than letters,
+// digits, '-', and '_' are accepted when reading.
+func TestNew_keys(t *testing.T) {
+	t.Parallel()
+
+	i, err := New(strings.NewReader("StarDict's dict ifo file\nversion=3.0.0\nbook name=foo\nx.custom=bar\n"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if got, want := i.Value("book name"), "foo"; got != want {
+		t.Errorf("Value: expected %q, got %q", want, got)
+	}
+	if got, want := i.Value("x.custom"), "bar"; got != want {
+		t.Errorf("Value: expected %q, got %q", want, got)
+	}
+
+	// The keys are written unchanged.
+	var buf bytes.Buffer
+	if err := Write(&buf, i); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if diff := cmp.Diff("StarDict's dict ifo file\nversion=3.0.0\nbook name=foo\nx.custom=bar\n", buf.String()); diff != "" {
+		t.Errorf("Write (-want, +got):\n%s", diff)
+	}
+}
+
+// TestWrite tests that .ifo files are written in order with unknown keys
+// preserved.
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	data := "StarDict's dict ifo file\nversion=3.0.0\nbookname=foo\nwordcount=2\nx-custom=bar\nidxfilesize=10\n"
+
+	i, err := New(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if diff := cmp.Diff([]string{"version", "bookname", "wordcount", "x-custom", "idxfilesize"}, i.Keys()); diff != "" {
+		t.Errorf("Keys (-want, +got):\n%s", diff)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, i); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if diff := cmp.Diff(data, buf.String()); diff != "" {
+		t.Errorf("Write (-want, +got):\n%s", diff)
+	}
+}
+
+// TestWrite_invalid tests writing invalid .ifo files.
+func TestWrite_invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		ifo  *Ifo
+	}{
+		{
+			name: "no magic",
+			ifo:  FromInfo(&Info{Version: "3.0.0"}),
+		},
+		{
+			name: "no version",
+			ifo:  FromInfo(&Info{Magic: DictMagic}),
+		},
+		{
+			name: "newline in value",
+			ifo: func() *Ifo {
+				i := FromInfo(&Info{Magic: DictMagic, Version: "3.0.0"})
+				i.Set("bookname", "foo\nbar")
+				return i
+			}(),
+		},
+		{
+			name: "invalid key",
+			ifo: func() *Ifo {
+				i := FromInfo(&Info{Magic: DictMagic, Version: "3.0.0"})
+				i.Set("book=name", "foo")
+				return i
+			}(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := Write(&bytes.Buffer{}, test.ifo); err == nil {
+				t.Fatal("Write: expected error")
+			}
+		})
+	}
+}
+
+// TestIfo_Info tests Ifo.Info.
+func TestIfo_Info(t *testing.T) {
+	t.Parallel()
+
+	i, err := New(strings.NewReader(`StarDict's dict ifo file
+version=3.0.0
+bookname=foo
+wordcount=2
+synwordcount=3
+idxfilesize=40
+idxoffsetbits=64
+author=me
+description=line one<br>line two
+date=2026.01.02
+dicttype=wordnet
+lang=en-ja
+from=en
+to=ja
+sametypesequence=m`))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	info, err := i.Info()
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+
+	expected := &Info{
+		Magic:            DictMagic,
+		Version:          "3.0.0",
+		BookName:         "foo",
+		WordCount:        2,
+		SynWordCount:     3,
+		IdxFileSize:      40,
+		IdxOffsetBits:    64,
+		Author:           "me",
+		Description:      "line one\nline two",
+		Date:             "2026.01.02",
+		SameTypeSequence: "m",
+		DictType:         "wordnet",
+		Lang:             "en-ja",
+		From:             "en",
+		To:               "ja",
+	}
+	if diff := cmp.Diff(expected, info); diff != "" {
+		t.Errorf("Info (-want, +got):\n%s", diff)
+	}
+}
+
+// TestIfo_Info_invalid tests Ifo.Info with invalid values.
+func TestIfo_Info_invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, line := range []string{"wordcount=abc", "idxfilesize=-1", "idxoffsetbits=16"} {
+		t.Run(line, func(t *testing.T) {
+			t.Parallel()
+
+			i, err := New(strings.NewReader("StarDict's dict ifo file\nversion=3.0.0\n" + line))
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if _, err := i.Info(); err == nil {
+				t.Fatal("Info: expected error")
+			}
+		})
+	}
+}
+
+// TestIfo_Info_idxoffsetbits tests that idxoffsetbits is ignored for versions
+// other than 3.0.0.
+func TestIfo_Info_idxoffsetbits(t *testing.T) {
+	t.Parallel()
+
+	i, err := New(strings.NewReader("StarDict's dict ifo file\nversion=2.4.2\nidxoffsetbits=junk"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	info, err := i.Info()
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if got, want := info.IdxOffsetBits, 0; got != want {
+		t.Errorf("IdxOffsetBits: expected %d, got %d", want, got)
+	}
+}
+
+// TestIfo_SetInfo tests that editing typed metadata preserves key order and
+// unknown keys.
+func TestIfo_SetInfo(t *testing.T) {
+	t.Parallel()
+
+	i, err := New(strings.NewReader("StarDict's dict ifo file\nversion=2.4.2\nx-custom=bar\n" +
+		"bookname=foo\nwordcount=2\nidxfilesize=10\nauthor=me\n"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	info, err := i.Info()
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	info.BookName = "bar"
+	info.Author = ""
+	info.Description = "a\nb"
+	i.SetInfo(info)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, i); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	expected := "StarDict's dict ifo file\nversion=2.4.2\nx-custom=bar\nbookname=bar\nwordcount=2\n" +
+		"idxfilesize=10\ndescription=a<br>b\n"
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Errorf("Write (-want, +got):\n%s", diff)
+	}
+}
+
+// TestFromInfo tests FromInfo.
+func TestFromInfo(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FromInfo(&Info{
+		Magic:    DictMagic,
+		Version:  "3.0.0",
+		BookName: "empty",
+	})); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	// wordcount and idxfilesize are required for dictionaries.
+	expected := "StarDict's dict ifo file\nversion=3.0.0\nbookname=empty\nwordcount=0\nidxfilesize=0\n"
+	if diff := cmp.Diff(expected, buf.String()); diff != "" {
+		t.Errorf("Write (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DictMagic is the magic string for dictionary .ifo files.
+	DictMagic = "StarDict's dict ifo file"
+
+	// TreeDictMagic is the magic string for tree dictionary .ifo files.
+	TreeDictMagic = "StarDict's treedict ifo file"
+)
+
+// Info is the typed metadata in an .ifo file. Empty or zero fields are
+// omitted when written except for wordcount and idxfilesize, which are
+// required for dictionaries that are not tree dictionaries.
+type Info struct {
+	// Magic is the magic string on the first line of the file.
+	Magic string
+
+	// Version is the file format version (e.g. "2.4.2" or "3.0.0").
+	Version string
+
+	// BookName is the dictionary name.
+	BookName string
+
+	// WordCount is the number of entries in the .idx file.
+	WordCount int64
+
+	// SynWordCount is the number of entries in the .syn file.
+	SynWordCount int64
+
+	// IdxFileSize is the size of the uncompressed .idx file.
+	IdxFileSize int64
+
+	// TdxFileSize is the size of the uncompressed .tdx file for tree
+	// dictionaries.
+	TdxFileSize int64
+
+	// IdxOffsetBits is the number of bits in .idx offsets. It is either 32
+	// or 64 or zero if unset. It is only read for version 3.0.0 files.
+	IdxOffsetBits int
+
+	// Author is the dictionary author.
+	Author string
+
+	// Email is the author's email address.
+	Email string
+
+	// Website is the dictionary's website.
+	Website string
+
+	// Description is the dictionary description. Line breaks, which are
+	// stored as "<br>" in the file, are converted to newlines.
+	Description string
+
+	// Date is the date the dictionary was created.
+	Date string
+
+	// SameTypeSequence is the sequence of data types for every entry.
+	SameTypeSequence string
+
+	// DictType is the type of dictionary (e.g. "wordnet").
+	DictType string
+
+	// Lang is the dictionary language or language pair (e.g. "en-ja").
+	Lang string
+
+	// From is the source language of the dictionary.
+	From string
+
+	// To is the target language of the dictionary.
+	To string
+}
+
+// Info returns the typed metadata. An error is returned if a numeric value
+// is invalid. The idxoffsetbits key is ignored unless the version is 3.0.0.
+func (i *Ifo) Info() (*Info, error) {
+	info := &Info{
+		Magic:            i.magic,
+		Version:          i.Value("version"),
+		BookName:         i.Value("bookname"),
+		Author:           i.Value("author"),
+		Email:            i.Value("email"),
+		Website:          i.Value("website"),
+		Description:      strings.ReplaceAll(i.Value("description"), "<br>", "\n"),
+		Date:             i.Value("date"),
+		SameTypeSequence: i.Value("sametypesequence"),
+		DictType:         i.Value("dicttype"),
+		Lang:             i.Value("lang"),
+		From:             i.Value("from"),
+		To:               i.Value("to"),
+	}
+
+	for _, f := range []struct {
+		key string
+		val *int64
+	}{
+		{"wordcount", &info.WordCount},
+		{"synwordcount", &info.SynWordCount},
+		{"idxfilesize", &info.IdxFileSize},
+		{"tdxfilesize", &info.TdxFileSize},
+	} {
+		v := i.Value(f.key)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w: %s: %q", errInvalidValue, f.key, v)
+		}
+		*f.val = n
+	}
+
+	// TODO(#28): version check should be >= 3.0.0
+	if v := i.Value("idxoffsetbits"); v != "" && info.Version == "3.0.0" {
+		n, err := strconv.Atoi(v)
+		if err != nil || (n != 32 && n != 64) {
+			return nil, fmt.Errorf("%w: %w: %q", errInvalidValue, ErrInvalidIdxOffsetBits, v)
+		}
+		info.IdxOffsetBits = n
+	}
+
+	return info, nil
+}
+
+// SetInfo updates the metadata from the typed metadata. Existing keys keep
+// their position, new keys are added at the end, and keys for empty or zero
+// fields are removed. Unknown keys are not changed.
+func (i *Ifo) SetInfo(info *Info) {
+	i.magic = info.Magic
+
+	for _, f := range []struct {
+		key string
+		val string
+	}{
+		{"version", info.Version},
+		{"bookname", info.BookName},
+		{"wordcount", formatInt(info.WordCount)},
+		{"synwordcount", formatInt(info.SynWordCount)},
+		{"idxfilesize", formatInt(info.IdxFileSize)},
+		{"tdxfilesize", formatInt(info.TdxFileSize)},
+		{"idxoffsetbits", formatInt(int64(info.IdxOffsetBits))},
+		{"author", info.Author},
+		{"email", info.Email},
+		{"website", info.Website},
+		{"description", strings.ReplaceAll(strings.ReplaceAll(info.Description, "\r\n", "\n"), "\n", "<br>")},
+		{"date", info.Date},
+		{"sametypesequence", info.SameTypeSequence},
+		{"dicttype", info.DictType},
+		{"lang", info.Lang},
+		{"from", info.From},
+		{"to", info.To},
+	} {
+		if f.val == "" && info.Magic != TreeDictMagic && (f.key == "wordcount" || f.key == "idxfilesize") {
+			f.val = "0"
+		}
+		if f.val == "" {
+			i.Delete(f.key)
+			continue
+		}
+		i.Set(f.key, f.val)
+	}
+}
+
+// FromInfo returns a new Ifo with the given typed metadata.
+func FromInfo(info *Info) *Ifo {
+	i := &Ifo{
+		metadata: map[string]string{},
+	}
+	i.SetInfo(info)
+	return i
+}
+
+// formatInt formats n as a decimal string or returns an empty string if n is
+// zero.
+func formatInt(n int64) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.FormatInt(n, 10)
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gzipfile reads files that may be gzip or dictzip compressed.
+package gzipfile
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ianlewis/go-dictzip"
+)
+
+// IsCompressed returns true if the file name has a .gz or .dz extension.
+func IsCompressed(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".gz" || ext == ".dz"
+}
+
+// ReadCloser reads the decompressed data of a file. Closing it closes both
+// the decompressor and the file.
+type ReadCloser struct {
+	r io.ReadCloser
+	f *os.File
+}
+
+// Read implements [io.Reader.Read].
+func (r *ReadCloser) Read(p []byte) (int, error) {
+	//nolint:wrapcheck // errors are returned unwrapped like the underlying reader.
+	return r.r.Read(p)
+}
+
+// Close closes the decompressor and the underlying file.
+func (r *ReadCloser) Close() error {
+	err := r.r.Close()
+	if fErr := r.f.Close(); fErr != nil && err == nil {
+		err = fErr
+	}
+	if err != nil {
+		return fmt.Errorf("closing %q: %w", r.f.Name(), err)
+	}
+	return nil
+}
+
+// Open opens the file at path and returns a reader for its data. See
+// [NewReader].
+func Open(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		//nolint:wrapcheck // error includes the path.
+		return nil, err
+	}
+	return NewReader(f)
+}
+
+// NewReader returns a reader that decompresses f based on its extension.
+// Files with a .dz extension are read as dictzip files and files with a .gz
+// extension are read as gzip files. Otherwise, f is returned. The reader
+// assumes ownership of f and f is closed if an error occurs.
+func NewReader(f *os.File) (io.ReadCloser, error) {
+	var z io.ReadCloser
+	var err error
+	switch strings.ToLower(filepath.Ext(f.Name())) {
+	case ".dz":
+		z, err = dictzip.NewReader(f)
+	case ".gz":
+		z, err = gzip.NewReader(f)
+	default:
+		return f, nil
+	}
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("reading %q: %w", f.Name(), err)
+	}
+	return &ReadCloser{
+		r: z,
+		f: f,
+	}, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gzipfile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ianlewis/go-dictzip"
+)
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("hoge fuga piyo")
+
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	if _, err := gw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var dz bytes.Buffer
+	dw, err := dictzip.NewWriter(&dz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = dw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err = dw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		file string
+		data []byte
+		err  bool
+	}{
+		{
+			name: "uncompressed",
+			file: "dictionary.idx",
+			data: data,
+		},
+		{
+			name: "gzip",
+			file: "dictionary.idx.gz",
+			data: gz.Bytes(),
+		},
+		{
+			name: "dictzip",
+			file: "dictionary.dsl.DZ",
+			data: dz.Bytes(),
+		},
+		{
+			name: "invalid gzip",
+			file: "dictionary.syn.gz",
+			data: data,
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), test.file)
+			if err := os.WriteFile(path, test.data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			r, err := Open(path)
+			if got, want := err != nil, test.err; got != want {
+				t.Fatalf("Open: unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("ReadAll: %v", err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("ReadAll: expected %q, got %q", data, got)
+			}
+			if err := r.Close(); err != nil {
+				t.Errorf("Close: %v", err)
+			}
+		})
+	}
+}
+
+func TestIsCompressed(t *testing.T) {
+	t.Parallel()
+
+	for name, expected := range map[string]bool{
+		"dictionary.idx":     false,
+		"dictionary.idx.gz":  true,
+		"dictionary.dict.dz": true,
+		"dictionary.SYN.GZ":  true,
+		"dictionary.gz.idx":  false,
+	} {
+		if got := IsCompressed(name); got != expected {
+			t.Errorf("IsCompressed(%q): expected %v, got %v", name, expected, got)
+		}
+	}
+}
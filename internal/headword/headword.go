@@ -0,0 +1,28 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package headword validates the words written to .idx and .syn files.
+package headword
+
+import "strings"
+
+// MaxLength is the maximum length of a word in bytes.
+const MaxLength = 255
+
+// Valid returns true if word can be written to an .idx or .syn file. Words
+// must be non-empty, must not contain a null byte, and must be no longer than
+// MaxLength bytes.
+func Valid(word string) bool {
+	return word != "" && len(word) <= MaxLength && strings.IndexByte(word, 0) < 0
+}
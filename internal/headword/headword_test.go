@@ -0,0 +1,37 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package headword
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	t.Parallel()
+
+	for word, expected := range map[string]bool{
+		"hoge":                           true,
+		"日本語":                            true,
+		strings.Repeat("a", MaxLength):   true,
+		"":                               false,
+		"ho\x00ge":                       false,
+		strings.Repeat("a", MaxLength+1): false,
+	} {
+		if got := Valid(word); got != expected {
+			t.Errorf("Valid(%q): expected %v, got %v", word, expected, got)
+		}
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"slices"
 	"sort"
+	"strings"
 )
 
 // Index is a generic sorted array index.
@@ -28,15 +29,17 @@ type Index[V fmt.Stringer] struct {
 	cmp func(string, string) int
 }
 
-// NewIndex creates an index from the given slice and comparison function.
-// cmp(a, b) should return a negative number when a < b, a positive number when
-// a > b and zero when a == b or a and b are incomparable in the sense of a
-// strict weak ordering.
+// NewIndex creates an index from the given slice and comparison function. The
+// values are sorted by their string value in byte order. cmp(q, v) is used to
+// search the index and compares a query q with the string value v of a value.
+// It should return a negative number when q sorts before v, a positive number
+// when q sorts after v and zero when v matches q. The values matching q must
+// be contiguous in byte order.
 func NewIndex[V fmt.Stringer](index []V, cmp func(string, string) int) *Index[V] {
 	sorted := make([]V, len(index))
 	copy(sorted, index)
-	slices.SortFunc(sorted, func(a, b V) int {
-		return cmp(a.String(), b.String())
+	slices.SortStableFunc(sorted, func(a, b V) int {
+		return strings.Compare(a.String(), b.String())
 	})
 
 	return &Index[V]{
@@ -45,6 +48,12 @@ func NewIndex[V fmt.Stringer](index []V, cmp func(string, string) int) *Index[V]
 	}
 }
 
+// All returns all values in the index in sorted order. The returned slice
+// must not be modified.
+func (idx *Index[V]) All() []V {
+	return idx.index
+}
+
 // Search performs a binary search over the index and returns matching words.
 func (idx *Index[V]) Search(query string) []V {
 	i, found := sort.Find(len(idx.index), func(i int) int {
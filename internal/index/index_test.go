@@ -68,3 +68,32 @@ func TestIndex_string(t *testing.T) {
 		})
 	}
 }
+
+func TestIndex_prefix(t *testing.T) {
+	t.Parallel()
+
+	prefixCmp := func(q, v string) int {
+		if strings.HasPrefix(v, q) {
+			return 0
+		}
+		return strings.Compare(q, v)
+	}
+	index := NewIndex([]String{"b", "ab", "abc", "a", "aa", "c"}, prefixCmp)
+
+	if diff := cmp.Diff([]String{"ab", "abc"}, index.Search("ab")); diff != "" {
+		t.Fatalf("Search (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]String{"a", "aa", "ab", "abc"}, index.Search("a")); diff != "" {
+		t.Fatalf("Search (-want, +got):\n%s", diff)
+	}
+}
+
+func TestIndex_All(t *testing.T) {
+	t.Parallel()
+
+	index := NewIndex([]String{"foo", "bar", "baz"}, strings.Compare)
+
+	if diff := cmp.Diff([]String{"bar", "baz", "foo"}, index.All()); diff != "" {
+		t.Fatalf("All (-want, +got):\n%s", diff)
+	}
+}
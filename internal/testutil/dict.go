@@ -127,18 +127,21 @@ func MakeDict(t *testing.T, words []*dict.Word, sameTypeSequence []dict.DataType
 					// Data is a string like sequence.
 					b = append(b, d.Data...)
 					// Null terminator is not present on the last data item.
-					if i == len(w.Data)-1 {
+					if i < len(w.Data)-1 {
 						b = append(b, 0)
 					}
 				} else {
-					// Data is a file like sequence.
-					sizeBytes := make([]byte, 4)
-					dataLen := len(d.Data)
-					if dataLen > math.MaxUint32 {
-						t.Fatalf("word data too long: %d", dataLen)
+					// Data is a file like sequence. The size is not present
+					// on the last data item.
+					if i < len(w.Data)-1 {
+						sizeBytes := make([]byte, 4)
+						dataLen := len(d.Data)
+						if dataLen > math.MaxUint32 {
+							t.Fatalf("word data too long: %d", dataLen)
+						}
+						binary.BigEndian.PutUint32(sizeBytes, uint32(dataLen))
+						b = append(b, sizeBytes...)
 					}
-					binary.BigEndian.PutUint32(sizeBytes, uint32(dataLen))
-					b = append(b, sizeBytes...)
 					b = append(b, d.Data...)
 				}
 			}
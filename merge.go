@@ -0,0 +1,218 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stardict
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/idx"
+	"github.com/ianlewis/go-stardict/ifo"
+)
+
+// ErrUnsupportedPolicy indicates that the merge policy is not supported.
+var ErrUnsupportedPolicy = errors.New("unsupported merge policy")
+
+// MergePolicy is a policy for combining entries with the same headword.
+type MergePolicy string
+
+const (
+	// MergeConcat combines the data of every entry with the same headword
+	// into one entry.
+	MergeConcat MergePolicy = "concat"
+
+	// MergeFirst keeps the first entry with the same headword.
+	MergeFirst MergePolicy = "first"
+
+	// MergeLast keeps the last entry with the same headword.
+	MergeLast MergePolicy = "last"
+)
+
+// MergeOptions are options for Merge.
+type MergeOptions struct {
+	// Policy is the policy for combining entries with the same headword. The
+	// default is MergeConcat.
+	Policy MergePolicy
+}
+
+// mergeEntry is an entry in the merged dictionary.
+type mergeEntry struct {
+	words    []mergeWord
+	synonyms []string
+}
+
+// mergeWord is an index word and the dict it is read from.
+type mergeWord struct {
+	dict *dict.Dict
+	word *idx.Word
+}
+
+// Merge adds the entries of the dictionaries to the Builder and returns the
+// number of entries added. Entries with the same headword are combined
+// according to the policy. Entries are ordered by the dictionaries they are
+// read from followed by their .idx file order. The synonyms of combined
+// entries are unioned.
+//
+// Merge holds the index words and synonyms of every dictionary in memory so
+// its memory use grows with the size of the dictionaries' indexes. Entry data
+// is read as entries are added and isn't held in memory.
+func Merge(dicts []*Stardict, b *Builder, options *MergeOptions) (int, error) {
+	if options == nil {
+		options = &MergeOptions{}
+	}
+	policy := options.Policy
+	if policy == "" {
+		policy = MergeConcat
+	}
+	if !slices.Contains([]MergePolicy{MergeConcat, MergeFirst, MergeLast}, policy) {
+		return 0, fmt.Errorf("%w: %q", ErrUnsupportedPolicy, policy)
+	}
+
+	// Read the index of every dictionary. Only index words are kept in
+	// memory and the data is read when entries are added.
+	entries := map[string]*mergeEntry{}
+	var order []string
+	for _, s := range dicts {
+		if err := s.mergeInto(entries, &order); err != nil {
+			return 0, fmt.Errorf("reading %q: %w", s.Bookname(), err)
+		}
+	}
+
+	n := 0
+	for _, word := range order {
+		e := entries[word]
+
+		words := e.words
+		switch policy {
+		case MergeFirst:
+			words = words[:1]
+		case MergeLast:
+			words = words[len(words)-1:]
+		case MergeConcat:
+		}
+
+		var data []*dict.Data
+		for _, w := range words {
+			dictWord, err := w.dict.Word(w.word)
+			if err != nil {
+				return n, fmt.Errorf("reading word %q: %w", word, err)
+			}
+			data = append(data, dictWord.Data...)
+		}
+
+		if err := b.AddEntry(word, e.synonyms, data...); err != nil {
+			return n, fmt.Errorf("adding %q: %w", word, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// mergeInto adds the dictionary's index words and synonyms to entries and
+// appends new headwords to order.
+func (s *Stardict) mergeInto(entries map[string]*mergeEntry, order *[]string) error {
+	synonyms, err := s.synonyms()
+	if err != nil {
+		return err
+	}
+
+	d, err := s.Dict()
+	if err != nil {
+		return err
+	}
+
+	sc, err := s.IndexScanner()
+	if err != nil {
+		return err
+	}
+	defer sc.Close()
+
+	var n uint32
+	for sc.Scan() {
+		w := sc.Word()
+		e, ok := entries[w.Word]
+		if !ok {
+			e = &mergeEntry{}
+			entries[w.Word] = e
+			*order = append(*order, w.Word)
+		}
+		e.words = append(e.words, mergeWord{
+			dict: d,
+			word: w,
+		})
+		for _, synonym := range synonyms[n] {
+			if synonym != w.Word && !slices.Contains(e.synonyms, synonym) {
+				e.synonyms = append(e.synonyms, synonym)
+			}
+		}
+		n++
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("scanning index: %w", err)
+	}
+	return nil
+}
+
+// MergeInfo returns the metadata for a dictionary merged from dicts. The
+// book names, authors, emails, and websites are joined with commas and the
+// descriptions are joined with line breaks. Languages are kept only if all
+// dictionaries have the same language. The sametypesequence is not set
+// since merged entries may have different data types.
+func MergeInfo(dicts []*Stardict) (*ifo.Info, error) {
+	infos := make([]*ifo.Info, 0, len(dicts))
+	for _, s := range dicts {
+		info, err := s.Info()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+
+	join := func(sep string, field func(*ifo.Info) string) string {
+		var values []string
+		for _, info := range infos {
+			if v := field(info); v != "" && !slices.Contains(values, v) {
+				values = append(values, v)
+			}
+		}
+		return strings.Join(values, sep)
+	}
+	same := func(field func(*ifo.Info) string) string {
+		if len(infos) == 0 {
+			return ""
+		}
+		v := field(infos[0])
+		for _, info := range infos[1:] {
+			if field(info) != v {
+				return ""
+			}
+		}
+		return v
+	}
+
+	return &ifo.Info{
+		BookName:    join(", ", func(i *ifo.Info) string { return i.BookName }),
+		Author:      join(", ", func(i *ifo.Info) string { return i.Author }),
+		Email:       join(", ", func(i *ifo.Info) string { return i.Email }),
+		Website:     join(", ", func(i *ifo.Info) string { return i.Website }),
+		Description: join("<br>", func(i *ifo.Info) string { return i.Description }),
+		Lang:        same(func(i *ifo.Info) string { return i.Lang }),
+		From:        same(func(i *ifo.Info) string { return i.From }),
+		To:          same(func(i *ifo.Info) string { return i.To }),
+	}, nil
+}
@@ -0,0 +1,205 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stardict
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/ifo"
+)
+
+// mergeTestEntry is an entry in a dictionary for merge tests.
+type mergeTestEntry struct {
+	word     string
+	synonyms []string
+	data     string
+}
+
+// buildDict builds and opens a dictionary with text entries.
+func buildDict(t *testing.T, info *ifo.Info, entries []mergeTestEntry) *Stardict {
+	t.Helper()
+
+	b, err := NewBuilder(info, nil)
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+	for _, e := range entries {
+		if err := b.AddEntry(e.word, e.synonyms, &dict.Data{
+			Type: dict.UTFTextType,
+			Data: []byte(e.data),
+		}); err != nil {
+			t.Fatalf("AddEntry: %v", err)
+		}
+	}
+	dir := t.TempDir()
+	if err := b.Finish(dir, "dict"); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+
+	s, err := Open(filepath.Join(dir, "dict.ifo"), nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	text := func(s string) *dict.Data {
+		return &dict.Data{Type: dict.UTFTextType, Data: []byte(s)}
+	}
+
+	// Expected entries are in .idx file order.
+	tests := []struct {
+		policy   MergePolicy
+		expected []*Entry
+	}{
+		{
+			policy: MergeConcat,
+			expected: []*Entry{
+				{word: "bar", data: DataList{text("bar 2")}},
+				{word: "fuga", data: DataList{text("fuga 1")}},
+				{word: "hoge", synonyms: []string{"foo", "piyo"}, data: DataList{text("hoge 1"), text("hoge 2")}},
+			},
+		},
+		{
+			policy: MergeFirst,
+			expected: []*Entry{
+				{word: "bar", data: DataList{text("bar 2")}},
+				{word: "fuga", data: DataList{text("fuga 1")}},
+				{word: "hoge", synonyms: []string{"foo", "piyo"}, data: DataList{text("hoge 1")}},
+			},
+		},
+		{
+			policy: MergeLast,
+			expected: []*Entry{
+				{word: "bar", data: DataList{text("bar 2")}},
+				{word: "fuga", data: DataList{text("fuga 1")}},
+				{word: "hoge", synonyms: []string{"foo", "piyo"}, data: DataList{text("hoge 2")}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.policy), func(t *testing.T) {
+			t.Parallel()
+
+			dicts := []*Stardict{
+				buildDict(t, &ifo.Info{BookName: "one"}, []mergeTestEntry{
+					{word: "hoge", synonyms: []string{"piyo"}, data: "hoge 1"},
+					{word: "fuga", data: "fuga 1"},
+				}),
+				buildDict(t, &ifo.Info{BookName: "two"}, []mergeTestEntry{
+					{word: "hoge", synonyms: []string{"foo", "piyo"}, data: "hoge 2"},
+					{word: "bar", data: "bar 2"},
+				}),
+			}
+
+			b, err := NewBuilder(&ifo.Info{BookName: "merged"}, nil)
+			if err != nil {
+				t.Fatalf("NewBuilder: %v", err)
+			}
+			n, err := Merge(dicts, b, &MergeOptions{
+				Policy: test.policy,
+			})
+			if err != nil {
+				t.Fatalf("Merge: %v", err)
+			}
+			if got, want := n, 3; got != want {
+				t.Errorf("Merge: expected %d entries, got %d", want, got)
+			}
+			dir := t.TempDir()
+			if err := b.Finish(dir, "merged"); err != nil {
+				t.Fatalf("Finish: %v", err)
+			}
+
+			s, err := Open(filepath.Join(dir, "merged.ifo"), nil)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			defer s.Close()
+
+			var entries []*Entry
+			if err := s.Walk(func(e *Entry) error {
+				entries = append(entries, e)
+				return nil
+			}); err != nil {
+				t.Fatalf("Walk: %v", err)
+			}
+			if diff := cmp.Diff(test.expected, entries, cmp.AllowUnexported(Entry{})); diff != "" {
+				t.Errorf("Walk (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMerge_policy(t *testing.T) {
+	t.Parallel()
+
+	b, err := NewBuilder(&ifo.Info{BookName: "merged"}, nil)
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+	defer b.Close()
+
+	if _, err := Merge(nil, b, &MergeOptions{
+		Policy: "hoge",
+	}); !errors.Is(err, ErrUnsupportedPolicy) {
+		t.Errorf("Merge: expected %v, got %v", ErrUnsupportedPolicy, err)
+	}
+}
+
+func TestMergeInfo(t *testing.T) {
+	t.Parallel()
+
+	dicts := []*Stardict{
+		buildDict(t, &ifo.Info{
+			BookName:    "one",
+			Author:      "Ian Lewis",
+			Description: "The first dictionary.",
+			Lang:        "en-ja",
+		}, []mergeTestEntry{{word: "hoge", data: "hoge"}}),
+		buildDict(t, &ifo.Info{
+			BookName:    "two",
+			Author:      "Ian Lewis",
+			Website:     "https://example.com",
+			Description: "The second dictionary.",
+			Lang:        "en-ja",
+			From:        "en",
+		}, []mergeTestEntry{{word: "hoge", data: "hoge"}}),
+	}
+
+	info, err := MergeInfo(dicts)
+	if err != nil {
+		t.Fatalf("MergeInfo: %v", err)
+	}
+	expected := &ifo.Info{
+		BookName:    "one, two",
+		Author:      "Ian Lewis",
+		Website:     "https://example.com",
+		Description: "The first dictionary.<br>The second dictionary.",
+		Lang:        "en-ja",
+	}
+	if diff := cmp.Diff(expected, info); diff != "" {
+		t.Errorf("MergeInfo (-want, +got):\n%s", diff)
+	}
+}
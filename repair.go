@@ -0,0 +1,302 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stardict
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/ianlewis/go-stardict/collation"
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/idx"
+	"github.com/ianlewis/go-stardict/ifo"
+	"github.com/ianlewis/go-stardict/internal/headword"
+	"github.com/ianlewis/go-stardict/syn"
+)
+
+var errRepairTree = errors.New("tree dictionaries can't be repaired")
+
+// Change is a change made when repairing a dictionary.
+type Change struct {
+	// File is the extension of the dictionary file that was changed, such as
+	// ".ifo" or ".idx".
+	File string
+
+	// Message describes the change.
+	Message string
+}
+
+// String returns a string representation of the change.
+func (c *Change) String() string {
+	return fmt.Sprintf("%s: %s", c.File, c.Message)
+}
+
+// RepairReport is the result of repairing a dictionary.
+type RepairReport struct {
+	// Changes are the changes made to the dictionary. Dropped entries and
+	// synonyms are listed in file order followed by changes to the .ifo
+	// file.
+	Changes []*Change
+
+	// Entries is the number of entries in the repaired dictionary.
+	Entries int
+
+	// Synonyms is the number of synonyms in the repaired dictionary.
+	Synonyms int
+}
+
+// add adds a change to the report.
+func (r *RepairReport) add(file, format string, args ...any) {
+	r.Changes = append(r.Changes, &Change{
+		File:    file,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Repair writes a repaired copy of the dictionary to dir using basename for
+// the file names and returns a report of the changes made. Entries whose
+// data is outside the .dict file or can't be decoded are dropped along with
+// their synonyms, as are synonyms that refer to missing entries and
+// truncated records at the end of the .idx and .syn files. The .idx file is
+// written in StarDict order, the .syn file is remapped to the new entry
+// positions, and the .ifo file is regenerated with consistent counts and
+// sizes. The counts and sizes in the original .ifo file are not used so
+// invalid values don't prevent the dictionary from being repaired. Tree
+// dictionaries are not supported.
+func Repair(s *Stardict, dir, basename string, options *BuilderOptions) (*RepairReport, error) {
+	if s.isTree {
+		return nil, errRepairTree
+	}
+
+	info := s.repairInfo()
+
+	d, err := s.Dict()
+	if err != nil {
+		return nil, err
+	}
+	dictSize, err := s.dictSize()
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := NewBuilder(info, options)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	r := &RepairReport{}
+	synonyms, err := s.repairSyn(r)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.repairIdx(r, b, d, dictSize, synonyms); err != nil {
+		return nil, err
+	}
+
+	if err = b.Finish(dir, basename); err != nil {
+		return nil, err
+	}
+
+	if err = s.repairIfo(r, filepath.Join(dir, basename+".ifo")); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// repairIdx adds the entries in the .idx file whose data can be read to the
+// Builder along with their synonyms.
+func (s *Stardict) repairIdx(
+	r *RepairReport,
+	b *Builder,
+	d *dict.Dict,
+	dictSize int64,
+	synonyms map[uint32][]string,
+) error {
+	sc, err := s.IndexScanner()
+	if err != nil {
+		return err
+	}
+	defer sc.Close()
+
+	dictLen := uint64(dictSize) //nolint:gosec // dictSize is not negative.
+	sorted := true
+	var i uint32
+	var prev string
+	for sc.Scan() {
+		w := sc.Word()
+		if i > 0 && collation.StardictCompare(prev, w.Word) > 0 {
+			sorted = false
+		}
+		prev = w.Word
+
+		n := i
+		i++
+		words := synonyms[n]
+		delete(synonyms, n)
+
+		if !headword.Valid(w.Word) {
+			r.add(".idx", "dropped entry %d %q: invalid headword", n, w.Word)
+			r.dropSynonyms(words, n)
+			continue
+		}
+		if w.Offset > dictLen || uint64(w.Size) > dictLen-w.Offset {
+			r.add(".idx", "dropped entry %d %q: data at offset %d with size %d is outside the .dict file of size %d",
+				n, w.Word, w.Offset, w.Size, dictSize)
+			r.dropSynonyms(words, n)
+			continue
+		}
+		dictWord, wordErr := d.Word(w)
+		if errors.Is(wordErr, dict.ErrCorruptEntry) {
+			r.add(".idx", "dropped entry %d: %v", n, wordErr)
+			r.dropSynonyms(words, n)
+			continue
+		}
+		if wordErr != nil {
+			return fmt.Errorf("reading word %q: %w", w.Word, wordErr)
+		}
+
+		var valid []string
+		for _, synonym := range words {
+			if headword.Valid(synonym) {
+				valid = append(valid, synonym)
+			} else {
+				r.add(".syn", "dropped synonym %q of entry %d: invalid synonym", synonym, n)
+			}
+		}
+		if err = b.AddEntry(w.Word, valid, dictWord.Data...); err != nil {
+			return fmt.Errorf("adding %q: %w", w.Word, err)
+		}
+		r.Entries++
+		r.Synonyms += len(valid)
+	}
+	err = sc.Err()
+	if errors.Is(err, idx.ErrTruncated) {
+		r.add(".idx", "dropped truncated record at the end of the file: %v", err)
+	} else if err != nil {
+		return fmt.Errorf("scanning index: %w", err)
+	}
+
+	if !sorted {
+		r.add(".idx", "sorted entries in StarDict order")
+	}
+
+	// Any remaining synonyms refer to entries that don't exist.
+	for _, n := range slices.Sorted(maps.Keys(synonyms)) {
+		for _, synonym := range synonyms[n] {
+			r.add(".syn", "dropped synonym %q: entry %d does not exist", synonym, n)
+		}
+	}
+
+	return nil
+}
+
+// repairSyn reads the synonyms in the .syn file indexed by the original word
+// index. A truncated record at the end of the file is dropped.
+func (s *Stardict) repairSyn(r *RepairReport) (map[uint32][]string, error) {
+	synonyms := map[uint32][]string{}
+
+	sc, err := syn.NewScannerFromIfoPath(s.ifoPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return synonyms, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating synonym scanner: %w", err)
+	}
+	defer sc.Close()
+
+	for sc.Scan() {
+		w := sc.Word()
+		synonyms[w.OriginalWordIndex] = append(synonyms[w.OriginalWordIndex], w.Word)
+	}
+	err = sc.Err()
+	if errors.Is(err, syn.ErrTruncated) {
+		r.add(".syn", "dropped truncated record at the end of the file: %v", err)
+	} else if err != nil {
+		return nil, fmt.Errorf("scanning synonyms: %w", err)
+	}
+	return synonyms, nil
+}
+
+// dropSynonyms adds changes for the synonyms of the dropped entry n.
+func (r *RepairReport) dropSynonyms(synonyms []string, n uint32) {
+	for _, synonym := range synonyms {
+		r.add(".syn", "dropped synonym %q of dropped entry %d", synonym, n)
+	}
+}
+
+// repairIfo adds changes for the .ifo values that differ between the
+// original metadata and the repaired .ifo file.
+func (s *Stardict) repairIfo(r *RepairReport, ifoPath string) error {
+	f, err := os.Open(ifoPath)
+	if err != nil {
+		return fmt.Errorf("opening %q: %w", ifoPath, err)
+	}
+	defer f.Close()
+
+	i, err := ifo.New(f)
+	if err != nil {
+		return fmt.Errorf("reading %q: %w", ifoPath, err)
+	}
+	// The original values are compared as strings as they may be invalid.
+	// Missing values are treated as zero.
+	value := func(m *ifo.Ifo, key string) string {
+		if v := m.Value(key); v != "" {
+			return v
+		}
+		return "0"
+	}
+	for _, key := range []string{"wordcount", "idxfilesize", "synwordcount", "idxoffsetbits"} {
+		orig, v := value(s.ifo, key), value(i, key)
+		if orig != v {
+			r.add(".ifo", "changed %s from %s to %s", key, orig, v)
+		}
+	}
+	if orig, v := s.ifo.Value("version"), i.Value("version"); orig != v {
+		r.add(".ifo", "changed version from %q to %q", orig, v)
+	}
+
+	return nil
+}
+
+// repairInfo returns the metadata for the repaired dictionary. The counts and
+// sizes are set by the Builder so they aren't read from the .ifo file.
+func (s *Stardict) repairInfo() *ifo.Info {
+	info := &ifo.Info{
+		Magic:            s.ifo.Magic(),
+		Version:          s.version,
+		BookName:         s.bookname,
+		Author:           s.author,
+		Email:            s.email,
+		Website:          s.website,
+		Description:      s.description,
+		Date:             s.ifo.Value("date"),
+		SameTypeSequence: s.ifo.Value("sametypesequence"),
+		DictType:         s.ifo.Value("dicttype"),
+		Lang:             s.lang,
+		From:             s.ifo.Value("from"),
+		To:               s.ifo.Value("to"),
+	}
+	// idxoffsetbits is only used for version 3.0.0 dictionaries.
+	if s.version == "3.0.0" && s.ifo.Value("idxoffsetbits") != "" {
+		info.IdxOffsetBits = s.idxoffsetbits
+	}
+	return info
+}
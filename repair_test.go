@@ -0,0 +1,203 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stardict
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/idx"
+	"github.com/ianlewis/go-stardict/syn"
+)
+
+// repairDict returns a dictionary with an unsorted .idx, wrong .ifo counts,
+// an entry outside the .dict file, and a synonym for a missing entry.
+func repairDict() *testDict {
+	return &testDict{
+		ifo: `StarDict's dict ifo file
+version=2.4.2
+bookname=hoge
+wordcount=5
+synwordcount=3
+idxfilesize=10`,
+		dict: []*dict.Word{
+			{Data: []*dict.Data{{Type: dict.UTFTextType, Data: []byte("fuga")}}},
+			{Data: []*dict.Data{{Type: dict.UTFTextType, Data: []byte("hoge")}}},
+		},
+		idx: []*idx.Word{
+			{Word: "hoge", Offset: 6, Size: 6},
+			{Word: "fuga", Offset: 0, Size: 6},
+			{Word: "piyo", Offset: 12, Size: 6},
+		},
+		syn: []*syn.Word{
+			{Word: "hogehoge", OriginalWordIndex: 0},
+			{Word: "piyopiyo", OriginalWordIndex: 2},
+			{Word: "pico", OriginalWordIndex: 5},
+		},
+	}
+}
+
+func TestRepair(t *testing.T) {
+	t.Parallel()
+
+	dir := writeDict(t, repairDict())
+	defer os.RemoveAll(dir)
+
+	s, err := Open(filepath.Join(dir, "dictionary.ifo"), nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+
+	outDir := t.TempDir()
+	r, err := Repair(s, outDir, "repaired", nil)
+	if err != nil {
+		t.Fatalf("Repair: %v", err)
+	}
+
+	expected := []*Change{
+		{".idx", `dropped entry 2 "piyo": data at offset 12 with size 6 is outside the .dict file of size 12`},
+		{".syn", `dropped synonym "piyopiyo" of dropped entry 2`},
+		{".idx", "sorted entries in StarDict order"},
+		{".syn", `dropped synonym "pico": entry 5 does not exist`},
+		{".ifo", "changed wordcount from 5 to 2"},
+		{".ifo", "changed idxfilesize from 10 to 26"},
+		{".ifo", "changed synwordcount from 3 to 1"},
+	}
+	if diff := cmp.Diff(expected, r.Changes); diff != "" {
+		t.Errorf("Repair (-want, +got):\n%s", diff)
+	}
+	if got, want := r.Entries, 2; got != want {
+		t.Errorf("Entries: expected %d, got %d", want, got)
+	}
+	if got, want := r.Synonyms, 1; got != want {
+		t.Errorf("Synonyms: expected %d, got %d", want, got)
+	}
+
+	repaired, err := Open(filepath.Join(outDir, "repaired.ifo"), nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer repaired.Close()
+
+	if findings := repaired.Validate().Findings; len(findings) != 0 {
+		t.Errorf("Validate: expected no findings, got %v", findings)
+	}
+
+	var entries []*Entry
+	if err := repaired.Walk(func(e *Entry) error {
+		entries = append(entries, e)
+		return nil
+	}); err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+	expectedEntries := []*Entry{
+		{
+			word: "fuga",
+			data: DataList{{Type: dict.UTFTextType, Data: []byte("fuga")}},
+		},
+		{
+			word:     "hoge",
+			synonyms: []string{"hogehoge"},
+			data:     DataList{{Type: dict.UTFTextType, Data: []byte("hoge")}},
+		},
+	}
+	if diff := cmp.Diff(expectedEntries, entries, cmp.AllowUnexported(Entry{})); diff != "" {
+		t.Errorf("Walk (-want, +got):\n%s", diff)
+	}
+}
+
+// TestRepair_invalidCounts tests that the counts and sizes in the .ifo file
+// are regenerated even if they are invalid.
+func TestRepair_invalidCounts(t *testing.T) {
+	t.Parallel()
+
+	dir := writeDict(t, repairDict())
+	defer os.RemoveAll(dir)
+
+	s, err := Open(filepath.Join(dir, "dictionary.ifo"), nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+	s.ifo.Set("wordcount", "abc")
+	s.ifo.Set("idxfilesize", "-1")
+	s.ifo.Delete("synwordcount")
+
+	r, err := Repair(s, t.TempDir(), "repaired", nil)
+	if err != nil {
+		t.Fatalf("Repair: %v", err)
+	}
+
+	var changes []*Change
+	for _, c := range r.Changes {
+		if c.File == ".ifo" {
+			changes = append(changes, c)
+		}
+	}
+	expected := []*Change{
+		{".ifo", "changed wordcount from abc to 2"},
+		{".ifo", "changed idxfilesize from -1 to 26"},
+		{".ifo", "changed synwordcount from 0 to 1"},
+	}
+	if diff := cmp.Diff(expected, changes); diff != "" {
+		t.Errorf("Repair (-want, +got):\n%s", diff)
+	}
+}
+
+func TestRepair_truncated(t *testing.T) {
+	t.Parallel()
+
+	d := repairDict()
+	d.idx = d.idx[:2]
+	d.syn = d.syn[:1]
+	dir := writeDict(t, d)
+	defer os.RemoveAll(dir)
+
+	// Truncate the last record of the .idx file.
+	idxPath := filepath.Join(dir, "dictionary.idx")
+	fi, err := os.Stat(idxPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(idxPath, fi.Size()-2); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Open(filepath.Join(dir, "dictionary.ifo"), nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+
+	r, err := Repair(s, t.TempDir(), "repaired", nil)
+	if err != nil {
+		t.Fatalf("Repair: %v", err)
+	}
+
+	expected := []*Change{
+		{".idx", "dropped truncated record at the end of the file: truncated index entry: 11 trailing bytes"},
+		{".ifo", "changed wordcount from 5 to 1"},
+		{".ifo", "changed idxfilesize from 10 to 13"},
+		{".ifo", "changed synwordcount from 3 to 1"},
+	}
+	if diff := cmp.Diff(expected, r.Changes); diff != "" {
+		t.Errorf("Repair (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,389 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package res
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ianlewis/go-dictzip"
+
+	"github.com/ianlewis/go-stardict/idx"
+	"github.com/ianlewis/go-stardict/ifo"
+)
+
+const rifoMagic = "StarDict's storage ifo file"
+
+var (
+	errInvalidMagic   = errors.New("invalid magic data")
+	errOffsetTooLarge = errors.New("file offset too large")
+	errIsDir          = errors.New("is a directory")
+)
+
+// Database is a resource database made up of the res.rifo, res.ridx, and
+// res.rdic files. Database implements [fs.FS] and [fs.ReadFileFS]. File names
+// use forward slashes as separators. The res.ridx file only lists files so
+// directories are synthesized from the file paths and can be read with
+// [fs.ReadDir] and [fs.WalkDir].
+type Database struct {
+	r     *rdicReader
+	files map[string]*idx.Word
+
+	// dirs maps directory names to the sorted names of the entries in the
+	// directory.
+	dirs map[string][]string
+}
+
+// rdicReader is a reader that reads either from a dictzipped file if
+// compressed or directly from the file of not compressed.
+type rdicReader struct {
+	f  *os.File
+	dz *dictzip.Reader
+}
+
+// ReadAt implements io.ReaderAt.ReadAt.
+func (r *rdicReader) ReadAt(p []byte, off int64) (int, error) {
+	if r.dz != nil {
+		//nolint:wrapcheck // error wrapping is unnecessary.
+		return r.dz.ReadAt(p, off)
+	}
+	//nolint:wrapcheck // error wrapping is unnecessary.
+	return r.f.ReadAt(p, off)
+}
+
+// OpenDatabase opens the resource database given the path to the res.rifo
+// file. The res.ridx and res.rdic files are expected in the same directory.
+func OpenDatabase(rifoPath string) (*Database, error) {
+	rifoFile, err := os.Open(rifoPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening %q: %w", rifoPath, err)
+	}
+	defer rifoFile.Close()
+
+	rifo, err := ifo.New(rifoFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", rifoPath, err)
+	}
+	if rifo.Magic() != rifoMagic {
+		return nil, fmt.Errorf("%w: %q", errInvalidMagic, rifoPath)
+	}
+
+	offsetBits := 32
+	if v := rifo.Value("ridxoffsetbits"); v != "" {
+		offsetBits, err = strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", idx.ErrInvalidIdxOffset, err)
+		}
+	}
+
+	baseName := strings.TrimSuffix(rifoPath, filepath.Ext(rifoPath))
+
+	ridxFile, err := os.Open(baseName + ".ridx")
+	if err != nil {
+		return nil, fmt.Errorf("opening .ridx file: %w", err)
+	}
+	s, err := idx.NewScanner(ridxFile, &idx.ScannerOptions{
+		OffsetBits: offsetBits,
+	})
+	if err != nil {
+		_ = ridxFile.Close()
+		return nil, fmt.Errorf("creating resource index scanner: %w", err)
+	}
+	defer s.Close()
+
+	db := &Database{
+		files: map[string]*idx.Word{},
+	}
+	for s.Scan() {
+		w := s.Word()
+		db.files[w.Word] = w
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("scanning resource index: %w", err)
+	}
+	db.dirs = makeDirs(db.files)
+
+	db.r, err = openRdic(baseName)
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// openRdic opens the res.rdic file.
+func openRdic(baseName string) (*rdicReader, error) {
+	rdicExts := []string{
+		".rdic",
+		".rdic.dz",
+	}
+	var f *os.File
+	var err error
+	for _, ext := range rdicExts {
+		f, err = os.Open(baseName + ext)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("opening .rdic file: %w", err)
+		}
+	}
+
+	// Catch the case when no .rdic file was found.
+	if err != nil {
+		return nil, fmt.Errorf("opening .rdic file: %w", err)
+	}
+
+	r := &rdicReader{
+		f: f,
+	}
+	if filepath.Ext(f.Name()) == ".dz" {
+		r.dz, err = dictzip.NewReader(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("opening dictzip: %w", err)
+		}
+	}
+
+	return r, nil
+}
+
+// makeDirs returns the directories containing the files mapped to the sorted
+// names of their entries. Files with names that aren't valid paths can't be
+// opened and are not included.
+func makeDirs(files map[string]*idx.Word) map[string][]string {
+	entries := map[string]map[string]bool{
+		".": {},
+	}
+	for name := range files {
+		if !fs.ValidPath(name) || name == "." {
+			continue
+		}
+		for name != "." {
+			dir := path.Dir(name)
+			if entries[dir] == nil {
+				entries[dir] = map[string]bool{}
+			}
+			entries[dir][path.Base(name)] = true
+			name = dir
+		}
+	}
+
+	dirs := map[string][]string{}
+	for dir, names := range entries {
+		dirs[dir] = slices.Sorted(maps.Keys(names))
+	}
+	return dirs
+}
+
+// Open implements [fs.FS.Open].
+func (db *Database) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	w, ok := db.files[name]
+	if !ok {
+		if _, ok := db.dirs[name]; ok {
+			return &dir{
+				db:   db,
+				name: name,
+			}, nil
+		}
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	// NOTE: File offsets math.MaxInt64 < x < math.MaxUint64 not supported.
+	if w.Offset > math.MaxInt64 {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: errOffsetTooLarge}
+	}
+
+	return &file{
+		name: name,
+		size: int64(w.Size),
+		r:    io.NewSectionReader(db.r, int64(w.Offset), int64(w.Size)),
+	}, nil
+}
+
+// ReadFile implements [fs.ReadFileFS.ReadFile].
+func (db *Database) ReadFile(name string) ([]byte, error) {
+	f, err := db.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	//nolint:wrapcheck // error wrapping is unnecessary.
+	return io.ReadAll(f)
+}
+
+// Close closes the underlying res.rdic file and the dictzip reader if the
+// file is compressed.
+func (db *Database) Close() error {
+	if db.r.dz != nil {
+		if err := db.r.dz.Close(); err != nil {
+			_ = db.r.f.Close()
+			return fmt.Errorf("closing dictzip reader: %w", err)
+		}
+	}
+	if err := db.r.f.Close(); err != nil {
+		return fmt.Errorf("closing rdic file: %w", err)
+	}
+	return nil
+}
+
+// file is a file in the resource database.
+type file struct {
+	name string
+	size int64
+	r    *io.SectionReader
+}
+
+// Stat implements [fs.File.Stat].
+func (f *file) Stat() (fs.FileInfo, error) {
+	return &fileInfo{
+		name: path.Base(f.name),
+		size: f.size,
+	}, nil
+}
+
+// Read implements [fs.File.Read].
+func (f *file) Read(p []byte) (int, error) {
+	//nolint:wrapcheck // error wrapping is unnecessary.
+	return f.r.Read(p)
+}
+
+// ReadAt implements [io.ReaderAt.ReadAt].
+func (f *file) ReadAt(p []byte, off int64) (int, error) {
+	//nolint:wrapcheck // error wrapping is unnecessary.
+	return f.r.ReadAt(p, off)
+}
+
+// Seek implements [io.Seeker.Seek].
+func (f *file) Seek(offset int64, whence int) (int64, error) {
+	//nolint:wrapcheck // error wrapping is unnecessary.
+	return f.r.Seek(offset, whence)
+}
+
+// Close implements [fs.File.Close].
+func (f *file) Close() error {
+	return nil
+}
+
+// dir is a directory in the resource database. It implements
+// [fs.ReadDirFile].
+type dir struct {
+	db   *Database
+	name string
+
+	// offset is the number of entries returned by ReadDir.
+	offset int
+}
+
+// Stat implements [fs.File.Stat].
+func (d *dir) Stat() (fs.FileInfo, error) {
+	return &fileInfo{
+		name: path.Base(d.name),
+		dir:  true,
+	}, nil
+}
+
+// Read implements [fs.File.Read]. Directories can't be read.
+func (d *dir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errIsDir}
+}
+
+// Close implements [fs.File.Close].
+func (d *dir) Close() error {
+	return nil
+}
+
+// ReadDir implements [fs.ReadDirFile.ReadDir].
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	names := d.db.dirs[d.name][d.offset:]
+	if n > 0 {
+		if len(names) == 0 {
+			return nil, io.EOF
+		}
+		names = names[:min(n, len(names))]
+	}
+	d.offset += len(names)
+
+	entries := make([]fs.DirEntry, 0, len(names))
+	for _, name := range names {
+		info := &fileInfo{
+			name: name,
+		}
+		full := path.Join(d.name, name)
+		if w, ok := d.db.files[full]; ok {
+			info.size = int64(w.Size)
+		} else {
+			info.dir = true
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	return entries, nil
+}
+
+// fileInfo implements [fs.FileInfo] for files and directories in the
+// resource database.
+type fileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+// Name implements [fs.FileInfo.Name].
+func (fi *fileInfo) Name() string {
+	return fi.name
+}
+
+// Size implements [fs.FileInfo.Size].
+func (fi *fileInfo) Size() int64 {
+	return fi.size
+}
+
+// Mode implements [fs.FileInfo.Mode].
+func (fi *fileInfo) Mode() fs.FileMode {
+	if fi.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+// ModTime implements [fs.FileInfo.ModTime].
+func (fi *fileInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+// IsDir implements [fs.FileInfo.IsDir].
+func (fi *fileInfo) IsDir() bool {
+	return fi.dir
+}
+
+// Sys implements [fs.FileInfo.Sys].
+func (fi *fileInfo) Sys() any {
+	return nil
+}
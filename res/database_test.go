@@ -0,0 +1,117 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package res_test
+
+import (
+	"io"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/res"
+)
+
+// TestDatabase_Open tests Database.Open.
+func TestDatabase_Open(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeDatabase(t, dir, map[string][]byte{
+		"snd/hoge.wav": []byte("hoge"),
+	})
+
+	db, err := res.OpenDatabase(filepath.Join(dir, "res.rifo"))
+	if err != nil {
+		t.Fatalf("OpenDatabase: %v", err)
+	}
+	defer db.Close()
+
+	f, err := db.Open("snd/hoge.wav")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if want, got := "hoge.wav", info.Name(); want != got {
+		t.Errorf("Name: want: %q, got: %q", want, got)
+	}
+	if want, got := int64(4), info.Size(); want != got {
+		t.Errorf("Size: want: %d, got: %d", want, got)
+	}
+
+	b, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if diff := cmp.Diff([]byte("hoge"), b); diff != "" {
+		t.Errorf("ReadAll (-want, +got):\n%s", diff)
+	}
+
+	if _, err := db.Open("../hoge.wav"); err == nil {
+		t.Error("Open: expected failure for invalid path")
+	}
+}
+
+// TestDatabase_fs tests that Database implements fs.FS including directories.
+func TestDatabase_fs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeDatabase(t, dir, map[string][]byte{
+		"hoge.png":          []byte("hoge"),
+		"snd/fuga.wav":      []byte("fuga"),
+		"snd/en/piyo.wav":   []byte("piyo"),
+		"img/icons/foo.png": []byte("foo"),
+	})
+
+	db, err := res.OpenDatabase(filepath.Join(dir, "res.rifo"))
+	if err != nil {
+		t.Fatalf("OpenDatabase: %v", err)
+	}
+	defer db.Close()
+
+	if err := fstest.TestFS(db, "hoge.png", "snd/fuga.wav", "snd/en/piyo.wav", "img/icons/foo.png"); err != nil {
+		t.Fatalf("TestFS: %v", err)
+	}
+
+	var names []string
+	if err := fs.WalkDir(db, ".", func(name string, _ fs.DirEntry, err error) error {
+		names = append(names, name)
+		return err
+	}); err != nil {
+		t.Fatalf("WalkDir: %v", err)
+	}
+	expected := []string{
+		".",
+		"hoge.png",
+		"img",
+		"img/icons",
+		"img/icons/foo.png",
+		"snd",
+		"snd/en",
+		"snd/en/piyo.wav",
+		"snd/fuga.wav",
+	}
+	if diff := cmp.Diff(expected, names); diff != "" {
+		t.Errorf("WalkDir (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package res implements reading dictionary resource storage.
+//
+// Resources such as images and sound files referenced by dictionary entries
+// are stored either as plain files in a res/ directory next to the .ifo file,
+// or in a resource database made up of three files:
+//  1. res.rifo: metadata about the resource database.
+//  2. res.ridx: an index of file names and the associated offset and size of
+//     the file content in the res.rdic file. It has the same format as an
+//     .idx file.
+//  3. res.rdic: the file content. The res.rdic file can be compressed using
+//     the dictzip format.
+package res
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Open opens the resource storage for the dictionary in the given directory.
+// The resource database is preferred over the res/ directory if both are
+// present. If the returned [fs.FS] is a [*Database] it should be closed when
+// no longer in use.
+func Open(dir string) (fs.FS, error) {
+	rifoPath := filepath.Join(dir, "res.rifo")
+	if _, err := os.Stat(rifoPath); !errors.Is(err, fs.ErrNotExist) {
+		if err != nil {
+			return nil, fmt.Errorf("opening resource database: %w", err)
+		}
+		var db *Database
+		db, err = OpenDatabase(rifoPath)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
+
+	return OpenDir(filepath.Join(dir, "res"))
+}
+
+// OpenDir returns the plain resource directory at the given path as an
+// [fs.FS].
+func OpenDir(path string) (fs.FS, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening resource directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("opening resource directory: %w", &fs.PathError{
+			Op:   "open",
+			Path: path,
+			Err:  fs.ErrInvalid,
+		})
+	}
+	return os.DirFS(path), nil
+}
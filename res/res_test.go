@@ -0,0 +1,151 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package res_test
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/idx"
+	"github.com/ianlewis/go-stardict/internal/testutil"
+	"github.com/ianlewis/go-stardict/res"
+)
+
+// writeDatabase writes a test resource database to dir.
+func writeDatabase(t *testing.T, dir string, files map[string][]byte) {
+	t.Helper()
+
+	var words []*idx.Word
+	var rdic []byte
+	for name, data := range files {
+		words = append(words, &idx.Word{
+			Word:   name,
+			Offset: uint64(len(rdic)),
+			Size:   uint32(len(data)),
+		})
+		rdic = append(rdic, data...)
+	}
+	ridx := testutil.MakeIndex(words, 32)
+
+	rifo := "StarDict's storage ifo file\nversion=3.0.0\nfilecount=2\nridxfilesize=0\n"
+	if err := os.WriteFile(filepath.Join(dir, "res.rifo"), []byte(rifo), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "res.ridx"), ridx, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "res.rdic"), rdic, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestOpen tests Open.
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		database map[string][]byte
+		dir      map[string][]byte
+
+		file     string
+		expected []byte
+		err      error
+	}{
+		{
+			name: "database",
+			database: map[string][]byte{
+				"img/foo.png": []byte("foo image"),
+				"bar.wav":     []byte("bar sound"),
+			},
+			file:     "img/foo.png",
+			expected: []byte("foo image"),
+		},
+		{
+			name: "directory",
+			dir: map[string][]byte{
+				"img/foo.png": []byte("foo image"),
+			},
+			file:     "img/foo.png",
+			expected: []byte("foo image"),
+		},
+		{
+			name: "database preferred",
+			database: map[string][]byte{
+				"foo.png": []byte("database"),
+			},
+			dir: map[string][]byte{
+				"foo.png": []byte("directory"),
+			},
+			file:     "foo.png",
+			expected: []byte("database"),
+		},
+		{
+			name: "file not found",
+			database: map[string][]byte{
+				"foo.png": []byte("foo image"),
+			},
+			file: "bar.png",
+			err:  fs.ErrNotExist,
+		},
+		{
+			name: "no resources",
+			err:  fs.ErrNotExist,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			if test.database != nil {
+				writeDatabase(t, dir, test.database)
+			}
+			for name, data := range test.dir {
+				p := filepath.Join(dir, "res", filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(p, data, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			fsys, err := res.Open(dir)
+			if err == nil {
+				if c, ok := fsys.(io.Closer); ok {
+					defer c.Close()
+				}
+				var b []byte
+				b, err = fs.ReadFile(fsys, test.file)
+				if err == nil {
+					if diff := cmp.Diff(test.expected, b); diff != "" {
+						t.Errorf("ReadFile (-want, +got):\n%s", diff)
+					}
+				}
+			}
+			if !errors.Is(err, test.err) {
+				t.Fatalf("unexpected error; want: %v, got: %v", test.err, err)
+			}
+		})
+	}
+}
@@ -16,35 +16,42 @@
 package stardict
 
 import (
+	"crypto/sha256"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"unicode"
 
 	"golang.org/x/text/cases"
+	"golang.org/x/text/encoding"
 	"golang.org/x/text/runes"
 	"golang.org/x/text/transform"
 	"golang.org/x/text/unicode/norm"
 
+	"github.com/ianlewis/go-stardict/collation"
 	"github.com/ianlewis/go-stardict/dict"
 	"github.com/ianlewis/go-stardict/idx"
 	"github.com/ianlewis/go-stardict/ifo"
 	"github.com/ianlewis/go-stardict/internal/folding"
+	"github.com/ianlewis/go-stardict/res"
 	"github.com/ianlewis/go-stardict/syn"
+	"github.com/ianlewis/go-stardict/tdx"
 )
 
-const ifoMagic = "StarDict's dict ifo file"
-
 // Stardict is a stardict dictionary.
 type Stardict struct {
 	ifo  *ifo.Ifo
 	idx  *idx.Idx
+	disk *idx.DiskIdx
+	oft  *idx.OffsetIndex
 	syn  *syn.Syn
+	tdx  *tdx.Tree
 	dict *dict.Dict
+	res  fs.FS
 
 	dictFile *os.File
 
@@ -55,14 +62,24 @@ type Stardict struct {
 	wordcount        int64
 	synwordcount     int64
 	idxfilesize      int64
+	tdxfilesize      int64
 	idxoffsetbits    int
+	isTree           bool
 	author           string
 	email            string
 	website          string
 	description      string
+	lang             string
 	sametypesequence []dict.DataType
 
 	folder func() transform.Transformer
+
+	writeOffsetCache bool
+	charset          encoding.Encoding
+	maxEntrySize     uint32
+	diskIndex        bool
+	indexCacheDir    string
+	folderID         string
 }
 
 // Options are options for the Stardict dictionary.
@@ -70,15 +87,79 @@ type Options struct {
 	// Folder returns a [transform.Transformer] that performs folding (e.g.
 	// case folding, whitespace folding, etc.) on dictionary entries.
 	Folder func() transform.Transformer
+
+	// WriteOffsetCache indicates that the offset cache file (.idx.oft) used by
+	// IndexWord should be written if it is missing or out of date.
+	WriteOffsetCache bool
+
+	// Charset is the character encoding of locale text (LocaleTextType)
+	// data. If nil, the encoding is guessed from the dictionary's lang and
+	// from the data itself. Locale text that is valid UTF-8 is then not
+	// decoded.
+	Charset encoding.Encoding
+
+	// MaxEntrySize is the maximum size of an entry's data. Larger entries are
+	// reported as corrupt. If zero, [dict.DefaultMaxEntrySize] is used.
+	MaxEntrySize uint32
+
+	// DiskIndex indicates that Search and Related should binary search the
+	// .idx and .syn files on disk using [idx.DiskIdx] rather than loading the
+	// index into memory. Words are then matched ignoring the case of ASCII
+	// letters and Folder is not used. The .idx and .syn files must be
+	// uncompressed.
+	DiskIndex bool
+
+	// IndexCacheDir is a directory where Index stores folded index cache
+	// files. The folded and sorted index is written to a cache file the
+	// first time it is built and the cache file is memory-mapped by later
+	// calls to Index. Cache files are rebuilt when the .idx or .syn file
+	// changes. If empty, no cache files are used.
+	IndexCacheDir string
+
+	// FolderID identifies the folding performed by Folder. Cache files
+	// written with a different FolderID are not used. Index cache files are
+	// only used with a custom Folder if FolderID is set.
+	FolderID string
 }
 
 var (
 	errNoBookname     = errors.New("missing bookname")
+	errMissingKey     = errors.New("missing required key")
+	errNoTree         = errors.New("not a tree dictionary")
 	errInvalidVersion = errors.New("invalid version")
 	errInvalidMagic   = errors.New("invalid magic data")
 	errIfoExtension   = errors.New("invalid .ifo file extension")
 )
 
+// DefaultFolderID identifies the folding performed by DefaultFolder. It must
+// be changed when DefaultFolder changes so that index cache files written with
+// the previous folding are not used.
+const DefaultFolderID = "stardict-default-v1"
+
+// DefaultFolder returns the [transform.Transformer] used for folding when Open
+// is called without options. It performs Unicode normalization, case
+// folding, and whitespace folding and removes non-spacing marks and
+// punctuation.
+func DefaultFolder() transform.Transformer {
+	return transform.Chain(
+		// Unicode Normalization Form D (Canonical Decomposition.
+		norm.NFD,
+		// Perform case folding.
+		cases.Fold(),
+		// Perform whitespace folding.
+		&folding.WhitespaceFolder{},
+		// Remove Non-spacing marks ([, ] {, }, etc.).
+		runes.Remove(runes.In(unicode.Mn)),
+		// Remove punctuation.
+		runes.Remove(runes.In(unicode.P)),
+		// Unicode Normalization Form C (Canonical Decomposition, followed by Canonical Composition)
+		// NOTE: Case folding does not normalize the input and may not
+		// preserve a normal form. Canonical Decomposition is thus necessary
+		// to be performed a second time.
+		norm.NFC,
+	)
+}
+
 // OpenAll opens all dictionaries under a directory. This function will return
 // all successfully opened dictionaries along with any errors that occurred.
 func OpenAll(path string, options *Options) ([]*Stardict, []error) {
@@ -110,25 +191,8 @@ func OpenAll(path string, options *Options) ([]*Stardict, []error) {
 func Open(path string, options *Options) (*Stardict, error) {
 	if options == nil {
 		options = &Options{
-			Folder: func() transform.Transformer {
-				return transform.Chain(
-					// Unicode Normalization Form D (Canonical Decomposition.
-					norm.NFD,
-					// Perform case folding.
-					cases.Fold(),
-					// Perform whitespace folding.
-					&folding.WhitespaceFolder{},
-					// Remove Non-spacing marks ([, ] {, }, etc.).
-					runes.Remove(runes.In(unicode.Mn)),
-					// Remove punctuation.
-					runes.Remove(runes.In(unicode.P)),
-					// Unicode Normalization Form C (Canonical Decomposition, followed by Canonical Composition)
-					// NOTE: Case folding does not normalize the input and may not
-					// preserve a normal form. Canonical Decomposition is thus necessary
-					// to be performed a second time.
-					norm.NFC,
-				)
-			},
+			Folder:   DefaultFolder,
+			FolderID: DefaultFolderID,
 		}
 	}
 
@@ -140,9 +204,16 @@ func Open(path string, options *Options) (*Stardict, error) {
 	s.folder = func() transform.Transformer {
 		return transform.Nop
 	}
+	s.folderID = "nop"
 	if options.Folder != nil {
 		s.folder = options.Folder
+		s.folderID = options.FolderID
 	}
+	s.indexCacheDir = options.IndexCacheDir
+	s.writeOffsetCache = options.WriteOffsetCache
+	s.charset = options.Charset
+	s.maxEntrySize = options.MaxEntrySize
+	s.diskIndex = options.DiskIndex
 
 	ifoExt := filepath.Ext(s.ifoPath)
 	if ifoExt != ".ifo" && ifoExt != ".IFO" {
@@ -160,12 +231,21 @@ func Open(path string, options *Options) (*Stardict, error) {
 		return nil, fmt.Errorf("reading %q: %w", s.ifoPath, err)
 	}
 
-	if s.ifo.Magic() != ifoMagic {
+	s.isTree = s.ifo.Magic() == ifo.TreeDictMagic
+	if s.ifo.Magic() != ifo.DictMagic && !s.isTree {
 		return nil, fmt.Errorf("%w: %q", errInvalidMagic, s.ifoPath)
 	}
 
+	info, err := s.ifo.Info()
+	if err != nil {
+		if errors.Is(err, ifo.ErrInvalidIdxOffsetBits) {
+			return nil, fmt.Errorf("%w: %w", idx.ErrInvalidIdxOffset, err)
+		}
+		return nil, fmt.Errorf("reading %q: %w", s.ifoPath, err)
+	}
+
 	// Validate the version
-	s.version = s.ifo.Value("version")
+	s.version = info.Version
 	switch s.version {
 	case "2.4.2":
 	case "3.0.0":
@@ -173,63 +253,53 @@ func Open(path string, options *Options) (*Stardict, error) {
 		return nil, fmt.Errorf("%w: %v", errInvalidVersion, s.version)
 	}
 
-	s.bookname = s.ifo.Value("bookname")
+	s.bookname = info.BookName
 	if s.bookname == "" {
 		return nil, errNoBookname
 	}
 
-	s.wordcount, err = strconv.ParseInt(s.ifo.Value("wordcount"), 10, 64)
-	if err != nil {
-		return nil, fmt.Errorf("invalid workdcount: %w", err)
-	}
-
-	s.idxfilesize, err = strconv.ParseInt(s.ifo.Value("idxfilesize"), 10, 64)
-	if err != nil {
-		return nil, fmt.Errorf("invalid idxfilesize: %w", err)
+	// Tree dictionaries use a .tdx file instead of the .idx file so the
+	// wordcount and idxfilesize may be omitted.
+	required := []string{"wordcount", "idxfilesize"}
+	if s.isTree {
+		required = []string{"tdxfilesize"}
 	}
-
-	idxoffsetbits := s.ifo.Value("idxoffsetbits")
-	// TODO(#28): version check should be > 3.0.0
-	if idxoffsetbits != "" && s.version == "3.0.0" {
-		var idxoffsetbits64 int64
-		idxoffsetbits64, err = strconv.ParseInt(idxoffsetbits, 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("%w: %w", idx.ErrInvalidIdxOffset, err)
-		}
-		if idxoffsetbits64 != 32 && idxoffsetbits64 != 64 {
-			return nil, fmt.Errorf("%w: %d", idx.ErrInvalidIdxOffset, idxoffsetbits64)
+	for _, key := range required {
+		if s.ifo.Value(key) == "" {
+			return nil, fmt.Errorf("%w: %s", errMissingKey, key)
 		}
-		s.idxoffsetbits = int(idxoffsetbits64)
 	}
+	s.wordcount = info.WordCount
+	s.idxfilesize = info.IdxFileSize
+	s.tdxfilesize = info.TdxFileSize
+	s.synwordcount = info.SynWordCount
 
-	synwordcount := s.ifo.Value("synwordcount")
-	if synwordcount != "" {
-		s.synwordcount, err = strconv.ParseInt(synwordcount, 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("bad synwordcount: %w", err)
-		}
+	if info.IdxOffsetBits != 0 {
+		s.idxoffsetbits = info.IdxOffsetBits
 	}
 
-	sametypesequence := s.ifo.Value("sametypesequence")
-	if sametypesequence != "" {
-		for _, r := range sametypesequence {
-			s.sametypesequence = append(s.sametypesequence, dict.DataType(r))
-		}
+	for _, r := range info.SameTypeSequence {
+		s.sametypesequence = append(s.sametypesequence, dict.DataType(r))
 	}
 
-	s.author = s.ifo.Value("author")
-	s.email = s.ifo.Value("email")
-	s.description = strings.ReplaceAll(s.ifo.Value("description"), "<br>", "\n")
-	s.website = s.ifo.Value("website")
-
-	// TODO(#3): support the .tdx file.
-	// TODO(#4): support resource storage
-	// TODO(#7): support collation files.
-	// TODO(#8): support offset cache files.
+	s.author = info.Author
+	s.email = info.Email
+	s.description = info.Description
+	s.website = info.Website
+	s.lang = info.Lang
 
 	return s, nil
 }
 
+// Info returns the dictionary's typed metadata from the .ifo file.
+func (s *Stardict) Info() (*ifo.Info, error) {
+	info, err := s.ifo.Info()
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", s.ifoPath, err)
+	}
+	return info, nil
+}
+
 // Bookname returns the dictionary name.
 func (s *Stardict) Bookname() string {
 	return s.bookname
@@ -267,6 +337,12 @@ func (s *Stardict) SynWordCount() int64 {
 	return s.synwordcount
 }
 
+// Lang returns the dictionary language. This field is optional for
+// dictionaries.
+func (s *Stardict) Lang() string {
+	return s.lang
+}
+
 // Version returns the dictionary format version.
 func (s *Stardict) Version() string {
 	return s.version
@@ -302,10 +378,8 @@ func (s *Stardict) Version() string {
 // The pattern is folded using the given folding transformer and matches the
 // folded word in the index.
 func (s *Stardict) Search(query string) ([]*Entry, error) {
-	var entries []*Entry
-
 	// Read entries from the index.
-	index, err := s.Index()
+	index, err := s.searchIndex()
 	if err != nil {
 		return nil, err
 	}
@@ -314,12 +388,106 @@ func (s *Stardict) Search(query string) ([]*Entry, error) {
 		return nil, fmt.Errorf("searching index: %w", err)
 	}
 
-	// Read the entries from the dict.
+	return s.entries(idxResults)
+}
+
+// Related follows a WordNet relation from the synset and returns the
+// dictionary entries for the related words. Related words that are not found
+// in the index are skipped.
+func (s *Stardict) Related(synset *dict.WordNetSynset, rel dict.WordNetRelation) ([]*Entry, error) {
+	index, err := s.searchIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	var idxResults []*idx.Word
+	for _, word := range synset.Related(rel) {
+		words, err := index.Lookup(word)
+		if err != nil {
+			return nil, fmt.Errorf("looking up %q: %w", word, err)
+		}
+		idxResults = append(idxResults, words...)
+	}
+
+	return s.entries(idxResults)
+}
+
+// Walk calls fn for each entry in the dictionary in .idx file order. Entries
+// are read one at a time so that the full index is not loaded into memory.
+// Only the synonyms are loaded up front so that entries include their
+// synonyms. Walk stops and returns the error if fn returns an error.
+func (s *Stardict) Walk(fn func(*Entry) error) error {
+	synonyms, err := s.synonyms()
+	if err != nil {
+		return err
+	}
+
+	d, err := s.Dict()
+	if err != nil {
+		return err
+	}
+
+	sc, err := s.IndexScanner()
+	if err != nil {
+		return err
+	}
+	defer sc.Close()
+
+	var n uint32
+	for sc.Scan() {
+		idxWord := sc.Word()
+		dictWord, err := d.Word(idxWord)
+		if err != nil {
+			return fmt.Errorf("reading word %q: %w", idxWord.Word, err)
+		}
+		if err := fn(&Entry{
+			word:     idxWord.Word,
+			synonyms: synonyms[n],
+			data:     dictWord.Data,
+		}); err != nil {
+			return err
+		}
+		n++
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("scanning index: %w", err)
+	}
+	return nil
+}
+
+// synonyms reads the .syn file and returns the synonyms for each index
+// position. It returns an empty map if the dictionary has no .syn file.
+func (s *Stardict) synonyms() (map[uint32][]string, error) {
+	synonyms := map[uint32][]string{}
+
+	sc, err := syn.NewScannerFromIfoPath(s.ifoPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return synonyms, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating synonym scanner: %w", err)
+	}
+	defer sc.Close()
+
+	for sc.Scan() {
+		w := sc.Word()
+		synonyms[w.OriginalWordIndex] = append(synonyms[w.OriginalWordIndex], w.Word)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("scanning synonyms: %w", err)
+	}
+	return synonyms, nil
+}
+
+// entries reads the entries for the index words from the dict.
+func (s *Stardict) entries(idxWords []*idx.Word) ([]*Entry, error) {
+	var entries []*Entry
+
 	d, err := s.Dict()
 	if err != nil {
 		return nil, err
 	}
-	for _, idxWord := range idxResults {
+	for _, idxWord := range idxWords {
 		dictWord, err := d.Word(idxWord)
 		if err != nil {
 			return nil, fmt.Errorf("reading word: %w", err)
@@ -351,13 +519,26 @@ func (s *Stardict) Index() (*idx.Idx, error) {
 		return s.idx, nil
 	}
 
-	// Open the .idx file.
-	index, err := idx.NewFromIfoPath(s.ifoPath, &idx.Options{
+	compare, err := s.compare(".idx.clt")
+	if err != nil {
+		return nil, err
+	}
+
+	options := &idx.Options{
 		Folder: s.folder,
 		ScannerOptions: &idx.ScannerOptions{
 			OffsetBits: s.idxoffsetbits,
 		},
-	})
+		Compare: compare,
+	}
+	if s.indexCacheDir != "" && s.folderID != "" {
+		// The cache depends on the folding and how the .idx file is read.
+		options.CachePath = s.indexCachePath()
+		options.FolderID = fmt.Sprintf("%s\nidxoffsetbits=%d", s.folderID, s.idxoffsetbits)
+	}
+
+	// Open the .idx file.
+	index, err := idx.NewFromIfoPath(s.ifoPath, options)
 	if err != nil {
 		return nil, fmt.Errorf("opening index: %w", err)
 	}
@@ -366,15 +547,101 @@ func (s *Stardict) Index() (*idx.Idx, error) {
 	return s.idx, nil
 }
 
+// DiskIndex returns a version of the dictionary's index that binary searches
+// the .idx and .syn files on disk. Only the offset of each entry is held in
+// memory. DiskIndex requires uncompressed .idx and .syn files.
+func (s *Stardict) DiskIndex() (*idx.DiskIdx, error) {
+	if s.disk != nil {
+		return s.disk, nil
+	}
+
+	disk, err := idx.NewDiskFromIfoPath(s.ifoPath, &idx.ScannerOptions{
+		OffsetBits: s.idxoffsetbits,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("opening disk index: %w", err)
+	}
+	s.disk = disk
+
+	return s.disk, nil
+}
+
+// searcher is implemented by the index types used by Search and Related.
+type searcher interface {
+	Search(query string) ([]*idx.Word, error)
+	Lookup(word string) ([]*idx.Word, error)
+}
+
+// searchIndex returns the index used by Search and Related.
+func (s *Stardict) searchIndex() (searcher, error) {
+	if s.diskIndex {
+		disk, err := s.DiskIndex()
+		if err != nil {
+			return nil, err
+		}
+		return disk, nil
+	}
+	index, err := s.Index()
+	if err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// indexCachePath returns the path of the folded index cache file in the
+// index cache directory. The file name includes a hash of the absolute .ifo
+// path so that dictionaries with the same name don't share a cache file.
+func (s *Stardict) indexCachePath() string {
+	path, err := filepath.Abs(s.ifoPath)
+	if err != nil {
+		path = s.ifoPath
+	}
+	sum := sha256.Sum256([]byte(path))
+	base := strings.TrimSuffix(filepath.Base(s.ifoPath), filepath.Ext(s.ifoPath))
+	return filepath.Join(s.indexCacheDir, fmt.Sprintf("%s-%x.idx.cache", base, sum[:8]))
+}
+
+// IndexWord returns the n-th entry in the dictionary's .idx file. Entries are
+// read directly from the .idx file using an offset cache so the full index is
+// not loaded into memory. The offset cache is read from the .idx.oft file if it
+// is up to date and is otherwise built by scanning the .idx file. IndexWord
+// requires an uncompressed .idx file.
+func (s *Stardict) IndexWord(n int64) (*idx.Word, error) {
+	if s.oft == nil {
+		oft, err := idx.NewOffsetIndexFromIfoPath(s.ifoPath, s.wordcount, &idx.OffsetIndexOptions{
+			ScannerOptions: &idx.ScannerOptions{
+				OffsetBits: s.idxoffsetbits,
+			},
+			WriteCache: s.writeOffsetCache,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("opening offset index: %w", err)
+		}
+		s.oft = oft
+	}
+
+	w, err := s.oft.Word(n)
+	if err != nil {
+		return nil, fmt.Errorf("reading index word: %w", err)
+	}
+	return w, nil
+}
+
 // Syn returns a simple in-memory version of the dictionary's synonym index.
 func (s *Stardict) Syn() (*syn.Syn, error) {
 	if s.syn != nil {
 		return s.syn, nil
 	}
 
+	compare, err := s.compare(".syn.clt")
+	if err != nil {
+		return nil, err
+	}
+
 	// Open the .syn file.
 	synIndex, err := syn.NewFromIfoPath(s.ifoPath, &syn.Options{
-		Folder: s.folder,
+		Folder:  s.folder,
+		Compare: compare,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("opening synonym index: %w", err)
@@ -384,6 +651,71 @@ func (s *Stardict) Syn() (*syn.Syn, error) {
 	return s.syn, nil
 }
 
+// IsTree returns true if the dictionary is a tree dictionary. Tree
+// dictionaries are browsed using Tree rather than searched with an index.
+func (s *Stardict) IsTree() bool {
+	return s.isTree
+}
+
+// Tree returns a simple in-memory version of the tree dictionary's .tdx tree
+// index. Use TreeWord to read the dictionary data for a node.
+func (s *Stardict) Tree() (*tdx.Tree, error) {
+	if s.tdx != nil {
+		return s.tdx, nil
+	}
+	if !s.IsTree() {
+		return nil, fmt.Errorf("%w: %q", errNoTree, s.ifoPath)
+	}
+
+	// Open the .tdx file.
+	tree, err := tdx.NewFromIfoPath(s.ifoPath, &tdx.ScannerOptions{
+		OffsetBits: s.idxoffsetbits,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("opening tree index: %w", err)
+	}
+	s.tdx = tree
+
+	return s.tdx, nil
+}
+
+// TreeWord reads the dictionary data for the given tree node.
+func (s *Stardict) TreeWord(n *tdx.Node) (*dict.Word, error) {
+	d, err := s.Dict()
+	if err != nil {
+		return nil, err
+	}
+
+	w := n.Word()
+	dictWord, err := d.Word(&idx.Word{
+		Word:   w.Word,
+		Offset: w.Offset,
+		Size:   w.Size,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading word: %w", err)
+	}
+	return dictWord, nil
+}
+
+// compare returns the comparison function used to order search results and
+// browsed words. The collation function named in the collation file with the
+// given extension is used if present. Otherwise, a collation for the
+// dictionary language is used. If the dictionary has no language then nil is
+// returned and the default ordering is used.
+func (s *Stardict) compare(cltExt string) (func(a, b string) int, error) {
+	cltPath := strings.TrimSuffix(s.ifoPath, filepath.Ext(s.ifoPath)) + cltExt
+	clt, err := collation.ReadFile(cltPath)
+	if err == nil {
+		return clt.Func.Compare(), nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("reading collation file: %w", err)
+	}
+
+	return collation.ForLanguage(s.lang), nil
+}
+
 // Dict returns the dictionary's dict.
 func (s *Stardict) Dict() (*dict.Dict, error) {
 	if s.dict != nil {
@@ -391,8 +723,15 @@ func (s *Stardict) Dict() (*dict.Dict, error) {
 	}
 
 	// Open the .dict file.
+	charset := s.charset
+	if charset == nil {
+		charset = dict.CharsetForLanguage(s.lang)
+	}
 	d, err := dict.NewFromIfoPath(s.ifoPath, &dict.Options{
 		SameTypeSequence: s.sametypesequence,
+		Charset:          charset,
+		DetectCharset:    s.charset == nil,
+		MaxEntrySize:     s.maxEntrySize,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("opening dict: %w", err)
@@ -402,8 +741,47 @@ func (s *Stardict) Dict() (*dict.Dict, error) {
 	return s.dict, nil
 }
 
+// Resources returns the dictionary's resource storage. Resources are files,
+// such as images and sound files, that are referenced by dictionary entries
+// with the [dict.ResourceFileListType] data type. Resources are read from the
+// resource database (res.rifo, res.ridx, res.rdic) if present, or the res/
+// directory in the same directory as the .ifo file.
+func (s *Stardict) Resources() (fs.FS, error) {
+	if s.res != nil {
+		return s.res, nil
+	}
+
+	fsys, err := res.Open(filepath.Dir(s.ifoPath))
+	if err != nil {
+		return nil, fmt.Errorf("opening resources: %w", err)
+	}
+	s.res = fsys
+
+	return s.res, nil
+}
+
 // Close closes the dict and any underlying readers.
 func (s *Stardict) Close() error {
+	if s.idx != nil {
+		if err := s.idx.Close(); err != nil {
+			return fmt.Errorf("closing index: %w", err)
+		}
+	}
+	if s.disk != nil {
+		if err := s.disk.Close(); err != nil {
+			return fmt.Errorf("closing disk index: %w", err)
+		}
+	}
+	if s.oft != nil {
+		if err := s.oft.Close(); err != nil {
+			return fmt.Errorf("closing offset index: %w", err)
+		}
+	}
+	if c, ok := s.res.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			return fmt.Errorf("closing resources: %w", err)
+		}
+	}
 	if s.dictFile != nil {
 		if err := s.dictFile.Close(); err != nil {
 			return fmt.Errorf("closing dict file: %w", err)
@@ -16,17 +16,23 @@
 package stardict
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/text/encoding/traditionalchinese"
 
+	"github.com/ianlewis/go-stardict/collation"
 	"github.com/ianlewis/go-stardict/dict"
 	"github.com/ianlewis/go-stardict/idx"
+	"github.com/ianlewis/go-stardict/ifo"
 	"github.com/ianlewis/go-stardict/internal/testutil"
 	"github.com/ianlewis/go-stardict/syn"
+	"github.com/ianlewis/go-stardict/tdx"
 )
 
 type testDict struct {
@@ -34,6 +40,7 @@ type testDict struct {
 	dict []*dict.Word
 	idx  []*idx.Word
 	syn  []*syn.Word
+	tdx  []*tdx.Word
 }
 
 // writeDict writes out a test dictionary set of files.
@@ -57,6 +64,12 @@ func writeDict(t *testing.T, d *testDict) string {
 			panic(err)
 		}
 	}
+	// NOTE: tdx is only present for tree dictionaries.
+	if len(d.tdx) > 0 {
+		if err := os.WriteFile(filepath.Join(path, "dictionary.tdx"), testutil.MakeTdx(d.tdx, 32), 0o600); err != nil {
+			panic(err)
+		}
+	}
 	if err := os.WriteFile(filepath.Join(path, "dictionary.dict"), testutil.MakeDict(t, d.dict, nil), 0o600); err != nil {
 		panic(err)
 	}
@@ -105,6 +118,34 @@ idxoffsetbits=123`,
 			},
 			err: idx.ErrInvalidIdxOffset,
 		},
+		{
+			name: "idxoffsetbits ignored",
+			dicts: []*testDict{
+				{
+					ifo: `StarDict's dict ifo file
+version=2.4.2
+bookname=hoge
+wordcount=1
+idxfilesize=6
+idxoffsetbits=123`,
+				},
+			},
+
+			bookname:  "hoge",
+			wordcount: 1,
+		},
+		{
+			name: "missing wordcount",
+			dicts: []*testDict{
+				{
+					ifo: `StarDict's dict ifo file
+version=3.0.0
+bookname=hoge
+idxfilesize=6`,
+				},
+			},
+			err: errMissingKey,
+		},
 	}
 
 	for _, test := range tests {
@@ -413,6 +454,583 @@ idxfilesize=0`,
 	}
 }
 
+// TestSearch_diskIndex tests Search with Options.DiskIndex.
+func TestSearch_diskIndex(t *testing.T) {
+	t.Parallel()
+
+	path := writeDict(t, &testDict{
+		ifo: `StarDict's dict ifo file
+version=3.0.0
+bookname=hoge
+wordcount=2
+synwordcount=1
+idxfilesize=26`,
+		dict: []*dict.Word{
+			{Data: []*dict.Data{{Type: dict.UTFTextType, Data: []byte("fuga")}}},
+			{Data: []*dict.Data{{Type: dict.UTFTextType, Data: []byte("hoge")}}},
+		},
+		idx: []*idx.Word{
+			{Word: "Fuga", Offset: 0, Size: 6},
+			{Word: "hoge", Offset: 6, Size: 6},
+		},
+		syn: []*syn.Word{
+			{Word: "piyo", OriginalWordIndex: 1},
+		},
+	})
+	defer os.RemoveAll(path)
+
+	d, err := Open(filepath.Join(path, "dictionary.ifo"), &Options{
+		DiskIndex: true,
+	})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer d.Close()
+
+	for query, expected := range map[string][]*Entry{
+		"fuga": {
+			{
+				word: "Fuga",
+				data: []*dict.Data{{Type: dict.UTFTextType, Data: []byte("fuga")}},
+			},
+		},
+		"PIYO": {
+			{
+				word: "hoge",
+				data: []*dict.Data{{Type: dict.UTFTextType, Data: []byte("hoge")}},
+			},
+		},
+		"pico": nil,
+	} {
+		results, err := d.Search(query)
+		if err != nil {
+			t.Fatalf("Search(%q): %v", query, err)
+		}
+		if diff := cmp.Diff(expected, results, cmp.AllowUnexported(Entry{})); diff != "" {
+			t.Errorf("Search(%q) (-want, +got):\n%s", query, diff)
+		}
+	}
+}
+
+// TestSearch_indexCache tests that searches return the same results when the
+// folded index is read from the index cache.
+func TestSearch_indexCache(t *testing.T) {
+	t.Parallel()
+
+	path := writeDict(t, &testDict{
+		ifo: `StarDict's dict ifo file
+version=3.0.0
+bookname=hoge
+wordcount=2
+synwordcount=1
+idxfilesize=26`,
+		dict: []*dict.Word{
+			{Data: []*dict.Data{{Type: dict.UTFTextType, Data: []byte("fuga")}}},
+			{Data: []*dict.Data{{Type: dict.UTFTextType, Data: []byte("hoge")}}},
+		},
+		idx: []*idx.Word{
+			{Word: "Fuga", Offset: 0, Size: 6},
+			{Word: "hoge", Offset: 6, Size: 6},
+		},
+		syn: []*syn.Word{
+			{Word: "piyo", OriginalWordIndex: 1},
+		},
+	})
+	defer os.RemoveAll(path)
+
+	cacheDir := t.TempDir()
+	expected := map[string][]*Entry{
+		"FUGA": {
+			{
+				word: "Fuga",
+				data: []*dict.Data{{Type: dict.UTFTextType, Data: []byte("fuga")}},
+			},
+		},
+		"piyo": {
+			{
+				word: "hoge",
+				data: []*dict.Data{{Type: dict.UTFTextType, Data: []byte("hoge")}},
+			},
+		},
+		"pico": nil,
+	}
+
+	// The first open writes the cache and the second open reads it.
+	for range 2 {
+		d, err := Open(filepath.Join(path, "dictionary.ifo"), &Options{
+			Folder:        DefaultFolder,
+			FolderID:      DefaultFolderID,
+			IndexCacheDir: cacheDir,
+		})
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+
+		for query, want := range expected {
+			results, err := d.Search(query)
+			if err != nil {
+				t.Fatalf("Search(%q): %v", query, err)
+			}
+			if diff := cmp.Diff(want, results, cmp.AllowUnexported(Entry{})); diff != "" {
+				t.Errorf("Search(%q) (-want, +got):\n%s", query, diff)
+			}
+		}
+
+		if err := d.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	}
+
+	matches, err := filepath.Glob(filepath.Join(cacheDir, "dictionary-*.idx.cache"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 {
+		t.Errorf("expected one cache file, got %v", matches)
+	}
+}
+
+// TestTree tests Stardict.Tree and Stardict.TreeWord.
+func TestTree(t *testing.T) {
+	t.Parallel()
+
+	td := &testDict{
+		ifo: `StarDict's treedict ifo file
+version=2.4.2
+bookname=hoge
+tdxfilesize=31`,
+		dict: []*dict.Word{
+			{
+				Data: []*dict.Data{
+					{
+						Type: dict.UTFTextType,
+						Data: []byte("root"),
+					},
+				},
+			},
+			{
+				Data: []*dict.Data{
+					{
+						Type: dict.UTFTextType,
+						Data: []byte("child"),
+					},
+				},
+			},
+		},
+		tdx: []*tdx.Word{
+			{
+				Word:       "root",
+				Offset:     0,
+				Size:       6,
+				ChildCount: 1,
+			},
+			{
+				Word:   "child",
+				Offset: 6,
+				Size:   7,
+			},
+		},
+	}
+
+	path := writeDict(t, td)
+	defer os.RemoveAll(path)
+
+	s, err := Open(filepath.Join(path, "dictionary.ifo"), nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+
+	if !s.IsTree() {
+		t.Fatal("IsTree: want: true, got: false")
+	}
+
+	tree, err := s.Tree()
+	if err != nil {
+		t.Fatalf("Tree: %v", err)
+	}
+
+	roots := tree.Roots()
+	if want, got := 1, len(roots); want != got {
+		t.Fatalf("Roots: want: %d, got: %d", want, got)
+	}
+	children := roots[0].Children()
+	if want, got := 1, len(children); want != got {
+		t.Fatalf("Children: want: %d, got: %d", want, got)
+	}
+	if children[0].Parent() != roots[0] {
+		t.Errorf("Parent: want: %v, got: %v", roots[0], children[0].Parent())
+	}
+
+	w, err := s.TreeWord(children[0])
+	if err != nil {
+		t.Fatalf("TreeWord: %v", err)
+	}
+	if diff := cmp.Diff(td.dict[1], w); diff != "" {
+		t.Errorf("TreeWord (-want, +got):\n%s", diff)
+	}
+}
+
+// TestResources tests Stardict.Resources.
+func TestResources(t *testing.T) {
+	t.Parallel()
+
+	path := writeDict(t, &testDict{
+		ifo: `StarDict's dict ifo file
+version=3.0.0
+bookname=hoge
+wordcount=0
+idxfilesize=0`,
+	})
+	defer os.RemoveAll(path)
+
+	if err := os.MkdirAll(filepath.Join(path, "res", "img"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "res", "img", "hoge.png"), []byte("hoge"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Open(filepath.Join(path, "dictionary.ifo"), nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+
+	fsys, err := s.Resources()
+	if err != nil {
+		t.Fatalf("Resources: %v", err)
+	}
+
+	b, err := fs.ReadFile(fsys, "img/hoge.png")
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if diff := cmp.Diff([]byte("hoge"), b); diff != "" {
+		t.Errorf("ReadFile (-want, +got):\n%s", diff)
+	}
+}
+
+// TestIndex_collation tests that the index is ordered using the dictionary's
+// collation.
+// TestSearch_language tests that prefix searches find all matching words when
+// the index is ordered using a collation for the dictionary language. In
+// Danish "aa" is sorted after "z".
+func TestSearch_language(t *testing.T) {
+	t.Parallel()
+
+	for name, cache := range map[string]bool{
+		"no cache": false,
+		"cache":    true,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := writeDict(t, &testDict{
+				ifo: `StarDict's dict ifo file
+version=3.0.0
+bookname=hoge
+wordcount=4
+idxfilesize=0
+lang=da`,
+				idx: []*idx.Word{
+					{Word: "aa"},
+					{Word: "ab"},
+					{Word: "ba"},
+					{Word: "z"},
+				},
+			})
+			defer os.RemoveAll(path)
+
+			options := &Options{}
+			if cache {
+				options.Folder = DefaultFolder
+				options.FolderID = DefaultFolderID
+				options.IndexCacheDir = t.TempDir()
+			}
+
+			// The index is opened twice so that the cache file is used.
+			for range 2 {
+				s, err := Open(filepath.Join(path, "dictionary.ifo"), options)
+				if err != nil {
+					t.Fatalf("Open: %v", err)
+				}
+				defer s.Close()
+
+				index, err := s.Index()
+				if err != nil {
+					t.Fatalf("Index: %v", err)
+				}
+				result, err := index.Search("a*")
+				if err != nil {
+					t.Fatalf("Search: %v", err)
+				}
+
+				var words []string
+				for _, w := range result {
+					words = append(words, w.Word)
+				}
+				if diff := cmp.Diff([]string{"ab", "aa"}, words); diff != "" {
+					t.Errorf("Search (-want, +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestIndex_collation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		lang string
+		clt  *collation.File
+
+		expected []string
+	}{
+		{
+			name:     "default",
+			expected: []string{"Zebra", "apa", "äpple"},
+		},
+		{
+			name:     "language",
+			lang:     "sv",
+			expected: []string{"apa", "Zebra", "äpple"},
+		},
+		{
+			name: "collation file",
+			lang: "sv",
+			clt: &collation.File{
+				URL:   "dictionary.idx",
+				Func:  collation.UTF8Bin,
+				Order: []uint32{1, 0, 2},
+			},
+			expected: []string{"Zebra", "apa", "äpple"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := writeDict(t, &testDict{
+				ifo: `StarDict's dict ifo file
+version=3.0.0
+bookname=hoge
+wordcount=3
+idxfilesize=0
+lang=` + test.lang,
+				idx: []*idx.Word{
+					{Word: "apa"},
+					{Word: "Zebra"},
+					{Word: "äpple"},
+				},
+			})
+			defer os.RemoveAll(path)
+
+			if test.clt != nil {
+				f, err := os.Create(filepath.Join(path, "dictionary.idx.clt"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := collation.Write(f, test.clt); err != nil {
+					t.Fatal(err)
+				}
+				if err := f.Close(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			s, err := Open(filepath.Join(path, "dictionary.ifo"), &Options{})
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			defer s.Close()
+
+			index, err := s.Index()
+			if err != nil {
+				t.Fatalf("Index: %v", err)
+			}
+
+			var words []string
+			for _, w := range index.Words() {
+				words = append(words, w.Word)
+			}
+			if diff := cmp.Diff(test.expected, words); diff != "" {
+				t.Errorf("Words (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestIndexWord tests IndexWord.
+func TestIndexWord(t *testing.T) {
+	t.Parallel()
+
+	path := writeDict(t, &testDict{
+		ifo: `StarDict's dict ifo file
+version=3.0.0
+bookname=hoge
+wordcount=3
+idxfilesize=0`,
+		idx: []*idx.Word{
+			{Word: "bar", Offset: 0, Size: 1},
+			{Word: "baz", Offset: 1, Size: 2},
+			{Word: "foo", Offset: 3, Size: 3},
+		},
+	})
+	defer os.RemoveAll(path)
+
+	s, err := Open(filepath.Join(path, "dictionary.ifo"), &Options{
+		WriteOffsetCache: true,
+	})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+
+	w, err := s.IndexWord(2)
+	if err != nil {
+		t.Fatalf("IndexWord: %v", err)
+	}
+	if diff := cmp.Diff(&idx.Word{Word: "foo", Offset: 3, Size: 3}, w); diff != "" {
+		t.Errorf("IndexWord (-want, +got):\n%s", diff)
+	}
+
+	if _, err := s.IndexWord(3); !errors.Is(err, idx.ErrOutOfRange) {
+		t.Errorf("IndexWord: expected %v, got %v", idx.ErrOutOfRange, err)
+	}
+
+	if _, err := os.Stat(filepath.Join(path, "dictionary.idx.oft")); err != nil {
+		t.Errorf("offset cache not written: %v", err)
+	}
+}
+
+// TestRelated tests Related.
+func TestRelated(t *testing.T) {
+	t.Parallel()
+
+	dogData := []byte("<type>n</type><wordgroup><word>dog</word></wordgroup>" +
+		"<gloss>a domestic animal</gloss><hypernym>Canine</hypernym><antonym>missing</antonym>")
+	canineData := []byte("a carnivore")
+
+	path := writeDict(t, &testDict{
+		ifo: `StarDict's dict ifo file
+version=3.0.0
+bookname=hoge
+wordcount=2
+idxfilesize=0`,
+		dict: []*dict.Word{
+			{
+				Data: []*dict.Data{
+					{Type: dict.WordNetType, Data: dogData},
+				},
+			},
+			{
+				Data: []*dict.Data{
+					{Type: dict.UTFTextType, Data: canineData},
+				},
+			},
+		},
+		idx: []*idx.Word{
+			{Word: "canine", Offset: uint64(len(dogData) + 2), Size: uint32(len(canineData) + 2)},
+			{Word: "dog", Offset: 0, Size: uint32(len(dogData) + 2)},
+		},
+	})
+	defer os.RemoveAll(path)
+
+	s, err := Open(filepath.Join(path, "dictionary.ifo"), nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+
+	entries, err := s.Search("dog")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Search: expected 1 entry, got %d", len(entries))
+	}
+	wn, err := dict.ParseWordNet(entries[0].Data()[0].Data)
+	if err != nil {
+		t.Fatalf("ParseWordNet: %v", err)
+	}
+
+	related, err := s.Related(wn.Synsets[0], dict.Hypernym)
+	if err != nil {
+		t.Fatalf("Related: %v", err)
+	}
+	expected := []*Entry{
+		{
+			word: "canine",
+			data: []*dict.Data{
+				{Type: dict.UTFTextType, Data: canineData},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, related, cmp.AllowUnexported(Entry{})); diff != "" {
+		t.Errorf("Related (-want, +got):\n%s", diff)
+	}
+
+	// Related words not in the index are skipped.
+	related, err = s.Related(wn.Synsets[0], dict.Antonym)
+	if err != nil {
+		t.Fatalf("Related: %v", err)
+	}
+	if len(related) != 0 {
+		t.Errorf("Related: expected no entries, got %d", len(related))
+	}
+}
+
+// TestSearch_charset tests that locale text is decoded using the charset for
+// the dictionary's language.
+func TestSearch_charset(t *testing.T) {
+	t.Parallel()
+
+	big5, err := traditionalchinese.Big5.NewEncoder().Bytes([]byte("字典"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := writeDict(t, &testDict{
+		ifo: `StarDict's dict ifo file
+version=3.0.0
+bookname=hoge
+wordcount=1
+idxfilesize=0
+lang=zh_TW`,
+		dict: []*dict.Word{
+			{
+				Data: []*dict.Data{
+					{Type: dict.LocaleTextType, Data: big5},
+				},
+			},
+		},
+		idx: []*idx.Word{
+			{Word: "hoge", Offset: 0, Size: uint32(len(big5) + 2)},
+		},
+	})
+	defer os.RemoveAll(path)
+
+	s, err := Open(filepath.Join(path, "dictionary.ifo"), nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+
+	entries, err := s.Search("hoge")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Search: expected 1 entry, got %d", len(entries))
+	}
+	if diff := cmp.Diff("字典", entries[0].Data()[0].String()); diff != "" {
+		t.Errorf("String (-want, +got):\n%s", diff)
+	}
+}
+
 // TODO(#1): Restore concurrency test
 // TestConcurrency tests that Stardict can be used concurrently.
 // func TestConcurrency(t *testing.T) {
@@ -476,3 +1094,75 @@ idxfilesize=0`,
 //	}
 
 // }
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	b, err := NewBuilder(&ifo.Info{
+		BookName: "hoge",
+	}, &BuilderOptions{
+		CompressIndex: true,
+	})
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+	for _, e := range []struct {
+		word     string
+		synonyms []string
+	}{
+		{"hoge", []string{"piyo", "foo"}},
+		{"fuga", nil},
+	} {
+		if err := b.AddEntry(e.word, e.synonyms, &dict.Data{
+			Type: dict.UTFTextType,
+			Data: []byte(e.word + " data"),
+		}); err != nil {
+			t.Fatalf("AddEntry: %v", err)
+		}
+	}
+	dir := t.TempDir()
+	if err := b.Finish(dir, "hoge"); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+
+	s, err := Open(filepath.Join(dir, "hoge.ifo"), nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+
+	var entries []*Entry
+	if err := s.Walk(func(e *Entry) error {
+		entries = append(entries, e)
+		return nil
+	}); err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+
+	// Entries are returned in .idx file order.
+	expected := []*Entry{
+		{
+			word: "fuga",
+			data: []*dict.Data{
+				{Type: dict.UTFTextType, Data: []byte("fuga data")},
+			},
+		},
+		{
+			word:     "hoge",
+			synonyms: []string{"foo", "piyo"},
+			data: []*dict.Data{
+				{Type: dict.UTFTextType, Data: []byte("hoge data")},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, entries, cmp.AllowUnexported(Entry{})); diff != "" {
+		t.Errorf("Walk (-want, +got):\n%s", diff)
+	}
+
+	errStop := errors.New("stop")
+	if err := s.Walk(func(*Entry) error {
+		return errStop
+	}); !errors.Is(err, errStop) {
+		t.Errorf("Walk: expected %v, got %v", errStop, err)
+	}
+}
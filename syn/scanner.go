@@ -18,10 +18,17 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
+
+	"github.com/ianlewis/go-stardict/internal/gzipfile"
 )
 
+// ErrTruncated indicates that the synonym index ends with an incomplete
+// entry.
+var ErrTruncated = errors.New("truncated synonym entry")
+
 // Scanner scans an index from start to end.
 type Scanner struct {
 	r io.ReadCloser
@@ -40,13 +47,20 @@ func NewScanner(r io.ReadCloser) (*Scanner, error) {
 	return s, nil
 }
 
-// NewScannerFromIfoPath returns a new in-memory index.
+// NewScannerFromIfoPath returns a new synonym scanner for the .syn file given
+// the path to the .ifo file. Compressed .syn.gz files are decompressed.
 func NewScannerFromIfoPath(ifoPath string) (*Scanner, error) {
 	f, err := Open(ifoPath)
 	if err != nil {
 		return nil, err
 	}
-	return NewScanner(f)
+
+	r, err := gzipfile.NewReader(f)
+	if err != nil {
+		//nolint:wrapcheck // error is already wrapped.
+		return nil, err
+	}
+	return NewScanner(r)
 }
 
 // Scan advances the index to the next index entry. It returns false if the
@@ -97,7 +111,7 @@ func (s *Scanner) splitIndex(data []byte, atEOF bool) (advance int, token []byte
 	}
 
 	if atEOF {
-		return len(data), data, nil
+		return 0, nil, fmt.Errorf("%w: %d trailing bytes", ErrTruncated, len(data))
 	}
 
 	// Request more data.
@@ -16,7 +16,11 @@ package syn_test
 
 import (
 	"bytes"
+	"compress/gzip"
+	"errors"
 	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -84,3 +88,86 @@ func TestSynScanner(t *testing.T) {
 		})
 	}
 }
+
+// TestSynScanner_truncated tests that a truncated final entry is an error.
+func TestSynScanner_truncated(t *testing.T) {
+	t.Parallel()
+
+	expected := []*syn.Word{
+		{
+			Word:              "hoge",
+			OriginalWordIndex: 5,
+		},
+	}
+	b := testutil.MakeSyn(t, append(expected, &syn.Word{
+		Word:              "fuga",
+		OriginalWordIndex: 3,
+	}))
+
+	s, err := syn.NewScanner(io.NopCloser(bytes.NewReader(b[:len(b)-2])))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var words []*syn.Word
+	for s.Scan() {
+		words = append(words, s.Word())
+	}
+	if err := s.Err(); !errors.Is(err, syn.ErrTruncated) {
+		t.Errorf("Err: expected %v, got %v", syn.ErrTruncated, err)
+	}
+	if diff := cmp.Diff(expected, words); diff != "" {
+		t.Errorf("words (-want, +got):\n%s", diff)
+	}
+}
+
+// TestNewScannerFromIfoPath tests that NewScannerFromIfoPath reads
+// uncompressed and gzip compressed .syn files.
+func TestNewScannerFromIfoPath(t *testing.T) {
+	t.Parallel()
+
+	expected := []*syn.Word{
+		{Word: "fuga", OriginalWordIndex: 1},
+		{Word: "hoge", OriginalWordIndex: 0},
+	}
+	b := testutil.MakeSyn(t, expected)
+
+	var gz bytes.Buffer
+	z := gzip.NewWriter(&gz)
+	if _, err := z.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for ext, data := range map[string][]byte{
+		".syn":    b,
+		".syn.gz": gz.Bytes(),
+	} {
+		t.Run(ext, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "dictionary"+ext), data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			s, err := syn.NewScannerFromIfoPath(filepath.Join(dir, "dictionary.ifo"))
+			if err != nil {
+				t.Fatalf("NewScannerFromIfoPath: %v", err)
+			}
+			defer s.Close()
+
+			var words []*syn.Word
+			for s.Scan() {
+				words = append(words, s.Word())
+			}
+			if err := s.Err(); err != nil {
+				t.Fatalf("Err: %v", err)
+			}
+			if diff := cmp.Diff(expected, words); diff != "" {
+				t.Errorf("words (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
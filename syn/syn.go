@@ -15,16 +15,17 @@
 package syn
 
 import (
-	"compress/gzip"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"golang.org/x/text/transform"
 
+	"github.com/ianlewis/go-stardict/internal/gzipfile"
 	"github.com/ianlewis/go-stardict/internal/index"
 )
 
@@ -51,6 +52,14 @@ type Options struct {
 	// Folder returns a [transform.Transformer] that performs folding (e.g.
 	// case folding, whitespace folding, etc.) on index entries.
 	Folder func() transform.Transformer
+
+	// Compare is the comparison function used to order the folded words
+	// returned by Words. Compare returns a negative number when a < b, a
+	// positive number when a > b and zero when a == b. Defaults to
+	// [strings.Compare]. See the collation package for locale specific
+	// comparison functions. The index itself is always sorted and searched
+	// in byte order.
+	Compare func(a, b string) int
 }
 
 // DefaultOptions is the default options for a Syn.
@@ -58,6 +67,7 @@ var DefaultOptions = &Options{
 	Folder: func() transform.Transformer {
 		return transform.Nop
 	},
+	Compare: strings.Compare,
 }
 
 // Syn is is the synonym index. It is largely a map of synonym words to related
@@ -68,6 +78,9 @@ type Syn struct {
 
 	// foldTransformer performs folding on text.
 	foldTransformer func() transform.Transformer
+
+	// compare orders the results of Words.
+	compare func(a, b string) int
 }
 
 // New returns a new Syn by reading the data from r.
@@ -78,10 +91,14 @@ func New(r io.ReadCloser, options *Options) (*Syn, error) {
 
 	syn := Syn{
 		foldTransformer: DefaultOptions.Folder,
+		compare:         DefaultOptions.Compare,
 	}
 	if options.Folder != nil {
 		syn.foldTransformer = options.Folder
 	}
+	if options.Compare != nil {
+		syn.compare = options.Compare
+	}
 
 	i := 0
 	s, err := NewScanner(r)
@@ -115,40 +132,45 @@ func New(r io.ReadCloser, options *Options) (*Syn, error) {
 
 // NewFromIfoPath returns a new in-memory index.
 func NewFromIfoPath(ifoPath string, options *Options) (*Syn, error) {
-	var r io.ReadCloser
 	f, err := Open(ifoPath)
 	if err != nil {
 		return nil, err
 	}
-	r = f
-
-	idxExt := strings.ToLower(filepath.Ext(f.Name()))
-	if idxExt == ".gz" || idxExt == ".dz" {
-		r, err = gzip.NewReader(r)
-		if err != nil {
-			return nil, fmt.Errorf("creating .ifo gzip reader: %w", err)
-		}
+	r, err := gzipfile.NewReader(f)
+	if err != nil {
+		//nolint:wrapcheck // error is already wrapped.
+		return nil, err
 	}
+	defer r.Close()
 
 	return New(r, options)
 }
 
+// synExts are the file extensions of .syn files in the order that Open looks
+// for them.
+var synExts = []string{
+	".syn",
+	".syn.gz",
+	".syn.GZ",
+	".syn.dz",
+	".syn.DZ",
+	".SYN",
+	".SYN.gz",
+	".SYN.GZ",
+	".SYN.dz",
+	".SYN.DZ",
+}
+
+// Extensions returns the file extensions of .syn files in the order that
+// Open looks for them.
+func Extensions() []string {
+	return slices.Clone(synExts)
+}
+
 // Open opens the .syn file given the path to the .ifo file.
 func Open(ifoPath string) (*os.File, error) {
 	baseName := strings.TrimSuffix(ifoPath, filepath.Ext(ifoPath))
 
-	synExts := []string{
-		".syn",
-		".syn.gz",
-		".syn.GZ",
-		".syn.dz",
-		".syn.DZ",
-		".SYN",
-		".SYN.gz",
-		".SYN.GZ",
-		".SYN.dz",
-		".SYN.DZ",
-	}
 	var f *os.File
 	var err error
 	for _, ext := range synExts {
@@ -169,6 +191,20 @@ func Open(ifoPath string) (*os.File, error) {
 	return f, nil
 }
 
+// Words returns the words in the synonym index ordered by their folded
+// value using Options.Compare.
+func (syn *Syn) Words() []*Word {
+	all := slices.Clone(syn.index.All())
+	slices.SortStableFunc(all, func(a, b *foldedWord) int {
+		return syn.compare(a.folded, b.folded)
+	})
+	words := make([]*Word, 0, len(all))
+	for _, w := range all {
+		words = append(words, w.word)
+	}
+	return words
+}
+
 // Search performs a query of the index and returns matching words.
 func (syn *Syn) Search(query string) ([]*Word, error) {
 	foldedQuery, _, err := transform.String(syn.foldTransformer(), query)
@@ -21,6 +21,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 
+	"github.com/ianlewis/go-stardict/collation"
 	"github.com/ianlewis/go-stardict/internal/testutil"
 	"github.com/ianlewis/go-stardict/syn"
 )
@@ -204,3 +205,30 @@ func TestSyn_Search(t *testing.T) {
 		})
 	}
 }
+
+// TestSyn_Words tests Syn.Words.
+func TestSyn_Words(t *testing.T) {
+	t.Parallel()
+
+	b := testutil.MakeSyn(t, []*syn.Word{
+		{Word: "zebra", OriginalWordIndex: 0},
+		{Word: "äpple", OriginalWordIndex: 1},
+		{Word: "apa", OriginalWordIndex: 2},
+	})
+
+	index, err := syn.New(io.NopCloser(bytes.NewReader(b)), &syn.Options{
+		Compare: collation.UTF8SwedishCI.Compare(),
+	})
+	if err != nil {
+		t.Fatalf("syn.New: %v", err)
+	}
+
+	expected := []*syn.Word{
+		{Word: "apa", OriginalWordIndex: 2},
+		{Word: "zebra", OriginalWordIndex: 0},
+		{Word: "äpple", OriginalWordIndex: 1},
+	}
+	if diff := cmp.Diff(expected, index.Words()); diff != "" {
+		t.Fatalf("Words (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syn
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+
+	"github.com/ianlewis/go-stardict/collation"
+	"github.com/ianlewis/go-stardict/internal/headword"
+)
+
+var (
+	// ErrInvalidWord indicates that a word cannot be written to a synonym
+	// file.
+	ErrInvalidWord = errors.New("invalid word")
+
+	// ErrWriterClosed indicates that the Writer has already been flushed.
+	ErrWriterClosed = errors.New("writer already flushed")
+
+	// ErrInvalidIndex indicates that a synonym's original word index is out
+	// of range.
+	ErrInvalidIndex = errors.New("invalid original word index")
+)
+
+// Writer writes .syn files. Synonyms are buffered and are written when Flush
+// is called sorted in the order used by StarDict (see
+// [collation.StardictCompare]).
+type Writer struct {
+	w       io.Writer
+	words   []*Word
+	flushed bool
+}
+
+// NewWriter returns a new Writer that writes the synonym file to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		w: w,
+	}
+}
+
+// Add adds a synonym to the synonym file. The word's OriginalWordIndex is
+// the index of the original word in the order the words were added to the
+// idx.Writer and is resolved to the position in the sorted .idx file when
+// Flush is called. Words must be non-empty, must not contain a null byte,
+// and must be no longer than idx.MaxWordLength bytes.
+func (w *Writer) Add(word *Word) error {
+	if w.flushed {
+		return ErrWriterClosed
+	}
+	if !headword.Valid(word.Word) {
+		return fmt.Errorf("%w: %q", ErrInvalidWord, word.Word)
+	}
+
+	w.words = append(w.words, &Word{
+		Word:              word.Word,
+		OriginalWordIndex: word.OriginalWordIndex,
+	})
+	return nil
+}
+
+// Flush resolves the original word indexes, sorts, and writes the synonym
+// file. order gives the position in the sorted .idx file of each original
+// word as returned by idx.Writer.Order. If order is nil, the original word
+// indexes are written unchanged. No words may be added after Flush is called.
+func (w *Writer) Flush(order []int) error {
+	if w.flushed {
+		return ErrWriterClosed
+	}
+	w.flushed = true
+
+	for _, word := range w.words {
+		if order == nil {
+			continue
+		}
+		if int(word.OriginalWordIndex) >= len(order) {
+			return fmt.Errorf("%w: %q: %d", ErrInvalidIndex, word.Word, word.OriginalWordIndex)
+		}
+		n := order[word.OriginalWordIndex]
+		if n < 0 || n > math.MaxUint32 {
+			return fmt.Errorf("%w: %q: %d", ErrInvalidIndex, word.Word, n)
+		}
+		word.OriginalWordIndex = uint32(n)
+	}
+
+	slices.SortStableFunc(w.words, func(a, b *Word) int {
+		return collation.StardictCompare(a.Word, b.Word)
+	})
+
+	bw := bufio.NewWriter(w.w)
+	var buf [4]byte
+	for _, word := range w.words {
+		binary.BigEndian.PutUint32(buf[:], word.OriginalWordIndex)
+
+		if _, err := bw.WriteString(word.Word); err != nil {
+			return fmt.Errorf("writing synonym file: %w", err)
+		}
+		if err := bw.WriteByte(0); err != nil {
+			return fmt.Errorf("writing synonym file: %w", err)
+		}
+		if _, err := bw.Write(buf[:]); err != nil {
+			return fmt.Errorf("writing synonym file: %w", err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("writing synonym file: %w", err)
+	}
+	return nil
+}
+
+// WordCount returns the number of synonyms. This is the value of the
+// synwordcount key in the .ifo file.
+func (w *Writer) WordCount() int64 {
+	return int64(len(w.words))
+}
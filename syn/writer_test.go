@@ -0,0 +1,136 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syn_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/syn"
+)
+
+// TestWriter tests Writer.
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		words []*syn.Word
+		order []int
+
+		expected []*syn.Word
+		err      error
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "sorted and resolved",
+			words: []*syn.Word{
+				{Word: "hoge", OriginalWordIndex: 0},
+				{Word: "Fuga", OriginalWordIndex: 1},
+				{Word: "fuga", OriginalWordIndex: 2},
+			},
+			order: []int{2, 0, 1},
+			expected: []*syn.Word{
+				{Word: "Fuga", OriginalWordIndex: 0},
+				{Word: "fuga", OriginalWordIndex: 1},
+				{Word: "hoge", OriginalWordIndex: 2},
+			},
+		},
+		{
+			name: "no order",
+			words: []*syn.Word{
+				{Word: "b", OriginalWordIndex: 5},
+				{Word: "a", OriginalWordIndex: 7},
+			},
+			expected: []*syn.Word{
+				{Word: "a", OriginalWordIndex: 7},
+				{Word: "b", OriginalWordIndex: 5},
+			},
+		},
+		{
+			name: "index out of range",
+			words: []*syn.Word{
+				{Word: "a", OriginalWordIndex: 3},
+			},
+			order: []int{0},
+			err:   syn.ErrInvalidIndex,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			w := syn.NewWriter(&buf)
+			for _, word := range test.words {
+				if err := w.Add(word); err != nil {
+					t.Fatalf("Add: %v", err)
+				}
+			}
+			err := w.Flush(test.order)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("Flush: expected %v, got %v", test.err, err)
+			}
+			if test.err != nil {
+				return
+			}
+
+			if got, want := w.WordCount(), int64(len(test.words)); got != want {
+				t.Errorf("WordCount: expected %d, got %d", want, got)
+			}
+
+			s, err := syn.NewScanner(io.NopCloser(&buf))
+			if err != nil {
+				t.Fatalf("NewScanner: %v", err)
+			}
+			var words []*syn.Word
+			for s.Scan() {
+				words = append(words, s.Word())
+			}
+			if err := s.Err(); err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			if diff := cmp.Diff(test.expected, words); diff != "" {
+				t.Errorf("words (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestWriter_Add_invalid tests Writer.Add with invalid words.
+func TestWriter_Add_invalid(t *testing.T) {
+	t.Parallel()
+
+	w := syn.NewWriter(&bytes.Buffer{})
+	for _, word := range []string{"", "foo\x00bar"} {
+		if err := w.Add(&syn.Word{Word: word}); !errors.Is(err, syn.ErrInvalidWord) {
+			t.Errorf("Add(%q): expected %v, got %v", word, syn.ErrInvalidWord, err)
+		}
+	}
+
+	if err := w.Flush(nil); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if err := w.Add(&syn.Word{Word: "foo"}); !errors.Is(err, syn.ErrWriterClosed) {
+		t.Errorf("Add: expected %v, got %v", syn.ErrWriterClosed, err)
+	}
+}
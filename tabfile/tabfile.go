@@ -0,0 +1,228 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tabfile implements reading the tab separated source format used by
+// StarDict's tabfile tool.
+//
+// Each line of a tabfile contains a word and its definition separated by a
+// tab character. The word may be followed by synonyms separated by '|'
+// characters. Line breaks, tabs, and backslashes in the definition are escaped
+// as "\n", "\t", and "\\" respectively. Empty lines are ignored.
+//
+//	word|synonym1|synonym2<TAB>first line\nsecond line
+package tabfile
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ianlewis/go-stardict"
+	"github.com/ianlewis/go-stardict/dict"
+)
+
+// maxLineSize is the maximum size of a line in bytes.
+const maxLineSize = 64 * 1024 * 1024
+
+// utf8BOM is the UTF-8 byte order mark.
+const utf8BOM = "\uFEFF"
+
+var (
+	// ErrSyntax indicates a syntax error in the tabfile.
+	ErrSyntax = errors.New("syntax error")
+
+	errDataType        = errors.New("data type must be a string type")
+	errMissingTab      = errors.New("missing tab")
+	errEmptyWord       = errors.New("empty word")
+	errEmptyDefinition = errors.New("empty definition")
+)
+
+// Entry is an entry in a tabfile.
+type Entry struct {
+	// Word is the entry's word.
+	Word string
+
+	// Synonyms are alternate words for the entry.
+	Synonyms []string
+
+	// Definition is the unescaped definition.
+	Definition string
+}
+
+// Scanner scans a tabfile from start to end.
+type Scanner struct {
+	s     *bufio.Scanner
+	line  int
+	entry *Entry
+	err   error
+}
+
+// NewScanner returns a new Scanner that reads the tabfile from r.
+func NewScanner(r io.Reader) *Scanner {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, maxLineSize)
+	return &Scanner{
+		s: s,
+	}
+}
+
+// Scan advances to the next entry. It returns false if the scan stops either
+// by reaching the end of the tabfile or an error.
+func (s *Scanner) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+
+	for s.s.Scan() {
+		s.line++
+		line := strings.TrimSuffix(s.s.Text(), "\r")
+		if s.line == 1 {
+			line = strings.TrimPrefix(line, utf8BOM)
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		e, err := parseLine(line)
+		if err != nil {
+			s.err = fmt.Errorf("%w: line %d: %w", ErrSyntax, s.line, err)
+			return false
+		}
+		s.entry = e
+		return true
+	}
+
+	if err := s.s.Err(); err != nil {
+		s.err = fmt.Errorf("reading tabfile: line %d: %w", s.line+1, err)
+	}
+	return false
+}
+
+// Entry returns the current entry.
+func (s *Scanner) Entry() *Entry {
+	return s.entry
+}
+
+// Line returns the line number of the current entry.
+func (s *Scanner) Line() int {
+	return s.line
+}
+
+// Err returns the first error encountered.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// parseLine parses a non-empty line of a tabfile.
+func parseLine(line string) (*Entry, error) {
+	words, definition, ok := strings.Cut(line, "\t")
+	if !ok {
+		return nil, errMissingTab
+	}
+
+	var e Entry
+	for _, w := range strings.Split(words, "|") {
+		w = strings.TrimSpace(w)
+		if w == "" {
+			continue
+		}
+		if e.Word == "" {
+			e.Word = w
+			continue
+		}
+		e.Synonyms = append(e.Synonyms, w)
+	}
+	if e.Word == "" {
+		return nil, errEmptyWord
+	}
+
+	e.Definition = Unescape(definition)
+	if e.Definition == "" {
+		return nil, fmt.Errorf("%w: %q", errEmptyDefinition, e.Word)
+	}
+
+	return &e, nil
+}
+
+// Unescape unescapes a tabfile definition. The escape sequences "\n", "\t",
+// and "\\" are replaced with a line feed, tab, and backslash respectively.
+// Other backslashes are left unchanged.
+func Unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			_ = b.WriteByte(s[i])
+			continue
+		}
+		switch s[i+1] {
+		case 'n':
+			_ = b.WriteByte('\n')
+		case 't':
+			_ = b.WriteByte('\t')
+		case '\\':
+			_ = b.WriteByte('\\')
+		default:
+			_ = b.WriteByte(s[i])
+			continue
+		}
+		i++
+	}
+	return b.String()
+}
+
+// ImportOptions are options for Import.
+type ImportOptions struct {
+	// DataType is the type of the definition data. It must be a string type
+	// such as dict.UTFTextType ('m') or dict.HTMLType ('h'). The default is
+	// dict.UTFTextType.
+	DataType dict.DataType
+}
+
+// Import reads the tabfile from r and adds its entries to the Builder. It
+// returns the number of entries added.
+func Import(r io.Reader, b *stardict.Builder, options *ImportOptions) (int, error) {
+	if options == nil {
+		options = &ImportOptions{}
+	}
+	t := options.DataType
+	if t == 0 {
+		t = dict.UTFTextType
+	}
+	if t < 'a' || t > 'z' {
+		return 0, fmt.Errorf("%w: %q", errDataType, t)
+	}
+
+	n := 0
+	s := NewScanner(r)
+	for s.Scan() {
+		e := s.Entry()
+		if err := b.AddEntry(e.Word, e.Synonyms, &dict.Data{
+			Type: t,
+			Data: []byte(e.Definition),
+		}); err != nil {
+			return n, fmt.Errorf("line %d: %w", s.Line(), err)
+		}
+		n++
+	}
+	if err := s.Err(); err != nil {
+		return n, err
+	}
+	return n, nil
+}
@@ -0,0 +1,180 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tabfile
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict"
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/ifo"
+)
+
+func TestScanner(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []*Entry
+		err      error
+	}{
+		{
+			name:     "empty",
+			input:    "",
+			expected: nil,
+		},
+		{
+			name:  "entries",
+			input: "hoge\thoge definition\n\nfuga\tfuga definition\n",
+			expected: []*Entry{
+				{Word: "hoge", Definition: "hoge definition"},
+				{Word: "fuga", Definition: "fuga definition"},
+			},
+		},
+		{
+			name:  "synonyms",
+			input: "hoge|fuga| piyo |\tdefinition",
+			expected: []*Entry{
+				{Word: "hoge", Synonyms: []string{"fuga", "piyo"}, Definition: "definition"},
+			},
+		},
+		{
+			name:  "escapes",
+			input: `hoge` + "\t" + `line1\nline2\tcol\\n\x`,
+			expected: []*Entry{
+				{Word: "hoge", Definition: "line1\nline2\tcol\\n\\x"},
+			},
+		},
+		{
+			name:  "bom and crlf",
+			input: "\uFEFFhoge\tdefinition\r\nfuga\tdefinition\r\n",
+			expected: []*Entry{
+				{Word: "hoge", Definition: "definition"},
+				{Word: "fuga", Definition: "definition"},
+			},
+		},
+		{
+			name:  "missing tab",
+			input: "hoge\tdefinition\nfuga",
+			expected: []*Entry{
+				{Word: "hoge", Definition: "definition"},
+			},
+			err: ErrSyntax,
+		},
+		{
+			name:  "empty word",
+			input: "|\tdefinition",
+			err:   ErrSyntax,
+		},
+		{
+			name:  "empty definition",
+			input: "hoge\t",
+			err:   ErrSyntax,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var entries []*Entry
+			s := NewScanner(strings.NewReader(test.input))
+			for s.Scan() {
+				entries = append(entries, s.Entry())
+			}
+			if diff := cmp.Diff(test.expected, entries); diff != "" {
+				t.Errorf("entries (-want, +got):\n%s", diff)
+			}
+			if err := s.Err(); !errors.Is(err, test.err) {
+				t.Errorf("Err: expected %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	b, err := stardict.NewBuilder(&ifo.Info{
+		BookName:         "hoge",
+		SameTypeSequence: "h",
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+
+	input := "hoge|piyo\t<b>hoge</b>\\n definition\nfuga\tfuga definition\n"
+	n, err := Import(strings.NewReader(input), b, &ImportOptions{
+		DataType: dict.HTMLType,
+	})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if got, want := n, 2; got != want {
+		t.Errorf("Import: expected %d entries, got %d", want, got)
+	}
+
+	dir := t.TempDir()
+	if err := b.Finish(dir, "hoge"); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+
+	s, err := stardict.Open(filepath.Join(dir, "hoge.ifo"), nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+
+	entries, err := s.Search("piyo")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Search: expected 1 entry, got %d", len(entries))
+	}
+	if got, want := entries[0].Title(), "hoge"; got != want {
+		t.Errorf("Title: expected %q, got %q", want, got)
+	}
+	expected := stardict.DataList{
+		{Type: dict.HTMLType, Data: []byte("<b>hoge</b>\n definition")},
+	}
+	if diff := cmp.Diff(expected, entries[0].Data()); diff != "" {
+		t.Errorf("Data (-want, +got):\n%s", diff)
+	}
+}
+
+func TestImport_invalidDataType(t *testing.T) {
+	t.Parallel()
+
+	b, err := stardict.NewBuilder(&ifo.Info{
+		BookName: "hoge",
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewBuilder: %v", err)
+	}
+	defer b.Close()
+
+	if _, err := Import(strings.NewReader("hoge\tfuga"), b, &ImportOptions{
+		DataType: dict.WavType,
+	}); err == nil {
+		t.Error("Import: expected error")
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tdx implements reading .tdx files.
+//
+// The .tdx file is used by "tree dictionaries" instead of the .idx file. It
+// contains a tree of entries where each entry references article content in
+// the .dict file.
+//
+// Each .tdx file entry (word) comes in four parts:
+//  1. The title: a utf-8 string terminated by a null terminator ('\0').
+//  2. The offset: a 32 or 64 bit integer offset of the word in the .dict
+//     file in network byte order.
+//  3. The size: a 32 bit integer size of the word in the .dict file in
+//     network byte order.
+//  4. The child count: a 32 bit integer count of the entry's children in
+//     network byte order.
+//
+// Entries are stored in depth-first order. An entry's children immediately
+// follow the entry itself.
+package tdx
@@ -0,0 +1,127 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tdx_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/internal/testutil"
+	"github.com/ianlewis/go-stardict/tdx"
+)
+
+// TestScanner tests the Scanner type.
+func TestScanner(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		data          []byte
+		expected      []*tdx.Word
+		idxoffsetbits int
+		err           bool
+	}{
+		{
+			name: "multi 64 bit",
+			expected: []*tdx.Word{
+				{
+					Word:       "hoge",
+					Offset:     123,
+					Size:       456,
+					ChildCount: 1,
+				},
+				{
+					Word:   "fuga pico",
+					Offset: 12,
+					Size:   45,
+				},
+			},
+			idxoffsetbits: 64,
+		},
+		{
+			name: "multi 32 bit",
+			expected: []*tdx.Word{
+				{
+					Word:       "hoge",
+					Offset:     123,
+					Size:       456,
+					ChildCount: 1,
+				},
+				{
+					Word:   "fuga pico",
+					Offset: 12,
+					Size:   45,
+				},
+			},
+			idxoffsetbits: 32,
+		},
+		{
+			name:          "truncated",
+			data:          []byte{'h', 'o', 'g', 'e', 0, 0, 0},
+			idxoffsetbits: 32,
+			err:           true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := test.data
+			if b == nil {
+				b = testutil.MakeTdx(test.expected, test.idxoffsetbits)
+			}
+
+			var words []*tdx.Word
+			s, err := tdx.NewScanner(io.NopCloser(bytes.NewReader(b)), &tdx.ScannerOptions{
+				OffsetBits: test.idxoffsetbits,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for s.Scan() {
+				words = append(words, s.Word())
+			}
+			err = s.Err()
+			if test.err {
+				if err == nil {
+					t.Fatal("Scan: expected failure")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.expected, words); diff != "" {
+				t.Fatalf("Scan (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestNewScanner_invalidOffsetBits tests that NewScanner rejects invalid
+// offset bits.
+func TestNewScanner_invalidOffsetBits(t *testing.T) {
+	t.Parallel()
+
+	_, err := tdx.NewScanner(io.NopCloser(bytes.NewReader(nil)), &tdx.ScannerOptions{
+		OffsetBits: 16,
+	})
+	if err == nil {
+		t.Fatal("NewScanner: expected failure")
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tdx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ianlewis/go-stardict/internal/gzipfile"
+)
+
+var (
+	errTruncated    = errors.New("truncated tdx entry")
+	errMissingChild = errors.New("missing child entries")
+)
+
+// Word is a .tdx file entry.
+type Word struct {
+	// Word is the word as it appears in the tree index.
+	Word string
+
+	// Offset is the offset in the .dict file that the corresponding entry appears.
+	Offset uint64
+
+	// Size is the total size of the corresponding .dict file entry.
+	Size uint32
+
+	// ChildCount is the number of direct children of the entry.
+	ChildCount uint32
+}
+
+// Node is a node in the tree index.
+type Node struct {
+	word     *Word
+	parent   *Node
+	children []*Node
+}
+
+// Word returns the node's tree index entry.
+func (n *Node) Word() *Word {
+	return n.word
+}
+
+// Parent returns the node's parent. Parent returns nil for top-level nodes.
+func (n *Node) Parent() *Node {
+	return n.parent
+}
+
+// Children returns the node's direct children.
+func (n *Node) Children() []*Node {
+	return n.children
+}
+
+// Tree is an in-memory tree index.
+type Tree struct {
+	roots []*Node
+}
+
+// New returns a new in-memory tree index by reading the data from r. The
+// caller is responsible for closing r.
+func New(r io.ReadCloser, options *ScannerOptions) (*Tree, error) {
+	s, err := NewScanner(r, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating tree index scanner: %w", err)
+	}
+
+	t := &Tree{}
+
+	// pending holds the number of children that still need to be read for
+	// each node on the path from the root to the current node.
+	var path []*Node
+	var pending []uint32
+	for s.Scan() {
+		n := &Node{
+			word: s.Word(),
+		}
+
+		if len(path) == 0 {
+			t.roots = append(t.roots, n)
+		} else {
+			n.parent = path[len(path)-1]
+			n.parent.children = append(n.parent.children, n)
+			pending[len(pending)-1]--
+		}
+
+		path = append(path, n)
+		pending = append(pending, n.word.ChildCount)
+
+		// Pop nodes whose children have all been read.
+		for len(pending) > 0 && pending[len(pending)-1] == 0 {
+			path = path[:len(path)-1]
+			pending = pending[:len(pending)-1]
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("scanning tree index: %w", err)
+	}
+	if len(path) > 0 {
+		return nil, fmt.Errorf("%w: %q", errMissingChild, path[len(path)-1].word.Word)
+	}
+
+	return t, nil
+}
+
+// NewFromIfoPath returns a new in-memory tree index for the .tdx file that
+// accompanies the given .ifo file.
+func NewFromIfoPath(ifoPath string, options *ScannerOptions) (*Tree, error) {
+	r, err := openReader(ifoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return New(r, options)
+}
+
+// Open opens the .tdx file given the path to the .ifo file.
+func Open(ifoPath string) (*os.File, error) {
+	baseName := strings.TrimSuffix(ifoPath, filepath.Ext(ifoPath))
+
+	tdxExts := []string{
+		".tdx",
+		".tdx.gz",
+		".tdx.GZ",
+		".tdx.dz",
+		".tdx.DZ",
+		".TDX",
+		".TDX.gz",
+		".TDX.GZ",
+		".TDX.dz",
+		".TDX.DZ",
+	}
+	var f *os.File
+	var err error
+	for _, ext := range tdxExts {
+		f, err = os.Open(baseName + ext)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("opening .tdx file: %w", err)
+		}
+	}
+
+	// Catch the case when no .tdx file was found.
+	if err != nil {
+		return nil, fmt.Errorf("opening .tdx file: %w", err)
+	}
+
+	return f, nil
+}
+
+// openReader opens the .tdx file and decompresses it if necessary.
+func openReader(ifoPath string) (io.ReadCloser, error) {
+	f, err := Open(ifoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint:wrapcheck // error is already wrapped.
+	return gzipfile.NewReader(f)
+}
+
+// Roots returns the top-level nodes of the tree.
+func (t *Tree) Roots() []*Node {
+	return t.roots
+}
+
+// Walk walks the tree in depth-first order calling fn for each node. If fn
+// returns an error, walking stops and the error is returned.
+func (t *Tree) Walk(fn func(*Node) error) error {
+	var walk func([]*Node) error
+	walk = func(nodes []*Node) error {
+		for _, n := range nodes {
+			if err := fn(n); err != nil {
+				return err
+			}
+			if err := walk(n.children); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(t.roots)
+}
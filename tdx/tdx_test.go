@@ -0,0 +1,201 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tdx_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/internal/testutil"
+	"github.com/ianlewis/go-stardict/tdx"
+)
+
+// treeWords returns the words of the tree in depth-first order along with
+// the word of each node's parent.
+func treeWords(t *testing.T, tree *tdx.Tree) ([]string, []string) {
+	t.Helper()
+
+	var words, parents []string
+	if err := tree.Walk(func(n *tdx.Node) error {
+		words = append(words, n.Word().Word)
+		parent := ""
+		if n.Parent() != nil {
+			parent = n.Parent().Word().Word
+		}
+		parents = append(parents, parent)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return words, parents
+}
+
+// TestNew tests New.
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		entries []*tdx.Word
+
+		roots   int
+		words   []string
+		parents []string
+		err     bool
+	}{
+		{
+			name:    "empty",
+			entries: []*tdx.Word{},
+		},
+		{
+			name: "single root",
+			entries: []*tdx.Word{
+				{Word: "root", ChildCount: 2},
+				{Word: "foo", ChildCount: 1},
+				{Word: "foo child"},
+				{Word: "bar"},
+			},
+			roots:   1,
+			words:   []string{"root", "foo", "foo child", "bar"},
+			parents: []string{"", "root", "foo", "root"},
+		},
+		{
+			name: "multiple roots",
+			entries: []*tdx.Word{
+				{Word: "foo", ChildCount: 1},
+				{Word: "foo child"},
+				{Word: "bar"},
+			},
+			roots:   2,
+			words:   []string{"foo", "foo child", "bar"},
+			parents: []string{"", "foo", ""},
+		},
+		{
+			name: "missing children",
+			entries: []*tdx.Word{
+				{Word: "root", ChildCount: 2},
+				{Word: "foo"},
+			},
+			err: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := testutil.MakeTdx(test.entries, 32)
+			tree, err := tdx.New(io.NopCloser(bytes.NewReader(b)), nil)
+			if test.err {
+				if err == nil {
+					t.Fatal("New: expected failure")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+
+			if want, got := test.roots, len(tree.Roots()); want != got {
+				t.Errorf("Roots: want: %d, got: %d", want, got)
+			}
+
+			words, parents := treeWords(t, tree)
+			if diff := cmp.Diff(test.words, words); diff != "" {
+				t.Errorf("words (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.parents, parents); diff != "" {
+				t.Errorf("parents (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestNewFromIfoPath tests NewFromIfoPath.
+func TestNewFromIfoPath(t *testing.T) {
+	t.Parallel()
+
+	words := []*tdx.Word{
+		{Word: "root", Offset: 0, Size: 4, ChildCount: 1},
+		{Word: "child", Offset: 4, Size: 5},
+	}
+
+	tests := []struct {
+		name string
+		ext  string
+		gzip bool
+	}{
+		{
+			name: "uncompressed",
+			ext:  ".tdx",
+		},
+		{
+			name: "gzip",
+			ext:  ".tdx.gz",
+			gzip: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+
+			var b bytes.Buffer
+			data := testutil.MakeTdx(words, 32)
+			if test.gzip {
+				z := gzip.NewWriter(&b)
+				if _, err := z.Write(data); err != nil {
+					t.Fatal(err)
+				}
+				if err := z.Close(); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				b.Write(data)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "dictionary"+test.ext), b.Bytes(), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			tree, err := tdx.NewFromIfoPath(filepath.Join(dir, "dictionary.ifo"), nil)
+			if err != nil {
+				t.Fatalf("NewFromIfoPath: %v", err)
+			}
+
+			roots := tree.Roots()
+			if want, got := 1, len(roots); want != got {
+				t.Fatalf("Roots: want: %d, got: %d", want, got)
+			}
+			if diff := cmp.Diff(words[0], roots[0].Word()); diff != "" {
+				t.Errorf("root (-want, +got):\n%s", diff)
+			}
+			children := roots[0].Children()
+			if want, got := 1, len(children); want != got {
+				t.Fatalf("Children: want: %d, got: %d", want, got)
+			}
+			if diff := cmp.Diff(words[1], children[0].Word()); diff != "" {
+				t.Errorf("child (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stardict
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ianlewis/go-stardict/collation"
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/idx"
+	"github.com/ianlewis/go-stardict/internal/gzipfile"
+	"github.com/ianlewis/go-stardict/syn"
+)
+
+// maxRepeatedFindings is the maximum number of findings reported for each
+// kind of problem found in individual entries.
+const maxRepeatedFindings = 10
+
+// Severity is the severity of a validation finding.
+type Severity int
+
+const (
+	// SeverityWarning indicates a problem that most readers tolerate, such
+	// as entries that are out of order or .ifo counts and sizes that don't
+	// match the other files.
+	SeverityWarning Severity = iota + 1
+
+	// SeverityError indicates a problem that causes entries to be missing
+	// or unreadable.
+	SeverityError
+)
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// Finding is a problem found when validating a dictionary.
+type Finding struct {
+	// Severity is the severity of the problem.
+	Severity Severity
+
+	// File is the extension of the dictionary file with the problem, such as
+	// ".idx" or ".syn".
+	File string
+
+	// Message describes the problem.
+	Message string
+}
+
+// String returns a string representation of the finding.
+func (f *Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.Severity, f.File, f.Message)
+}
+
+// Report is the result of validating a dictionary.
+type Report struct {
+	// Findings are the problems found in file order.
+	Findings []*Finding
+
+	// counts is the number of findings of each kind for repeated problems.
+	counts map[string]int
+}
+
+// Valid returns true if the report has no errors. Warnings are allowed.
+func (r *Report) Valid() bool {
+	for _, f := range r.Findings {
+		if f.Severity >= SeverityError {
+			return false
+		}
+	}
+	return true
+}
+
+// add adds a finding to the report.
+func (r *Report) add(severity Severity, file, format string, args ...any) {
+	r.Findings = append(r.Findings, &Finding{
+		Severity: severity,
+		File:     file,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// addRepeated adds a finding for a problem that may occur in many entries.
+// Only the first maxRepeatedFindings findings of each kind are added.
+func (r *Report) addRepeated(kind string, severity Severity, file, format string, args ...any) {
+	if r.counts == nil {
+		r.counts = map[string]int{}
+	}
+	r.counts[kind]++
+	if r.counts[kind] <= maxRepeatedFindings {
+		r.add(severity, file, format, args...)
+	}
+}
+
+// summarize adds a finding with the number of omitted findings of the kind.
+func (r *Report) summarize(kind string, severity Severity, file string) {
+	if n := r.counts[kind] - maxRepeatedFindings; n > 0 {
+		r.add(severity, file, "%d more %s", n, kind)
+	}
+}
+
+// Validate checks the dictionary's files against each other and returns a
+// report of the problems found. It checks that the .ifo wordcount,
+// idxfilesize, and synwordcount match the .idx and .syn files, that .idx
+// and .syn entries are sorted in StarDict order, that each entry's data is
+// inside the bounds of the .dict file and can be decoded, and that .syn
+// entries refer to existing .idx entries. Mismatched .ifo values and unsorted
+// entries are reported as warnings. Entries that can't be read and files that
+// can't be read are reported as errors.
+func (s *Stardict) Validate() *Report {
+	r := &Report{}
+
+	if s.isTree {
+		r.add(SeverityWarning, ".tdx", "tree dictionaries are not validated")
+		return r
+	}
+
+	var d *dict.Dict
+	dictSize, err := s.dictSize()
+	if err == nil {
+		d, err = s.Dict()
+	}
+	if err != nil {
+		r.add(SeverityError, ".dict", "%v", err)
+	}
+
+	wordcount := s.validateIdx(r, d, dictSize)
+	s.validateSyn(r, wordcount)
+
+	return r
+}
+
+// validateIdx validates the .idx file and the data of each entry in d if
+// not nil. It returns the number of entries in the .idx file.
+func (s *Stardict) validateIdx(r *Report, d *dict.Dict, dictSize int64) int64 {
+	sc, err := s.IndexScanner()
+	if err != nil {
+		r.add(SeverityError, ".idx", "%v", err)
+		return 0
+	}
+	defer sc.Close()
+
+	const (
+		unsorted   = "entries out of order"
+		outOfRange = "entries outside the .dict file"
+		undecoded  = "entries that could not be decoded"
+	)
+
+	dictLen := uint64(dictSize) //nolint:gosec // dictSize is not negative.
+	var count int64
+	var prev string
+	for sc.Scan() {
+		w := sc.Word()
+		if count > 0 && collation.StardictCompare(prev, w.Word) > 0 {
+			r.addRepeated(unsorted, SeverityWarning, ".idx",
+				"entry %d %q is sorted before the previous entry %q", count, w.Word, prev)
+		}
+
+		switch {
+		case d == nil:
+		case w.Offset > dictLen || uint64(w.Size) > dictLen-w.Offset:
+			r.addRepeated(outOfRange, SeverityError, ".idx",
+				"entry %d %q data at offset %d with size %d is outside the .dict file of size %d",
+				count, w.Word, w.Offset, w.Size, dictSize)
+		default:
+			if _, err := d.Word(w); err != nil {
+				r.addRepeated(undecoded, SeverityError, ".dict", "entry %d %q: %v", count, w.Word, err)
+			}
+		}
+
+		prev = w.Word
+		count++
+	}
+	if err := sc.Err(); err != nil {
+		r.add(SeverityError, ".idx", "reading .idx file: %v", err)
+	}
+	r.summarize(unsorted, SeverityWarning, ".idx")
+	r.summarize(outOfRange, SeverityError, ".idx")
+	r.summarize(undecoded, SeverityError, ".dict")
+
+	if count != s.wordcount {
+		r.add(SeverityWarning, ".ifo", "wordcount is %d but the .idx file has %d entries", s.wordcount, count)
+	}
+	size, err := s.idxSize()
+	switch {
+	case err != nil:
+		r.add(SeverityError, ".idx", "%v", err)
+	case size != s.idxfilesize:
+		r.add(SeverityWarning, ".ifo", "idxfilesize is %d but the .idx file has %d bytes", s.idxfilesize, size)
+	}
+
+	return count
+}
+
+// validateSyn validates the .syn file. wordcount is the number of entries in
+// the .idx file.
+func (s *Stardict) validateSyn(r *Report, wordcount int64) {
+	sc, err := syn.NewScannerFromIfoPath(s.ifoPath)
+	if errors.Is(err, os.ErrNotExist) {
+		if s.synwordcount != 0 {
+			r.add(SeverityWarning, ".ifo", "synwordcount is %d but there is no .syn file", s.synwordcount)
+		}
+		return
+	}
+	if err != nil {
+		r.add(SeverityError, ".syn", "%v", err)
+		return
+	}
+	defer sc.Close()
+
+	const (
+		unsorted   = "synonyms out of order"
+		outOfRange = "synonyms with an invalid index"
+	)
+
+	var count int64
+	var prev string
+	for sc.Scan() {
+		w := sc.Word()
+		if count > 0 && collation.StardictCompare(prev, w.Word) > 0 {
+			r.addRepeated(unsorted, SeverityWarning, ".syn",
+				"synonym %d %q is sorted before the previous synonym %q", count, w.Word, prev)
+		}
+		if int64(w.OriginalWordIndex) >= wordcount {
+			r.addRepeated(outOfRange, SeverityError, ".syn",
+				"synonym %d %q refers to entry %d but the .idx file has %d entries",
+				count, w.Word, w.OriginalWordIndex, wordcount)
+		}
+		prev = w.Word
+		count++
+	}
+	if err := sc.Err(); err != nil {
+		r.add(SeverityError, ".syn", "reading .syn file: %v", err)
+	}
+	r.summarize(unsorted, SeverityWarning, ".syn")
+	r.summarize(outOfRange, SeverityError, ".syn")
+
+	if count != s.synwordcount {
+		r.add(SeverityWarning, ".ifo", "synwordcount is %d but the .syn file has %d entries", s.synwordcount, count)
+	}
+}
+
+// dictSize returns the uncompressed size of the .dict file.
+func (s *Stardict) dictSize() (int64, error) {
+	f, err := dict.Open(s.ifoPath)
+	if err != nil {
+		//nolint:wrapcheck // error is already wrapped.
+		return 0, err
+	}
+	n, err := uncompressedSize(f)
+	if err != nil {
+		return 0, fmt.Errorf("reading .dict file: %w", err)
+	}
+	return n, nil
+}
+
+// idxSize returns the uncompressed size of the .idx file.
+func (s *Stardict) idxSize() (int64, error) {
+	f, err := idx.Open(s.ifoPath)
+	if err != nil {
+		//nolint:wrapcheck // error is already wrapped.
+		return 0, err
+	}
+	n, err := uncompressedSize(f)
+	if err != nil {
+		return 0, fmt.Errorf("reading .idx file: %w", err)
+	}
+	return n, nil
+}
+
+// uncompressedSize returns the size of the data in f after decompression and
+// closes f.
+func uncompressedSize(f *os.File) (int64, error) {
+	if !gzipfile.IsCompressed(f.Name()) {
+		defer f.Close()
+		fi, err := f.Stat()
+		if err != nil {
+			//nolint:wrapcheck // error is wrapped by the caller.
+			return 0, err
+		}
+		return fi.Size(), nil
+	}
+
+	// The size in the gzip trailer is truncated to 32 bits so the data is
+	// decompressed to get the size.
+	r, err := gzipfile.NewReader(f)
+	if err != nil {
+		//nolint:wrapcheck // error is wrapped by the caller.
+		return 0, err
+	}
+	defer r.Close()
+	//nolint:wrapcheck // error is wrapped by the caller.
+	return io.Copy(io.Discard, r)
+}
@@ -0,0 +1,239 @@
+// Copyright 2026 Ian Lewis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stardict
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ianlewis/go-stardict/dict"
+	"github.com/ianlewis/go-stardict/idx"
+	"github.com/ianlewis/go-stardict/ifo"
+	"github.com/ianlewis/go-stardict/syn"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	validIfo := `StarDict's dict ifo file
+version=2.4.2
+bookname=hoge
+wordcount=2
+synwordcount=1
+idxfilesize=26`
+	words := []*dict.Word{
+		{Data: []*dict.Data{{Type: dict.UTFTextType, Data: []byte("fuga")}}},
+		{Data: []*dict.Data{{Type: dict.UTFTextType, Data: []byte("hoge")}}},
+	}
+	validIdx := []*idx.Word{
+		{Word: "fuga", Offset: 0, Size: 6},
+		{Word: "hoge", Offset: 6, Size: 6},
+	}
+	validSyn := []*syn.Word{
+		{Word: "piyo", OriginalWordIndex: 1},
+	}
+
+	tests := []struct {
+		name     string
+		dict     *testDict
+		truncate int64
+		expected []*Finding
+	}{
+		{
+			name: "valid",
+			dict: &testDict{
+				ifo:  validIfo,
+				dict: words,
+				idx:  validIdx,
+				syn:  validSyn,
+			},
+		},
+		{
+			name: "ifo counts",
+			dict: &testDict{
+				ifo: `StarDict's dict ifo file
+version=2.4.2
+bookname=hoge
+wordcount=3
+synwordcount=2
+idxfilesize=10`,
+				dict: words,
+				idx:  validIdx,
+				syn:  validSyn,
+			},
+			expected: []*Finding{
+				{SeverityWarning, ".ifo", "wordcount is 3 but the .idx file has 2 entries"},
+				{SeverityWarning, ".ifo", "idxfilesize is 10 but the .idx file has 26 bytes"},
+				{SeverityWarning, ".ifo", "synwordcount is 2 but the .syn file has 1 entries"},
+			},
+		},
+		{
+			name: "missing syn",
+			dict: &testDict{
+				ifo:  validIfo,
+				dict: words,
+				idx:  validIdx,
+			},
+			expected: []*Finding{
+				{SeverityWarning, ".ifo", "synwordcount is 1 but there is no .syn file"},
+			},
+		},
+		{
+			name: "unsorted index",
+			dict: &testDict{
+				ifo:  validIfo,
+				dict: words,
+				idx: []*idx.Word{
+					{Word: "hoge", Offset: 6, Size: 6},
+					{Word: "fuga", Offset: 0, Size: 6},
+				},
+				syn: validSyn,
+			},
+			expected: []*Finding{
+				{SeverityWarning, ".idx", `entry 1 "fuga" is sorted before the previous entry "hoge"`},
+			},
+		},
+		{
+			name: "truncated index",
+			dict: &testDict{
+				ifo: `StarDict's dict ifo file
+version=2.4.2
+bookname=hoge
+wordcount=1
+synwordcount=1
+idxfilesize=24`,
+				dict: words,
+				idx:  validIdx,
+				syn: []*syn.Word{
+					{Word: "piyo", OriginalWordIndex: 0},
+				},
+			},
+			// idxfilesize matches the size of the truncated file so only
+			// the truncated record is reported.
+			truncate: 2,
+			expected: []*Finding{
+				{SeverityError, ".idx", "reading .idx file: truncated index entry: 11 trailing bytes"},
+			},
+		},
+		{
+			name: "data out of bounds",
+			dict: &testDict{
+				ifo:  validIfo,
+				dict: words,
+				idx: []*idx.Word{
+					{Word: "fuga", Offset: 0, Size: 6},
+					{Word: "hoge", Offset: 6, Size: 7},
+				},
+				syn: validSyn,
+			},
+			expected: []*Finding{
+				{SeverityError, ".idx", `entry 1 "hoge" data at offset 6 with size 7 is outside the .dict file of size 12`},
+			},
+		},
+		{
+			name: "synonym index out of range",
+			dict: &testDict{
+				ifo:  validIfo,
+				dict: words,
+				idx:  validIdx,
+				syn: []*syn.Word{
+					{Word: "piyo", OriginalWordIndex: 2},
+				},
+			},
+			expected: []*Finding{
+				{SeverityError, ".syn", `synonym 0 "piyo" refers to entry 2 but the .idx file has 2 entries`},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := writeDict(t, test.dict)
+			defer os.RemoveAll(dir)
+
+			if test.truncate > 0 {
+				idxPath := filepath.Join(dir, "dictionary.idx")
+				fi, err := os.Stat(idxPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Truncate(idxPath, fi.Size()-test.truncate); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			s, err := Open(filepath.Join(dir, "dictionary.ifo"), nil)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			defer s.Close()
+
+			r := s.Validate()
+			if diff := cmp.Diff(test.expected, r.Findings); diff != "" {
+				t.Errorf("Validate (-want, +got):\n%s", diff)
+			}
+			valid := !slices.ContainsFunc(test.expected, func(f *Finding) bool {
+				return f.Severity == SeverityError
+			})
+			if got, want := r.Valid(), valid; got != want {
+				t.Errorf("Valid: expected %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestValidate_builder(t *testing.T) {
+	t.Parallel()
+
+	s := buildDict(t, &ifo.Info{BookName: "hoge"}, []mergeTestEntry{
+		{word: "hoge", synonyms: []string{"piyo"}, data: "hoge data"},
+		{word: "fuga", data: "fuga data"},
+	})
+
+	r := s.Validate()
+	if diff := cmp.Diff([]*Finding(nil), r.Findings); diff != "" {
+		t.Errorf("Validate (-want, +got):\n%s", diff)
+	}
+}
+
+func TestReport_Valid(t *testing.T) {
+	t.Parallel()
+
+	r := &Report{}
+	r.add(SeverityWarning, ".ifo", "hoge")
+	if !r.Valid() {
+		t.Errorf("Valid: expected true with only warnings")
+	}
+
+	for range maxRepeatedFindings + 2 {
+		r.addRepeated("fugas", SeverityError, ".idx", "fuga")
+	}
+	r.summarize("fugas", SeverityError, ".idx")
+	if r.Valid() {
+		t.Errorf("Valid: expected false with errors")
+	}
+	if got, want := len(r.Findings), maxRepeatedFindings+2; got != want {
+		t.Errorf("Findings: expected %d findings, got %d", want, got)
+	}
+	if got, want := r.Findings[len(r.Findings)-1].String(), "error: .idx: 2 more fugas"; got != want {
+		t.Errorf("String: expected %q, got %q", want, got)
+	}
+}